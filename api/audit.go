@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Attaches the client IP and request ID to the request context so that
+// store transactions can record them in the audit log
+func auditMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		metadata := db.AuditMetadata{
+			ClientIP:  ctx.ClientIP(),
+			RequestID: requestID(ctx),
+		}
+		ctx.Request = ctx.Request.WithContext(db.WithAuditMetadata(ctx.Request.Context(), metadata))
+		ctx.Next()
+	}
+}
+
+type listAuditEventsRequest struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listAuditEvents(ctx *gin.Context) {
+	var req listAuditEventsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListAuditEventsParams{
+		Actor:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
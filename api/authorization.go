@@ -0,0 +1,59 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	householdMemberKey = "household_member"
+)
+
+type householdURI struct {
+	HouseholdID int32 `uri:"household_id" binding:"required,min=1"`
+}
+
+// Makes sure the authenticated user is a member of the household in the path
+// and holds at least the required role. Must run after authMiddleware.
+func householdAuthorization(store db.Store, requiredRole string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var uri householdURI
+		if err := ctx.ShouldBindUri(&uri); err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		arg := db.GetHouseholdMemberParams{
+			HouseholdID: uri.HouseholdID,
+			Username:    authPayload.Username,
+		}
+
+		member, err := store.GetHouseholdMember(ctx, arg)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				err := errors.New("household doesn't belong to the authenticated user")
+				abortWithError(ctx, http.StatusForbidden, err)
+				return
+			}
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		if !util.HasRole(member.Role, requiredRole) {
+			err := fmt.Errorf("%s role is not allowed to perform this action", member.Role)
+			abortWithError(ctx, http.StatusForbidden, err)
+			return
+		}
+
+		ctx.Set(householdMemberKey, member)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+)
+
+type entryOperationRequest struct {
+	Op       string `json:"op" binding:"required,oneof=create update delete"`
+	ID       int32  `json:"id" binding:"required_unless=Op create,omitempty,gt=0"`
+	Name     string `json:"name" binding:"required_unless=Op delete,omitempty,alphanum"`
+	DueDate  string `json:"due_date" binding:"required_unless=Op delete"`
+	Amount   int64  `json:"amount" binding:"required_unless=Op delete,omitempty,gt=0"`
+	Category string `json:"category" binding:"max=15"`
+	Version  int32  `json:"version" binding:"required_unless=Op create,omitempty,gt=0"`
+}
+
+type batchEntriesRequest struct {
+	Operations []entryOperationRequest `json:"operations" binding:"required,min=1,max=100,dive"`
+	BestEffort bool                    `json:"best_effort"`
+}
+
+type entryOperationResponse struct {
+	Op     string    `json:"op"`
+	Status int       `json:"status"`
+	Entry  *db.Entry `json:"entry,omitempty"`
+	Error  *problem  `json:"error,omitempty"`
+}
+
+type batchEntriesResponse struct {
+	Results []entryOperationResponse `json:"results"`
+	User    userResponse             `json:"user"`
+}
+
+// Problem of an atomic batch, index is the operation that failed
+type batchOperationProblem struct {
+	problem
+	Index int `json:"index"`
+}
+
+func (server *Server) batchEntries(ctx *gin.Context) {
+	var req batchEntriesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.BatchEntriesTxParams{
+		Username:   authPayload.Username,
+		Operations: make([]db.EntryOperation, len(req.Operations)),
+		BestEffort: req.BestEffort,
+	}
+
+	for i, op := range req.Operations {
+		arg.Operations[i] = db.EntryOperation{
+			Op:       op.Op,
+			ID:       op.ID,
+			Name:     op.Name,
+			Amount:   op.Amount,
+			Category: op.Category,
+			Version:  op.Version,
+		}
+
+		if op.Op == db.BatchDelete {
+			continue
+		}
+
+		dueDate, err := time.Parse(YYYYMMDD, op.DueDate)
+		if err != nil {
+			p := newProblem(ctx, http.StatusBadRequest, err)
+			writeProblem(ctx, p.Status, batchOperationProblem{problem: p, Index: i})
+			return
+		}
+		arg.Operations[i].DueDate = dueDate
+	}
+
+	result, err := server.store.BatchEntriesTx(ctx, arg)
+	if err != nil {
+		var opErr *db.BatchOperationError
+		if errors.As(err, &opErr) {
+			p := newProblem(ctx, http.StatusInternalServerError, opErr.Err)
+			ctx.Error(err)
+			writeProblem(ctx, p.Status, batchOperationProblem{problem: p, Index: opErr.Index})
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := batchEntriesResponse{
+		Results: make([]entryOperationResponse, len(result.Results)),
+		User:    newUserResponse(result.User),
+	}
+	for i, opResult := range result.Results {
+		rsp.Results[i] = entryOperationResponse{
+			Op:     opResult.Op,
+			Status: http.StatusOK,
+			Entry:  opResult.Entry,
+		}
+		if opResult.Err != nil {
+			p := newProblem(ctx, http.StatusInternalServerError, opResult.Err)
+			rsp.Results[i].Status = p.Status
+			rsp.Results[i].Error = &p
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchEntries(t *testing.T) {
+	user := CreateRandomUser()
+	entry := createRandomEntry(user)
+	newEntry := createRandomEntry(user)
+	newEntry.ID = entry.ID + 1
+
+	operations := []gin.H{
+		{
+			"op":       db.BatchCreate,
+			"name":     newEntry.Name,
+			"due_date": "2022-12-11",
+			"amount":   newEntry.Amount,
+		},
+		{
+			"op":      db.BatchDelete,
+			"id":      entry.ID,
+			"version": entry.Version,
+		},
+	}
+
+	arg := db.BatchEntriesTxParams{
+		Username: user.Username,
+		Operations: []db.EntryOperation{
+			{
+				Op:      db.BatchCreate,
+				Name:    newEntry.Name,
+				DueDate: newEntry.DueDate,
+				Amount:  newEntry.Amount,
+			},
+			{
+				Op:      db.BatchDelete,
+				ID:      entry.ID,
+				Version: entry.Version,
+			},
+		},
+	}
+
+	bestEffortArg := arg
+	bestEffortArg.BestEffort = true
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.BatchEntriesTxResult{
+						Results: []db.EntryOperationResult{
+							{Op: db.BatchCreate, Entry: &newEntry},
+							{Op: db.BatchDelete},
+						},
+						User: user,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp batchEntriesResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp.Results, 2)
+				require.Equal(t, http.StatusOK, rsp.Results[0].Status)
+				require.Equal(t, newEntry.ID, rsp.Results[0].Entry.ID)
+				require.Nil(t, rsp.Results[1].Entry)
+				require.Equal(t, user.TotalExpenses, rsp.User.TotalExpenses)
+			},
+		},
+		{
+			name: "BestEffort",
+			body: gin.H{"operations": operations, "best_effort": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Eq(bestEffortArg)).
+					Times(1).
+					Return(db.BatchEntriesTxResult{
+						Results: []db.EntryOperationResult{
+							{Op: db.BatchCreate, Entry: &newEntry},
+							{Op: db.BatchDelete, Err: &db.VersionMismatchError{Current: entry}},
+						},
+						User: user,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp batchEntriesResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, rsp.Results[0].Status)
+				require.Equal(t, http.StatusPreconditionFailed, rsp.Results[1].Status)
+				require.NotEmpty(t, rsp.Results[1].Error)
+			},
+		},
+		{
+			name: "OperationFailed",
+			body: gin.H{"operations": operations},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BatchEntriesTxResult{}, &db.BatchOperationError{Index: 1, Err: sql.ErrNoRows})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+
+				var rsp struct {
+					Index int `json:"index"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, 1, rsp.Index)
+			},
+		},
+		{
+			name: "UnsupportedOperation",
+			body: gin.H{"operations": []gin.H{{"op": "move", "id": entry.ID, "version": 1}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UpdateWithoutVersion",
+			body: gin.H{"operations": []gin.H{{
+				"op":       db.BatchUpdate,
+				"id":       entry.ID,
+				"name":     entry.Name,
+				"due_date": "2022-12-11",
+				"amount":   entry.Amount,
+			}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			body: gin.H{"operations": []gin.H{{
+				"op":       db.BatchCreate,
+				"name":     newEntry.Name,
+				"due_date": "2008-14-14",
+				"amount":   newEntry.Amount,
+			}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoOperations",
+			body: gin.H{"operations": []gin.H{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BatchEntriesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/entries/batch", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Used when the config leaves them out
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "If-Match", "Idempotency-Key", "X-Request-ID"}
+)
+
+// Response headers browsers let cross-origin clients read
+var corsExposedHeaders = []string{
+	"ETag", "Idempotent-Replayed", "Deprecation", "Link", "Location",
+	"Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+}
+
+var errCORSWildcardCredentials = errors.New("CORS cannot allow credentials from every origin, list the allowed origins instead of *")
+
+// corsPolicy decides which cross-origin requests browsers may make
+type corsPolicy struct {
+	allowAllOrigins  bool
+	origins          map[string]bool
+	methods          string
+	headers          string
+	maxAge           string
+	allowCredentials bool
+}
+
+func newCORSPolicy(config util.Config) (corsPolicy, error) {
+	policy := corsPolicy{
+		origins:          map[string]bool{},
+		methods:          strings.Join(defaultCORSMethods, ", "),
+		headers:          strings.Join(defaultCORSHeaders, ", "),
+		allowCredentials: config.CORSAllowCredentials,
+	}
+
+	for _, origin := range config.CORSAllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			policy.allowAllOrigins = true
+		} else if origin != "" {
+			policy.origins[strings.TrimSuffix(origin, "/")] = true
+		}
+	}
+	if policy.allowAllOrigins && policy.allowCredentials {
+		return policy, errCORSWildcardCredentials
+	}
+
+	if len(config.CORSAllowedMethods) > 0 {
+		policy.methods = strings.ToUpper(strings.Join(config.CORSAllowedMethods, ", "))
+	}
+	if len(config.CORSAllowedHeaders) > 0 {
+		policy.headers = strings.Join(config.CORSAllowedHeaders, ", ")
+	}
+	if config.CORSMaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(config.CORSMaxAge.Seconds()))
+	}
+
+	return policy, nil
+}
+
+func (policy corsPolicy) allowsOrigin(origin string) bool {
+	return policy.allowAllOrigins || policy.origins[origin]
+}
+
+// Adds the CORS headers for allowed origins and answers their preflight requests.
+// Requests from other origins get no CORS headers, so browsers block them.
+func corsMiddleware(policy corsPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		header := ctx.Writer.Header()
+		if !policy.allowAllOrigins {
+			// the response depends on the origin, shared caches must not mix them up
+			header.Add("Vary", "Origin")
+		}
+
+		origin := ctx.GetHeader("Origin")
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" || !policy.allowsOrigin(origin) {
+			if preflight {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		if policy.allowAllOrigins {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if policy.allowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", policy.methods)
+			header.Set("Access-Control-Allow-Headers", policy.headers)
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			ctx.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+		ctx.Next()
+	}
+}
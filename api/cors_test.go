@@ -0,0 +1,135 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	"github.com/LeandroEstevez/budgetAppAPI/events"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		CORSAllowedOrigins:   []string{"https://yourbudgetapp.com", "http://localhost:3000/"},
+		CORSAllowedMethods:   []string{"get", "post"},
+		CORSMaxAge:           time.Hour,
+		CORSAllowCredentials: true,
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		header        map[string]string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "AllowedOrigin",
+			method: http.MethodGet,
+			header: map[string]string{"Origin": "https://yourbudgetapp.com"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "https://yourbudgetapp.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+				require.Contains(t, recorder.Header().Get("Access-Control-Expose-Headers"), "ETag")
+				require.Equal(t, []string{"Origin"}, recorder.Header().Values("Vary"))
+			},
+		},
+		{
+			name:   "OriginWithoutTrailingSlash",
+			method: http.MethodGet,
+			header: map[string]string{"Origin": "http://localhost:3000"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, "http://localhost:3000", recorder.Header().Get("Access-Control-Allow-Origin"))
+			},
+		},
+		{
+			name:   "OtherOrigin",
+			method: http.MethodGet,
+			header: map[string]string{"Origin": "https://evil.example"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+				require.Equal(t, []string{"Origin"}, recorder.Header().Values("Vary"))
+			},
+		},
+		{
+			name:   "SameOrigin",
+			method: http.MethodGet,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Equal(t, []string{"Origin"}, recorder.Header().Values("Vary"))
+			},
+		},
+		{
+			name:   "Preflight",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "https://yourbudgetapp.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "authorization",
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+				require.Equal(t, "https://yourbudgetapp.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Equal(t, "GET, POST", recorder.Header().Get("Access-Control-Allow-Methods"))
+				require.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+				require.Equal(t, "3600", recorder.Header().Get("Access-Control-Max-Age"))
+				require.Equal(t, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"}, recorder.Header().Values("Vary"))
+			},
+		},
+		{
+			name:   "PreflightFromOtherOrigin",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                        "https://evil.example",
+				"Access-Control-Request-Method": "POST",
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+				require.Empty(t, recorder.Header().Get("Access-Control-Allow-Methods"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server, err := NewServer(config, mockdb.NewMockStore(ctrl), events.NewBroker(), util.NewMemoryRateLimiter(), zerolog.Nop())
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, "/openapi.json", nil)
+			require.NoError(t, err)
+			for key, value := range tc.header {
+				request.Header.Set(key, value)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCORSAllOrigins(t *testing.T) {
+	policy, err := newCORSPolicy(util.Config{CORSAllowedOrigins: []string{"*"}})
+	require.NoError(t, err)
+	require.True(t, policy.allowsOrigin("https://anywhere.example"))
+
+	_, err = newCORSPolicy(util.Config{CORSAllowedOrigins: []string{"*"}, CORSAllowCredentials: true})
+	require.ErrorIs(t, err, errCORSWildcardCredentials)
+}
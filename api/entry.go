@@ -1,7 +1,7 @@
 package api
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
@@ -22,16 +22,27 @@ type createEntryRequest struct {
 	Category string `json:"category" binding:"max=15"`
 }
 
+// entryResultResponse is the result of an entry transaction, without the
+// user's password hash
+type entryResultResponse struct {
+	Entry db.Entry     `json:"entry"`
+	User  userResponse `json:"user"`
+}
+
+type deleteEntryResponse struct {
+	User userResponse `json:"user"`
+}
+
 func (server *Server) addEntry(ctx *gin.Context) {
 	var req createEntryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -46,21 +57,86 @@ func (server *Server) addEntry(ctx *gin.Context) {
 
 	entryResult, err := server.store.AddEntryTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setEntryETag(ctx, entryResult.Entry)
+	rsp := entryResultResponse{
+		Entry: entryResult.Entry,
+		User:  newUserResponse(entryResult.User),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type getEntryRequest struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+func (server *Server) getEntry(ctx *gin.Context) {
+	var req getEntryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.GetEntryParams{
+		Owner: authPayload.Username,
+		ID:    req.ID,
+	}
+
+	entry, err := server.store.GetEntry(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entryResult)
+	setEntryETag(ctx, entry)
+	ctx.JSON(http.StatusOK, entry)
+}
+
+// Responds 412 with the current entry when the client's version is stale.
+// Returns false if err is not a version mismatch.
+func handleVersionMismatch(ctx *gin.Context, err error) bool {
+	var mismatch *db.VersionMismatchError
+	if !errors.As(err, &mismatch) {
+		return false
+	}
+
+	setEntryETag(ctx, mismatch.Current)
+	ctx.JSON(http.StatusPreconditionFailed, mismatch.Current)
+	return true
 }
 
 type deleteEntryRequest struct {
 	ID int32 `uri:"id" binding:"required,gt=0"`
 }
 
+type deleteEntryQuery struct {
+	Version int32 `form:"version" binding:"omitempty,gt=0"`
+}
+
 func (server *Server) deleteEntry(ctx *gin.Context) {
 	var req deleteEntryRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var query deleteEntryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	version, err := expectedVersion(ctx, query.Version)
+	if err != nil {
+		if err == errPreconditionRequired {
+			writeError(ctx, http.StatusPreconditionRequired, err)
+			return
+		}
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -68,15 +144,19 @@ func (server *Server) deleteEntry(ctx *gin.Context) {
 	arg := db.DeleteEntryTxParams{
 		Username: authPayload.Username,
 		ID:       req.ID,
+		Version:  version,
 	}
 
 	deleteEntryResult, err := server.store.DeleteEntryTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if handleVersionMismatch(ctx, err) {
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, deleteEntryResult)
+	ctx.JSON(http.StatusOK, deleteEntryResponse{User: newUserResponse(deleteEntryResult.User)})
 }
 
 type getEntriesRequest struct {
@@ -86,18 +166,14 @@ type getEntriesRequest struct {
 func (server *Server) getEntries(ctx *gin.Context) {
 	var req getEntriesRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	entries, err := server.store.GetEntries(ctx, authPayload.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -111,18 +187,14 @@ type getCategoriesRequest struct {
 func (server *Server) getCategories(ctx *gin.Context) {
 	var req getCategoriesRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	categories, err := server.store.GetCategories(ctx, authPayload.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -136,18 +208,19 @@ type updateEntryRequest struct {
 	DueDate  string `json:"due_date" binding:"required" time_format:"2006-01-02"`
 	Amount   int64  `json:"amount" binding:"required,gt=0"`
 	Category string `json:"Category" binding:"max=10"`
+	Version  int32  `json:"version" binding:"omitempty,gt=0"`
 }
 
 func (server *Server) updateEntry(ctx *gin.Context) {
 	var req updateEntryRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -161,11 +234,37 @@ func (server *Server) updateEntry(ctx *gin.Context) {
 		Category: req.Category,
 	}
 
-	updateEntryResult, err := server.store.UpdateEntryTx(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	updateEntryResult, ok := server.runUpdateEntryTx(ctx, arg, req.Version)
+	if !ok {
 		return
 	}
 
 	ctx.JSON(http.StatusOK, updateEntryResult.Entry)
 }
+
+// Updates the entry at the version expected by the client and sets its new ETag.
+// Writes the error response and returns false if the update fails.
+func (server *Server) runUpdateEntryTx(ctx *gin.Context, arg db.UpdateEntryTxParams, bodyVersion int32) (db.UpdateEntryTxResult, bool) {
+	version, err := expectedVersion(ctx, bodyVersion)
+	if err != nil {
+		if err == errPreconditionRequired {
+			writeError(ctx, http.StatusPreconditionRequired, err)
+			return db.UpdateEntryTxResult{}, false
+		}
+		writeError(ctx, http.StatusBadRequest, err)
+		return db.UpdateEntryTxResult{}, false
+	}
+	arg.Version = version
+
+	updateEntryResult, err := server.store.UpdateEntryTx(ctx, arg)
+	if err != nil {
+		if handleVersionMismatch(ctx, err) {
+			return db.UpdateEntryTxResult{}, false
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return db.UpdateEntryTxResult{}, false
+	}
+
+	setEntryETag(ctx, updateEntryResult.Entry)
+	return updateEntryResult, true
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
 	"github.com/LeandroEstevez/budgetAppAPI/token"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -152,7 +154,8 @@ func TestAddEntry(t *testing.T) {
 			recorder := httptest.NewRecorder()
 
 			if tc.name == "InvalidOwner" {
-				tc.reqArg.Username = "xyz"
+				// longer than any username
+				tc.reqArg.Username = "xyzxyzxyzxyzxyzxyz"
 			} else if tc.name == "InvalidDate" {
 				tc.reqArg.DueDate = "2008-14-14"
 			}
@@ -182,6 +185,7 @@ func TestUpdateEntry(t *testing.T) {
 		DueDate  string `json:"due_date"`
 		Amount   int64  `json:"amount"`
 		Category string `json:"category"`
+		Version  int32  `json:"version"`
 	}
 
 	arg := db.UpdateEntryTxParams{
@@ -191,6 +195,7 @@ func TestUpdateEntry(t *testing.T) {
 		DueDate:  entry.DueDate,
 		Amount:   util.RandomMoney(),
 		Category: util.RandomString(6),
+		Version:  entry.Version,
 	}
 
 	entryResult := db.UpdateEntryTxResult{
@@ -204,6 +209,7 @@ func TestUpdateEntry(t *testing.T) {
 				String: arg.Category,
 				Valid:  true,
 			},
+			Version: entry.Version + 1,
 		},
 		User: user,
 	}
@@ -215,11 +221,20 @@ func TestUpdateEntry(t *testing.T) {
 		DueDate:  "2022-12-11",
 		Amount:   arg.Amount,
 		Category: arg.Category,
+		Version:  entry.Version,
 	}
 
+	// same request carrying the version in If-Match instead of the body
+	headerReqArg := reqArg
+	headerReqArg.Version = 0
+
+	currentEntry := entry
+	currentEntry.Version = entry.Version + 1
+
 	testCases := []struct {
 		name          string
 		reqArg        UpdateEntryParamsTest
+		ifMatch       string
 		arg           db.UpdateEntryTxParams
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
@@ -242,9 +257,84 @@ func TestUpdateEntry(t *testing.T) {
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				// check http status code
 				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, entryETag(entryResult.Entry.Version), recorder.Header().Get("ETag"))
 				requireBodyMatchUpdateEntryResult(t, recorder.Body, entryResult)
 			},
 		},
+		{
+			name:    "IfMatch",
+			reqArg:  headerReqArg,
+			ifMatch: entryETag(entry.Version),
+			arg:     arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entryResult, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "VersionRequired",
+			reqArg: headerReqArg,
+			arg:    arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidIfMatch",
+			reqArg:  headerReqArg,
+			ifMatch: "abc",
+			arg:     arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "VersionMismatch",
+			reqArg: reqArg,
+			arg:    arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateEntryTxResult{}, &db.VersionMismatchError{Current: currentEntry})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				require.Equal(t, entryETag(currentEntry.Version), recorder.Header().Get("ETag"))
+
+				var gotEntry db.Entry
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotEntry)
+				require.NoError(t, err)
+				require.Equal(t, currentEntry.Version, gotEntry.Version)
+			},
+		},
 		{
 			name:   "InvalidOwner",
 			reqArg: reqArg,
@@ -328,6 +418,9 @@ func TestUpdateEntry(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(body))
 			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
 
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
@@ -353,6 +446,7 @@ func TestDeleteEntry(t *testing.T) {
 	arg := db.DeleteEntryTxParams{
 		Username: user.Username,
 		ID:       entry.ID,
+		Version:  entry.Version,
 	}
 
 	user.TotalExpenses = user.TotalExpenses - entry.Amount
@@ -388,6 +482,39 @@ func TestDeleteEntry(t *testing.T) {
 				requireBodyMatchDeletedEntryResult(t, recorder.Body, result)
 			},
 		},
+		{
+			name:   "VersionMismatch",
+			reqArg: reqArg,
+			arg:    arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.DeleteEntryTxResult{}, &db.VersionMismatchError{Current: entry})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+			},
+		},
+		{
+			name:   "VersionRequired",
+			reqArg: reqArg,
+			arg:    arg,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
 		{
 			name:   "BadId",
 			reqArg: reqArg,
@@ -451,6 +578,9 @@ func TestDeleteEntry(t *testing.T) {
 
 			request, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(body))
 			require.NoError(t, err)
+			if tc.name != "VersionRequired" {
+				request.Header.Set("If-Match", entryETag(entry.Version))
+			}
 
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
@@ -459,6 +589,85 @@ func TestDeleteEntry(t *testing.T) {
 	}
 }
 
+func TestGetEntry(t *testing.T) {
+	user := CreateRandomUser()
+	entry := createRandomEntry(user)
+
+	testCases := []struct {
+		name          string
+		entryID       int32
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			entryID: entry.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetEntryParams{
+					Owner: user.Username,
+					ID:    entry.ID,
+				}
+				store.EXPECT().
+					GetEntry(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entry, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, entryETag(entry.Version), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:    "NotFound",
+			entryID: entry.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Entry{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "BadId",
+			entryID: -1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEntry(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/entry/%d", tc.entryID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func TestGetEntries(t *testing.T) {
 	user := CreateRandomUser()
 
@@ -595,7 +804,9 @@ func requireBodyMatchEntryResult(t *testing.T, body *bytes.Buffer, entryResult d
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotEntry db.AddEntryTxResult
+	require.NotContains(t, string(data), "hashed_password")
+
+	var gotEntry entryResultResponse
 	err = json.Unmarshal(data, &gotEntry)
 	require.NoError(t, err)
 
@@ -625,7 +836,9 @@ func requireBodyMatchDeletedEntryResult(t *testing.T, body *bytes.Buffer, userRe
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotUser db.DeleteEntryTxResult
+	require.NotContains(t, string(data), "hashed_password")
+
+	var gotUser deleteEntryResponse
 	err = json.Unmarshal(data, &gotUser)
 	require.NoError(t, err)
 
@@ -660,5 +873,64 @@ func createRandomEntry(user db.User) db.Entry {
 		Name:    util.RandomString(6),
 		DueDate: date,
 		Amount:  5,
+		Version: 1,
+	}
+}
+
+// Runs the entry endpoints against the in-memory store, which keeps the total
+// expenses of the user like the database does
+func TestEntryTotalExpensesWithMemStore(t *testing.T) {
+	store := db.NewMemStore()
+	server := newTestServer(t, store)
+
+	user, err := store.CreateUser(context.Background(), db.CreateUserParams{
+		Username:       util.RandomString(6),
+		HashedPassword: "secret",
+		FullName:       util.RandomFullName(),
+		Email:          util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	serve := func(method string, url string, body interface{}) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+		require.NoError(t, err)
+		request.Header.Set("If-Match", "*")
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	requireTotalExpenses := func(totalExpenses int64) {
+		recorder := serve(http.MethodGet, fmt.Sprintf("/user/%s", user.Username), nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var rsp userResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+		require.Equal(t, totalExpenses, rsp.TotalExpenses)
+	}
+
+	var entries []db.Entry
+	for _, amount := range []int64{100, 50} {
+		recorder := serve(http.MethodPost, "/entry", gin.H{
+			"username": user.Username,
+			"name":     util.RandomString(8),
+			"due_date": "2022-12-11",
+			"amount":   amount,
+		})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var result entryResultResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+		entries = append(entries, result.Entry)
 	}
+	requireTotalExpenses(150)
+
+	recorder := serve(http.MethodDelete, fmt.Sprintf("/deleteEntry/%d", entries[0].ID), nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireTotalExpenses(50)
 }
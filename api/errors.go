@@ -0,0 +1,241 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const problemContentType = "application/problem+json"
+
+// Error codes clients can switch on. Errors without a more specific code use
+// the snake cased status text, e.g. "not_found" or "internal_server_error".
+const (
+	codeValidationFailed         = "validation_failed"
+	codeMalformedRequest         = "malformed_request"
+	codeInvalidReference         = "invalid_reference"
+	codeTokenExpired             = "token_expired"
+	codeInvalidToken             = "invalid_token"
+	codeVersionMismatch          = "version_mismatch"
+	codeInvalidSplit             = "invalid_split"
+	codeNotHouseholdMember       = "not_household_member"
+	codeSettlementExceedsDebt    = "settlement_exceeds_debt"
+	codeIdempotencyKeyReused     = "idempotency_key_reused"
+	codeIdempotencyKeyInProgress = "idempotency_key_in_progress"
+)
+
+// problem is an RFC 7807 problem details object
+type problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []fieldError `json:"errors,omitempty"`
+}
+
+// fieldError describes why a single field of the request was rejected
+type fieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors of the API itself that have their own code
+var errorCodes = map[error]string{
+	util.ErrInvalidSplit:          codeInvalidSplit,
+	db.ErrShareNotHouseholdMember: codeNotHouseholdMember,
+	db.ErrSettlementExceedsDebt:   codeSettlementExceedsDebt,
+	errIdempotencyKeyReused:       codeIdempotencyKeyReused,
+	errIdempotencyKeyInProgress:   codeIdempotencyKeyInProgress,
+}
+
+// Human readable details for the unique constraints clients can run into
+var uniqueViolationDetails = map[string]string{
+	"users_pkey":                   "username is already taken",
+	"users_email_key":              "email is already in use",
+	"entries_name_key":             "an entry with this name already exists",
+	"shared_expenses_entry_id_key": "entry is already split",
+	"household_members_pkey":       "user is already a member of the household",
+}
+
+// Builds the problem for err. Known errors get their own status, any other
+// error keeps the given one. Details of server errors are never exposed.
+func newProblem(ctx *gin.Context, status int, err error) problem {
+	p := problem{
+		Status: status,
+		Detail: err.Error(),
+	}
+
+	var (
+		validationErrors validator.ValidationErrors
+		syntaxError      *json.SyntaxError
+		typeError        *json.UnmarshalTypeError
+		mismatch         *db.VersionMismatchError
+	)
+	violation, constraint := db.ConstraintViolation(err)
+
+	switch {
+	case errors.As(err, &validationErrors):
+		p.Status = http.StatusBadRequest
+		p.Code = codeValidationFailed
+		p.Detail = "the request failed validation"
+		for _, fe := range validationErrors {
+			p.Errors = append(p.Errors, newFieldError(fe))
+		}
+	case errors.As(err, &typeError):
+		p.Status = http.StatusBadRequest
+		p.Code = codeValidationFailed
+		p.Detail = "the request failed validation"
+		p.Errors = []fieldError{{
+			Field:   typeError.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be a %s", typeError.Type),
+		}}
+	case errors.As(err, &syntaxError), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		p.Status = http.StatusBadRequest
+		p.Code = codeMalformedRequest
+		p.Detail = "the request body is not valid JSON"
+	case errors.Is(err, sql.ErrNoRows):
+		p.Status = http.StatusNotFound
+		p.Detail = "the requested resource was not found"
+	case errors.Is(err, token.ErrExpiredToken):
+		p.Status = http.StatusUnauthorized
+		p.Code = codeTokenExpired
+	case errors.Is(err, token.ErrInvalidToken):
+		p.Status = http.StatusUnauthorized
+		p.Code = codeInvalidToken
+	case errors.As(err, &mismatch):
+		p.Status = http.StatusPreconditionFailed
+		p.Code = codeVersionMismatch
+		p.Detail = fmt.Sprintf("entry is at version %d", mismatch.Current.Version)
+	case violation == db.UniqueViolation:
+		p.Status = http.StatusConflict
+		p.Detail = uniqueViolationDetails[constraint]
+		if p.Detail == "" {
+			p.Detail = "the resource already exists"
+		}
+	case violation == db.ForeignKeyViolation:
+		p.Status = http.StatusUnprocessableEntity
+		p.Code = codeInvalidReference
+		p.Detail = "the request refers to a resource that doesn't exist"
+	}
+
+	for target, code := range errorCodes {
+		if p.Code == "" && errors.Is(err, target) {
+			p.Code = code
+		}
+	}
+	if p.Code == "" {
+		p.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(p.Status), " ", "_"))
+	}
+	if p.Status >= http.StatusInternalServerError {
+		p.Detail = ""
+	}
+
+	p.Type = "about:blank"
+	p.Title = http.StatusText(p.Status)
+	p.Instance = ctx.Request.URL.Path
+	p.RequestID = requestID(ctx)
+	return p
+}
+
+func newFieldError(fe validator.FieldError) fieldError {
+	// drop the name of the request struct from the namespace
+	field := fe.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+
+	return fieldError{
+		Field:   field,
+		Code:    fe.Tag(),
+		Message: validationMessage(fe),
+	}
+}
+
+func validationMessage(fe validator.FieldError) string {
+	unit := ""
+	switch fe.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required", "required_unless":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "alphanum":
+		return "must contain only letters and numbers"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "role":
+		return fmt.Sprintf("must be one of: %s, %s, %s", util.OwnerRole, util.EditorRole, util.ViewerRole)
+	case "split_type":
+		return fmt.Sprintf("must be one of: %s, %s, %s", util.EqualSplit, util.ExactSplit, util.PercentageSplit)
+	case "webhook_event":
+		return fmt.Sprintf("must be one of: %s, %s, %s", util.EntryCreatedEvent, util.BudgetExceededEvent, util.BillDueEvent)
+	case "url":
+		return "must be a valid URL"
+	case "webhook_url":
+		return "must be an http or https URL of a public host"
+	}
+	return fmt.Sprintf("failed the %s validation", fe.Tag())
+}
+
+// Names fields after the request tag the client sent them in
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "uri", "form"} {
+		name := strings.Split(field.Tag.Get(key), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Responds with the problem for err
+func writeError(ctx *gin.Context, status int, err error) {
+	p := newProblem(ctx, status, err)
+	ctx.Error(err)
+	writeProblem(ctx, p.Status, p)
+}
+
+// Responds with a problem, or a type embedding one to add extension members
+func writeProblem(ctx *gin.Context, status int, body interface{}) {
+	ctx.Header("Content-Type", problemContentType)
+	ctx.JSON(status, body)
+}
+
+// Responds with the problem for err and stops the remaining handlers
+func abortWithError(ctx *gin.Context, status int, err error) {
+	p := newProblem(ctx, status, err)
+	ctx.Error(err)
+	ctx.Header("Content-Type", problemContentType)
+	ctx.AbortWithStatusJSON(p.Status, p)
+}
@@ -0,0 +1,205 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeProblem(t *testing.T, recorder *httptest.ResponseRecorder) problem {
+	require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var p problem
+	err := json.Unmarshal(recorder.Body.Bytes(), &p)
+	require.NoError(t, err)
+	require.Equal(t, recorder.Code, p.Status)
+	require.Equal(t, http.StatusText(p.Status), p.Title)
+	return p
+}
+
+func TestWriteError(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "NoRows",
+			status: http.StatusInternalServerError,
+			err:    sql.ErrNoRows,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, "not_found", p.Code)
+				require.Equal(t, "/test", p.Instance)
+				require.Equal(t, "request-id", p.RequestID)
+			},
+		},
+		{
+			name:   "UniqueViolation",
+			status: http.StatusInternalServerError,
+			err:    &pq.Error{Code: "23505", Constraint: "users_email_key"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, "conflict", p.Code)
+				require.Equal(t, "email is already in use", p.Detail)
+			},
+		},
+		{
+			name:   "ForeignKeyViolation",
+			status: http.StatusInternalServerError,
+			err:    &pq.Error{Code: "23503", Message: "insert violates foreign key constraint"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, codeInvalidReference, p.Code)
+				require.NotContains(t, p.Detail, "constraint")
+			},
+		},
+		{
+			name:   "ExpiredToken",
+			status: http.StatusUnauthorized,
+			err:    token.ErrExpiredToken,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, codeTokenExpired, p.Code)
+			},
+		},
+		{
+			name:   "VersionMismatch",
+			status: http.StatusInternalServerError,
+			err:    &db.VersionMismatchError{Current: db.Entry{Version: 3}},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, codeVersionMismatch, p.Code)
+			},
+		},
+		{
+			name:   "ClientError",
+			status: http.StatusForbidden,
+			err:    errors.New("owners cannot remove themselves"),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, "forbidden", p.Code)
+				require.Equal(t, "owners cannot remove themselves", p.Detail)
+			},
+		},
+		{
+			name:   "InternalError",
+			status: http.StatusInternalServerError,
+			err:    &pq.Error{Code: "08006", Message: "connection failure"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				p := decodeProblem(t, recorder)
+				require.Equal(t, "internal_server_error", p.Code)
+				require.Empty(t, p.Detail)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/test", nil)
+			ctx.Set(requestIDKey, "request-id")
+
+			writeError(ctx, tc.status, tc.err)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestValidationProblem(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		code   string
+		fields []fieldError
+	}{
+		{
+			name: "InvalidFields",
+			body: `{"username": "abc!", "full_name": "Full Name", "email": "not-an-email", "password": "secret"}`,
+			code: codeValidationFailed,
+			fields: []fieldError{
+				{Field: "username", Code: "alphanum", Message: "must contain only letters and numbers"},
+				{Field: "email", Code: "email", Message: "must be a valid email address"},
+			},
+		},
+		{
+			name: "WrongType",
+			body: `{"username": 123}`,
+			code: codeValidationFailed,
+			fields: []fieldError{
+				{Field: "username", Code: "type", Message: "must be a string"},
+			},
+		},
+		{
+			name: "MalformedJSON",
+			body: `{"username": `,
+			code: codeMalformedRequest,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				CreateUserTx(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/users", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			p := decodeProblem(t, recorder)
+			require.Equal(t, tc.code, p.Code)
+			require.Equal(t, tc.fields, p.Errors)
+		})
+	}
+}
+
+func TestAuthMiddlewareProblem(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/me", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "user", -time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	p := decodeProblem(t, recorder)
+	require.Equal(t, codeTokenExpired, p.Code)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errPreconditionRequired = errors.New("the If-Match header or a version is required")
+	errInvalidIfMatch       = errors.New("the If-Match header is not a valid entry version")
+)
+
+// Formats an entry version as a strong ETag
+func entryETag(version int32) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+func setEntryETag(ctx *gin.Context, entry db.Entry) {
+	ctx.Header("ETag", entryETag(entry.Version))
+}
+
+// Returns the entry version the client expects to change. The If-Match header wins
+// over the version sent in the request, "*" matches any version and is returned as 0.
+func expectedVersion(ctx *gin.Context, version int32) (int32, error) {
+	ifMatch := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if ifMatch == "" {
+		if version == 0 {
+			return 0, errPreconditionRequired
+		}
+		return version, nil
+	}
+
+	if ifMatch == "*" {
+		return 0, nil
+	}
+
+	ifMatch = strings.TrimPrefix(ifMatch, "W/")
+	parsed, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 32)
+	if err != nil || parsed <= 0 {
+		return 0, errInvalidIfMatch
+	}
+
+	return int32(parsed), nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Comment sent on idle streams so proxies don't close them
+const eventsHeartbeatInterval = 30 * time.Second
+
+type eventsRequest struct {
+	AccessToken string `form:"access_token"`
+}
+
+// EventSource can't set headers, so browsers send the access token in the query
+func queryTokenMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req eventsRequest
+		if err := ctx.ShouldBindQuery(&req); err == nil && req.AccessToken != "" && ctx.GetHeader(authorizationHeaderKey) == "" {
+			ctx.Request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, req.AccessToken))
+		}
+		ctx.Next()
+	}
+}
+
+// Streams the changes to the entries and the total of the authenticated user
+// as Server-Sent Events until the client disconnects
+func (server *Server) streamEvents(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	events, cancel := server.events.Subscribe(authPayload.Username)
+	defer cancel()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/events"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents(t *testing.T) {
+	testCases := []struct {
+		name      string
+		setupAuth func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		status    int
+	}{
+		{
+			name: "Header",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "user01", time.Minute)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "QueryToken",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				accessToken, err := tokenMaker.CreateToken("user01", time.Minute)
+				require.NoError(t, err)
+				request.URL.RawQuery = fmt.Sprintf("access_token=%s", accessToken)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			status:    http.StatusUnauthorized,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			httpServer := httptest.NewServer(server.router)
+			defer httpServer.Close()
+
+			request, err := http.NewRequest(http.MethodGet, httpServer.URL+"/events", nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			response, err := httpServer.Client().Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			require.Equal(t, tc.status, response.StatusCode)
+			if tc.status != http.StatusOK {
+				return
+			}
+			require.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+			// the subscription is made before the headers are sent
+			server.events.Publish(events.Event{Type: events.TotalUpdated, Username: "user02", Data: events.Total{TotalExpenses: 1}})
+			server.events.Publish(events.Event{Type: events.TotalUpdated, Username: "user01", Data: events.Total{TotalExpenses: 2}})
+
+			reader := bufio.NewReader(response.Body)
+			var lines []string
+			for len(lines) < 2 {
+				line, err := reader.ReadString('\n')
+				require.NoError(t, err)
+				if line = strings.TrimSpace(line); line != "" {
+					lines = append(lines, line)
+				}
+			}
+			require.Equal(t, []string{"event:total.updated", `data:{"total_expenses":2}`}, lines)
+		})
+	}
+}
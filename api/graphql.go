@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]interface{}     `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Runs a GraphQL query as the authenticated user. Errors of the query itself
+// are part of the GraphQL response, which is always sent with status 200.
+func (server *Server) executeGraphQL(ctx *gin.Context) {
+	var req graphQLRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result := server.graphQL.Execute(ctx, authPayload.Username, req.Query, req.OperationName, req.Variables)
+
+	rsp := graphQLResponse{Errors: result.Errors}
+	if data, ok := result.Data.(map[string]interface{}); ok {
+		rsp.Data = data
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
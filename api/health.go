@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
+	"github.com/gin-gonic/gin"
+)
+
+// Time the readiness checks get before the replica is reported unavailable
+const readinessTimeout = 2 * time.Second
+
+const (
+	healthOK          = "ok"
+	healthReady       = "ready"
+	healthUnavailable = "unavailable"
+)
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness probe, the process answers as long as it isn't stuck
+func (server *Server) getHealth(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, healthResponse{Status: healthOK})
+}
+
+// Readiness probe, the replica is ready once the database is reachable and
+// migrated at least to the schema embedded in the binary. Replicas of an older
+// release stay ready while a newer one migrates further.
+func (server *Server) getReadiness(ctx *gin.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	rsp := healthResponse{Status: healthReady, Checks: map[string]string{}}
+	fail := func(check string, problem string, err error) {
+		requestLogger(ctx).Warn().Err(err).Str("check", check).Msg("readiness check failed")
+		rsp.Checks[check] = problem
+		rsp.Status = healthUnavailable
+	}
+
+	err := server.store.Ping(checkCtx)
+	if err != nil {
+		fail("database", "unreachable", err)
+	} else {
+		rsp.Checks["database"] = healthOK
+
+		version, err := server.store.GetMigrationVersion(checkCtx)
+		latest, latestErr := migration.LatestVersion(server.config.DBDriver)
+		switch {
+		case err != nil:
+			fail("migrations", "cannot read the migration version", err)
+		case latestErr != nil:
+			fail("migrations", "cannot read the embedded migrations", latestErr)
+		case version.Dirty:
+			fail("migrations", fmt.Sprintf("migration %d failed", version.Version), nil)
+		case version.Version < int64(latest):
+			fail("migrations", fmt.Sprintf("behind: at version %d, want %d", version.Version, latest), nil)
+		default:
+			rsp.Checks["migrations"] = healthOK
+		}
+	}
+
+	status := http.StatusOK
+	if rsp.Status != healthReady {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, rsp)
+}
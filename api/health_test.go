@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func latestMigration(t *testing.T) int64 {
+	latest, err := migration.LatestVersion(db.PostgresDriver)
+	require.NoError(t, err)
+	return int64(latest)
+}
+
+func TestGetHealth(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/healthz", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
+
+func TestGetReadiness(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+		checks     map[string]string
+	}{
+		{
+			name: "Ready",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetMigrationVersion(gomock.Any()).Times(1).Return(db.MigrationVersion{Version: latestMigration(t)}, nil)
+			},
+			status: http.StatusOK,
+			checks: map[string]string{"database": "ok", "migrations": "ok"},
+		},
+		{
+			name: "DatabaseUnreachable",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(errors.New("dial tcp: connection refused"))
+				store.EXPECT().GetMigrationVersion(gomock.Any()).Times(0)
+			},
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "unreachable"},
+		},
+		{
+			name: "MigrationsBehind",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetMigrationVersion(gomock.Any()).Times(1).Return(db.MigrationVersion{Version: latestMigration(t) - 1}, nil)
+			},
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "ok", "migrations": fmt.Sprintf("behind: at version %d, want %d", latestMigration(t)-1, latestMigration(t))},
+		},
+		{
+			name: "MigrationsAhead",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetMigrationVersion(gomock.Any()).Times(1).Return(db.MigrationVersion{Version: latestMigration(t) + 1}, nil)
+			},
+			status: http.StatusOK,
+			checks: map[string]string{"database": "ok", "migrations": "ok"},
+		},
+		{
+			name: "MigrationDirty",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().Ping(gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetMigrationVersion(gomock.Any()).Times(1).Return(db.MigrationVersion{Version: latestMigration(t), Dirty: true}, nil)
+			},
+			status: http.StatusServiceUnavailable,
+			checks: map[string]string{"database": "ok", "migrations": fmt.Sprintf("migration %d failed", latestMigration(t))},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/readyz", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+
+			var rsp healthResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, tc.checks, rsp.Checks)
+			if tc.status == http.StatusOK {
+				require.Equal(t, "ready", rsp.Status)
+			} else {
+				require.Equal(t, "unavailable", rsp.Status)
+			}
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- server.Start("127.0.0.1:0")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+	require.NoError(t, <-stopped)
+
+	// the event streams end with the server
+	events, cancelEvents := server.events.Subscribe("user01")
+	defer cancelEvents()
+	_, ok := <-events
+	require.False(t, ok)
+}
@@ -0,0 +1,466 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+type createHouseholdRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+func (server *Server) createHousehold(ctx *gin.Context) {
+	var req createHouseholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateHouseholdTxParams{
+		Name:     req.Name,
+		Username: authPayload.Username,
+	}
+
+	result, err := server.store.CreateHouseholdTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) listHouseholds(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	households, err := server.store.ListHouseholds(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, households)
+}
+
+type householdResponse struct {
+	Household     db.Household         `json:"household"`
+	Members       []db.HouseholdMember `json:"members"`
+	TotalExpenses int64                `json:"total_expenses"`
+}
+
+func (server *Server) getHousehold(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	household, err := server.store.GetHousehold(ctx, member.HouseholdID)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	members, err := server.store.ListHouseholdMembers(ctx, member.HouseholdID)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	total, err := server.store.GetHouseholdTotal(ctx, householdIDParam(member.HouseholdID))
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := householdResponse{
+		Household:     household,
+		Members:       members,
+		TotalExpenses: total,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type createHouseholdEntryRequest struct {
+	Name     string `json:"name" binding:"required,alphanum,min=1"`
+	DueDate  string `json:"due_date" binding:"required" time_format:"2006-01-02"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Category string `json:"category" binding:"max=15"`
+}
+
+func (server *Server) addHouseholdEntry(ctx *gin.Context) {
+	var req createHouseholdEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	arg := db.CreateHouseholdEntryParams{
+		Owner:       member.Username,
+		Name:        req.Name,
+		DueDate:     dueDate,
+		Amount:      req.Amount,
+		Category:    sql.NullString{String: req.Category, Valid: req.Category != ""},
+		HouseholdID: householdIDParam(member.HouseholdID),
+	}
+
+	entry, err := server.store.CreateHouseholdEntryTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	metrics.EntriesCreated.Inc()
+
+	ctx.JSON(http.StatusOK, entry)
+}
+
+func (server *Server) getHouseholdEntries(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	entries, err := server.store.GetHouseholdEntries(ctx, householdIDParam(member.HouseholdID))
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+type updateHouseholdEntryRequest struct {
+	ID       int32  `json:"id" binding:"required,gt=0"`
+	Name     string `json:"name" binding:"required,alphanum,min=1"`
+	DueDate  string `json:"due_date" binding:"required" time_format:"2006-01-02"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Category string `json:"category" binding:"max=15"`
+	Version  int32  `json:"version" binding:"omitempty,gt=0"`
+}
+
+func (server *Server) updateHouseholdEntry(ctx *gin.Context) {
+	var req updateHouseholdEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	version, err := expectedVersion(ctx, req.Version)
+	if err != nil {
+		if err == errPreconditionRequired {
+			writeError(ctx, http.StatusPreconditionRequired, err)
+			return
+		}
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	arg := db.UpdateHouseholdEntryTxParams{
+		Username:    member.Username,
+		HouseholdID: member.HouseholdID,
+		ID:          req.ID,
+		Name:        req.Name,
+		DueDate:     dueDate,
+		Amount:      req.Amount,
+		Category:    sql.NullString{String: req.Category, Valid: req.Category != ""},
+		Version:     version,
+	}
+
+	entry, err := server.store.UpdateHouseholdEntryTx(ctx, arg)
+	if err != nil {
+		if handleVersionMismatch(ctx, err) {
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setEntryETag(ctx, entry)
+	ctx.JSON(http.StatusOK, entry)
+}
+
+type deleteHouseholdEntryRequest struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+func (server *Server) deleteHouseholdEntry(ctx *gin.Context) {
+	var req deleteHouseholdEntryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var query deleteEntryQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	version, err := expectedVersion(ctx, query.Version)
+	if err != nil {
+		if err == errPreconditionRequired {
+			writeError(ctx, http.StatusPreconditionRequired, err)
+			return
+		}
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	arg := db.DeleteHouseholdEntryTxParams{
+		Username:    member.Username,
+		HouseholdID: member.HouseholdID,
+		ID:          req.ID,
+		Version:     version,
+	}
+
+	err = server.store.DeleteHouseholdEntryTx(ctx, arg)
+	if err != nil {
+		if handleVersionMismatch(ctx, err) {
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Deletion Completed")
+}
+
+func (server *Server) getHouseholdTrash(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	householdID := sql.NullInt32{Int32: member.HouseholdID, Valid: true}
+	entries, err := server.store.ListDeletedHouseholdEntries(ctx, householdID)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+func (server *Server) restoreHouseholdEntry(ctx *gin.Context) {
+	var req restoreEntryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	arg := db.RestoreHouseholdEntryTxParams{
+		Username:    member.Username,
+		HouseholdID: member.HouseholdID,
+		ID:          req.ID,
+	}
+
+	entry, err := server.store.RestoreHouseholdEntryTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setEntryETag(ctx, entry)
+	ctx.JSON(http.StatusOK, entry)
+}
+
+func (server *Server) listHouseholdMembers(ctx *gin.Context) {
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+
+	members, err := server.store.ListHouseholdMembers(ctx, member.HouseholdID)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, members)
+}
+
+type inviteHouseholdMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,role"`
+}
+
+func (server *Server) inviteHouseholdMember(ctx *gin.Context) {
+	var req inviteHouseholdMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	arg := db.CreateHouseholdInvitationParams{
+		HouseholdID: member.HouseholdID,
+		Email:       req.Email,
+		Role:        req.Role,
+		InvitedBy:   member.Username,
+	}
+
+	invitation, err := server.store.CreateHouseholdInvitationTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	markIdempotentResponseFinal(ctx)
+
+	emailData := util.EmailData{
+		URL:       fmt.Sprintf("%s/invitations/%d", server.config.FrontendURL, invitation.ID),
+		FirstName: member.Username,
+		Subject:   "You have been invited to share a budget",
+		ToEmail:   invitation.Email,
+	}
+
+	err = server.sendEmail(&emailData, "householdInvitation.html")
+	if err != nil {
+		writeError(ctx, http.StatusBadGateway, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitation)
+}
+
+type householdMemberRequest struct {
+	Username string `uri:"username" binding:"required,alphanum,min=1,max=15"`
+}
+
+type updateHouseholdMemberRequest struct {
+	Role string `json:"role" binding:"required,role"`
+}
+
+func (server *Server) updateHouseholdMember(ctx *gin.Context) {
+	var uri householdMemberRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req updateHouseholdMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	if uri.Username == member.Username {
+		err := errors.New("owners cannot change their own role")
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.UpdateHouseholdMemberRoleTxParams{
+		Username:    member.Username,
+		HouseholdID: member.HouseholdID,
+		Member:      uri.Username,
+		Role:        req.Role,
+	}
+
+	updatedMember, err := server.store.UpdateHouseholdMemberRoleTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updatedMember)
+}
+
+func (server *Server) removeHouseholdMember(ctx *gin.Context) {
+	var uri householdMemberRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	member := ctx.MustGet(householdMemberKey).(db.HouseholdMember)
+	if uri.Username == member.Username {
+		err := errors.New("owners cannot remove themselves")
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.DeleteHouseholdMemberTxParams{
+		Username:    member.Username,
+		HouseholdID: member.HouseholdID,
+		Member:      uri.Username,
+	}
+
+	err := server.store.DeleteHouseholdMemberTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, "Deletion Completed")
+}
+
+func (server *Server) listHouseholdInvitations(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	invitations, err := server.store.ListHouseholdInvitations(ctx, user.Email)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+type acceptHouseholdInvitationRequest struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+func (server *Server) acceptHouseholdInvitation(ctx *gin.Context) {
+	var req acceptHouseholdInvitationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	arg := db.AcceptHouseholdInvitationTxParams{
+		InvitationID: req.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+	}
+
+	result, err := server.store.AcceptHouseholdInvitationTx(ctx, arg)
+	if err != nil {
+		if err == db.ErrInvitationNotValid {
+			writeError(ctx, http.StatusForbidden, err)
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func householdIDParam(householdID int32) sql.NullInt32 {
+	return sql.NullInt32{Int32: householdID, Valid: true}
+}
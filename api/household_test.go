@@ -0,0 +1,893 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHousehold(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+
+	result := db.CreateHouseholdTxResult{
+		Household: household,
+		Member: db.HouseholdMember{
+			HouseholdID: household.ID,
+			Username:    user.Username,
+			Role:        util.OwnerRole,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name": household.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateHouseholdTxParams{
+					Name:     household.Name,
+					Username: user.Username,
+				}
+				store.EXPECT().
+					CreateHouseholdTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchHouseholdResult(t, recorder.Body, result)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"name": household.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingName",
+			body: gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"name": household.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateHouseholdTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateHouseholdTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/households", bytes.NewBuffer(body))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddHouseholdEntry(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	entry := createRandomEntry(user)
+	entry.HouseholdID = sql.NullInt32{Int32: household.ID, Valid: true}
+	entry.CreatedBy = sql.NullString{String: user.Username, Valid: true}
+
+	body := gin.H{
+		"name":     entry.Name,
+		"due_date": "2022-12-11",
+		"amount":   entry.Amount,
+	}
+
+	memberArg := db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Editor",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}, nil)
+
+				arg := db.CreateHouseholdEntryParams{
+					Owner:       user.Username,
+					Name:        entry.Name,
+					DueDate:     entry.DueDate,
+					Amount:      entry.Amount,
+					HouseholdID: entry.HouseholdID,
+				}
+				store.EXPECT().
+					CreateHouseholdEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entry, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotEntry db.Entry
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotEntry)
+				require.NoError(t, err)
+				require.Equal(t, entry.HouseholdID, gotEntry.HouseholdID)
+				require.Equal(t, entry.CreatedBy, gotEntry.CreatedBy)
+			},
+		},
+		{
+			name: "Viewer",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.ViewerRole}, nil)
+				store.EXPECT().
+					CreateHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotMember",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdMember{}, sql.ErrConnDone)
+				store.EXPECT().
+					CreateHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/households/%d/entries", household.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateHouseholdEntry(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	entry := createRandomEntry(user)
+	entry.HouseholdID = sql.NullInt32{Int32: household.ID, Valid: true}
+
+	memberArg := db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+	}
+	updateArg := db.UpdateHouseholdEntryTxParams{
+		Username:    user.Username,
+		HouseholdID: household.ID,
+		ID:          entry.ID,
+		Name:        entry.Name,
+		DueDate:     entry.DueDate,
+		Amount:      entry.Amount,
+		Version:     entry.Version,
+	}
+	editor := db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}
+
+	testCases := []struct {
+		name          string
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := entry
+				updated.Version = entry.Version + 1
+
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					UpdateHouseholdEntryTx(gomock.Any(), gomock.Eq(updateArg)).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, entryETag(entry.Version+1), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:    "PreconditionRequired",
+			ifMatch: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					UpdateHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:    "PreconditionFailed",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				current := entry
+				current.Version = entry.Version + 1
+
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					UpdateHouseholdEntryTx(gomock.Any(), gomock.Eq(updateArg)).
+					Times(1).
+					Return(db.Entry{}, &db.VersionMismatchError{Current: current})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				require.Equal(t, entryETag(entry.Version+1), recorder.Header().Get("ETag"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"id":       entry.ID,
+				"name":     entry.Name,
+				"due_date": entry.DueDate.Format(YYYYMMDD),
+				"amount":   entry.Amount,
+			})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/households/%d/entries", household.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteHouseholdEntry(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	entry := createRandomEntry(user)
+
+	memberArg := db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+	}
+	deleteArg := db.DeleteHouseholdEntryTxParams{
+		Username:    user.Username,
+		HouseholdID: household.ID,
+		ID:          entry.ID,
+		Version:     entry.Version,
+	}
+	editor := db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}
+
+	testCases := []struct {
+		name          string
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Eq(deleteArg)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Eq(deleteArg)).
+					Times(1).
+					Return(sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "Viewer",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.ViewerRole}, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "PreconditionRequired",
+			ifMatch: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:    "PreconditionFailed",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				current := entry
+				current.Version = entry.Version + 1
+
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Eq(deleteArg)).
+					Times(1).
+					Return(&db.VersionMismatchError{Current: current})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				require.Equal(t, entryETag(entry.Version+1), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:    "InternalError",
+			ifMatch: entryETag(entry.Version),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}, nil)
+				store.EXPECT().
+					DeleteHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/households/%d/entries/%d", household.ID, entry.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetHouseholdTrash(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	entries := []db.Entry{createRandomEntry(user), createRandomEntry(user), createRandomEntry(user)}
+
+	memberArg := db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+	}
+	viewer := db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.ViewerRole}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(viewer, nil)
+				store.EXPECT().
+					ListDeletedHouseholdEntries(gomock.Any(), gomock.Eq(sql.NullInt32{Int32: household.ID, Valid: true})).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchEntries(t, recorder.Body, entries)
+			},
+		},
+		{
+			name: "NotMember",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListDeletedHouseholdEntries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(viewer, nil)
+				store.EXPECT().
+					ListDeletedHouseholdEntries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/households/%d/trash", household.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRestoreHouseholdEntry(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	entry := createRandomEntry(user)
+
+	memberArg := db.GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+	}
+	restoreArg := db.RestoreHouseholdEntryTxParams{
+		Username:    user.Username,
+		HouseholdID: household.ID,
+		ID:          entry.ID,
+	}
+	editor := db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.EditorRole}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					RestoreHouseholdEntryTx(gomock.Any(), gomock.Eq(restoreArg)).
+					Times(1).
+					Return(entry, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, entryETag(entry.Version), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name: "Viewer",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.ViewerRole}, nil)
+				store.EXPECT().
+					RestoreHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					RestoreHouseholdEntryTx(gomock.Any(), gomock.Eq(restoreArg)).
+					Times(1).
+					Return(db.Entry{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Eq(memberArg)).
+					Times(1).
+					Return(editor, nil)
+				store.EXPECT().
+					RestoreHouseholdEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Entry{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/households/%d/trash/%d/restore", household.ID, entry.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUpdateHouseholdMember(t *testing.T) {
+	owner := CreateRandomUser()
+	editor := CreateRandomUser()
+	household := createRandomHousehold()
+
+	ownerMember := db.HouseholdMember{
+		HouseholdID: household.ID,
+		Username:    owner.Username,
+		Role:        util.OwnerRole,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: editor.Username,
+			role:     util.ViewerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ownerMember, nil)
+
+				arg := db.UpdateHouseholdMemberRoleTxParams{
+					Username:    owner.Username,
+					HouseholdID: household.ID,
+					Member:      editor.Username,
+					Role:        util.ViewerRole,
+				}
+				store.EXPECT().
+					UpdateHouseholdMemberRoleTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.HouseholdMember{HouseholdID: household.ID, Username: editor.Username, Role: util.ViewerRole}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "UnsupportedRole",
+			username: editor.Username,
+			role:     "admin",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ownerMember, nil)
+				store.EXPECT().
+					UpdateHouseholdMemberRoleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnRole",
+			username: owner.Username,
+			role:     util.ViewerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ownerMember, nil)
+				store.EXPECT().
+					UpdateHouseholdMemberRoleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: editor.Username,
+			role:     util.ViewerRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetHouseholdMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(ownerMember, nil)
+				store.EXPECT().
+					UpdateHouseholdMemberRoleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.HouseholdMember{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"role": tc.role})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/households/%d/members/%s", household.ID, tc.username)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func createRandomHousehold() db.Household {
+	return db.Household{
+		ID:        int32(util.RandomInt(1, 1000)),
+		Name:      util.RandomString(8),
+		CreatedAt: time.Now(),
+	}
+}
+
+func requireBodyMatchHouseholdResult(t *testing.T, body *bytes.Buffer, result db.CreateHouseholdTxResult) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotResult db.CreateHouseholdTxResult
+	err = json.Unmarshal(data, &gotResult)
+	require.NoError(t, err)
+
+	require.Equal(t, result.Household.ID, gotResult.Household.ID)
+	require.Equal(t, result.Household.Name, gotResult.Household.Name)
+	require.Equal(t, result.Member.Username, gotResult.Member.Username)
+	require.Equal(t, result.Member.Role, gotResult.Member.Role)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeaderKey     = "Idempotency-Key"
+	idempotentReplayedHeaderKey = "Idempotent-Replayed"
+	idempotentFinalKey          = "idempotent_final"
+	maxIdempotencyKeyLength     = 255
+)
+
+var (
+	errIdempotencyKeyTooLong    = errors.New("idempotency key is too long")
+	errIdempotencyKeyReused     = errors.New("idempotency key was already used for a different request")
+	errIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+// Hashes what identifies a request so that a reused key can be told apart from a retry
+func requestHash(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Captures the response body so it can be replayed for retries
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Makes POST requests carrying an Idempotency-Key header safe to retry.
+// The first request with a key is processed and its response stored, retries with
+// the same key and request within ttl replay that response, and reusing the key for
+// a different request is rejected with 422. Must run after authMiddleware.
+func idempotencyMiddleware(store db.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(idempotencyKeyHeaderKey)
+		if ctx.Request.Method != http.MethodPost || key == "" {
+			ctx.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(ctx, http.StatusBadRequest, errIdempotencyKeyTooLong)
+			return
+		}
+
+		body, err := ioutil.ReadAll(ctx.Request.Body)
+		if err != nil {
+			abortWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		ctx.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		arg := db.CreateIdempotencyKeyParams{
+			Username:    authPayload.Username,
+			Key:         key,
+			RequestHash: requestHash(ctx.Request.Method, ctx.Request.URL.Path, body),
+		}
+
+		claimed, err := claimIdempotencyKey(ctx, store, arg, ttl)
+		if err != nil {
+			if err == errIdempotencyKeyInProgress {
+				abortWithError(ctx, http.StatusConflict, err)
+				return
+			}
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		if !claimed.claimed {
+			existing := claimed.existing
+			switch {
+			case existing.RequestHash != arg.RequestHash:
+				abortWithError(ctx, http.StatusUnprocessableEntity, errIdempotencyKeyReused)
+			case existing.StatusCode == 0:
+				abortWithError(ctx, http.StatusConflict, errIdempotencyKeyInProgress)
+			default:
+				contentType := existing.ContentType
+				if contentType == "" {
+					contentType = gin.MIMEJSON + "; charset=utf-8"
+				}
+				ctx.Header(idempotentReplayedHeaderKey, "true")
+				ctx.Data(int(existing.StatusCode), contentType, existing.ResponseBody)
+				ctx.Abort()
+			}
+			return
+		}
+
+		keyParams := db.DeleteIdempotencyKeyParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+		}
+
+		// a handler that panics never finishes the request, so the key must not stay claimed
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				releaseIdempotencyKey(ctx, store, keyParams)
+				panic(recovered)
+			}
+		}()
+
+		writer := &idempotencyResponseWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		ctx.Next()
+
+		// failed transactions are rolled back, so the client may retry with the same key,
+		// unless the handler committed its changes before failing
+		if writer.Status() >= http.StatusInternalServerError && !ctx.GetBool(idempotentFinalKey) {
+			releaseIdempotencyKey(ctx, store, keyParams)
+			return
+		}
+
+		err = store.SaveIdempotencyResponse(ctx, db.SaveIdempotencyResponseParams{
+			Username:     arg.Username,
+			Key:          arg.Key,
+			StatusCode:   int32(writer.Status()),
+			ResponseBody: writer.body.Bytes(),
+			ContentType:  writer.Header().Get("Content-Type"),
+		})
+		if err != nil {
+			// without its response the key would be in progress until it expires
+			requestLogger(ctx).Error().Err(err).Msg("cannot save idempotent response")
+			releaseIdempotencyKey(ctx, store, keyParams)
+		}
+	}
+}
+
+// Tells idempotencyMiddleware that the handler has committed its changes, so the
+// response is stored for retries even if the handler then fails with a 5xx.
+// Otherwise retrying after a failure such as a lost email would repeat the changes.
+func markIdempotentResponseFinal(ctx *gin.Context) {
+	ctx.Set(idempotentFinalKey, true)
+}
+
+// Deletes the key so the request can be retried with it. The request may be
+// canceled already, so this doesn't run under its context.
+func releaseIdempotencyKey(ctx *gin.Context, store db.Store, arg db.DeleteIdempotencyKeyParams) {
+	err := store.DeleteIdempotencyKey(context.Background(), arg)
+	if err != nil {
+		requestLogger(ctx).Error().Err(err).Msg("cannot release idempotency key")
+	}
+}
+
+type idempotencyClaim struct {
+	claimed  bool
+	existing db.IdempotencyKey
+}
+
+// Stores the key for this request, or returns the request that already holds it.
+// Keys older than ttl are released first, a ttl of 0 keeps them forever.
+func claimIdempotencyKey(ctx *gin.Context, store db.Store, arg db.CreateIdempotencyKeyParams, ttl time.Duration) (idempotencyClaim, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		_, err := store.CreateIdempotencyKey(ctx, arg)
+		if err == nil {
+			return idempotencyClaim{claimed: true}, nil
+		}
+		if err != sql.ErrNoRows {
+			return idempotencyClaim{}, err
+		}
+
+		existing, err := store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+		})
+		if err == sql.ErrNoRows {
+			// released in the meantime
+			continue
+		}
+		if err != nil {
+			return idempotencyClaim{}, err
+		}
+
+		if ttl <= 0 || time.Since(existing.CreatedAt) < ttl {
+			return idempotencyClaim{existing: existing}, nil
+		}
+
+		err = store.DeleteIdempotencyKey(ctx, db.DeleteIdempotencyKeyParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+		})
+		if err != nil {
+			return idempotencyClaim{}, err
+		}
+	}
+
+	return idempotencyClaim{}, errIdempotencyKeyInProgress
+}
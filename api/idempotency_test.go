@@ -0,0 +1,402 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	user := CreateRandomUser()
+	friend := CreateRandomUser()
+	key := "retry-key"
+
+	body, err := json.Marshal(gin.H{
+		"to_username": friend.Username,
+		"amount":      10,
+	})
+	require.NoError(t, err)
+
+	hash := requestHash(http.MethodPost, "/settlements", body)
+	keyArg := db.CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         key,
+		RequestHash: hash,
+	}
+	getKeyArg := db.GetIdempotencyKeyParams{
+		Username: user.Username,
+		Key:      key,
+	}
+
+	result := db.SettleUpTxResult{
+		Settlement: db.Settlement{
+			ID:           1,
+			FromUsername: user.Username,
+			ToUsername:   friend.Username,
+			Amount:       10,
+		},
+	}
+	storedBody, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Eq(keyArg)).
+					Times(1).
+					Return(db.IdempotencyKey{Username: user.Username, Key: key, RequestHash: hash}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+				store.EXPECT().
+					SaveIdempotencyResponse(gomock.Any(), gomock.Eq(db.SaveIdempotencyResponseParams{
+						Username:     user.Username,
+						Key:          key,
+						StatusCode:   http.StatusOK,
+						ResponseBody: storedBody,
+						ContentType:  gin.MIMEJSON + "; charset=utf-8",
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get(idempotentReplayedHeaderKey))
+			},
+		},
+		{
+			name: "Replay",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Username:     user.Username,
+						Key:          key,
+						RequestHash:  hash,
+						StatusCode:   http.StatusOK,
+						ResponseBody: storedBody,
+						CreatedAt:    time.Now(),
+					}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeaderKey))
+				require.JSONEq(t, string(storedBody), recorder.Body.String())
+			},
+		},
+		{
+			name: "ReplayProblem",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).
+					Times(1).
+					Return(db.IdempotencyKey{
+						Username:     user.Username,
+						Key:          key,
+						RequestHash:  hash,
+						StatusCode:   http.StatusNotFound,
+						ResponseBody: []byte(`{"status":404}`),
+						ContentType:  problemContentType,
+						CreatedAt:    time.Now(),
+					}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+				require.JSONEq(t, `{"status":404}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "DifferentRequest",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{
+						RequestHash: "another request",
+						StatusCode:  http.StatusOK,
+						CreatedAt:   time.Now(),
+					}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InProgress",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{RequestHash: hash, CreatedAt: time.Now()}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "FailedRequestReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SettleUpTxResult{}, sql.ErrConnDone)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Username: user.Username,
+						Key:      key,
+					})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "SaveFailureReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+				store.EXPECT().
+					SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Username: user.Username,
+						Key:      key,
+					})).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PanicReleasesKey",
+			key:  key,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.IdempotencyKey{}, nil)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.SettleUpTxParams) (db.SettleUpTxResult, error) {
+						panic("settle up failed")
+					})
+				store.EXPECT().
+					DeleteIdempotencyKey(gomock.Any(), gomock.Eq(db.DeleteIdempotencyKeyParams{
+						Username: user.Username,
+						Key:      key,
+					})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoKey",
+			key:  "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/settlements", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			if tc.key != "" {
+				request.Header.Set(idempotencyKeyHeaderKey, tc.key)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestIdempotencyMiddlewareFinalFailure(t *testing.T) {
+	user := CreateRandomUser()
+	household := createRandomHousehold()
+	key := "invite-key"
+
+	body, err := json.Marshal(gin.H{
+		"email": util.RandomEmail(),
+		"role":  util.EditorRole,
+	})
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("/households/%d/invitations", household.ID)
+	hash := requestHash(http.MethodPost, url, body)
+	invitation := db.HouseholdInvitation{ID: 1, HouseholdID: household.ID, Role: util.EditorRole, InvitedBy: user.Username}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	// the invitation is created once, the retry replays the stored 502
+	var saved db.SaveIdempotencyResponseParams
+	gomock.InOrder(
+		store.EXPECT().
+			CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(db.IdempotencyKey{Username: user.Username, Key: key, RequestHash: hash}, nil),
+		store.EXPECT().
+			CreateIdempotencyKey(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(db.IdempotencyKey{}, sql.ErrNoRows),
+	)
+	store.EXPECT().
+		GetHouseholdMember(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.HouseholdMember{HouseholdID: household.ID, Username: user.Username, Role: util.OwnerRole}, nil)
+	store.EXPECT().
+		CreateHouseholdInvitationTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(invitation, nil)
+	store.EXPECT().
+		SaveIdempotencyResponse(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.SaveIdempotencyResponseParams) error {
+			saved = arg
+			return nil
+		})
+	store.EXPECT().
+		DeleteIdempotencyKey(gomock.Any(), gomock.Any()).
+		Times(0)
+	store.EXPECT().
+		GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: user.Username, Key: key})).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+			return db.IdempotencyKey{
+				Username:     saved.Username,
+				Key:          saved.Key,
+				RequestHash:  hash,
+				StatusCode:   saved.StatusCode,
+				ResponseBody: saved.ResponseBody,
+				ContentType:  saved.ContentType,
+				CreatedAt:    time.Now(),
+			}, nil
+		})
+
+	server := newTestServer(t, store)
+	server.sendEmail = func(data *util.EmailData, templateName string) error {
+		return errors.New("cannot reach the mail server")
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+		require.NoError(t, err)
+		request.Header.Set(idempotencyKeyHeaderKey, key)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadGateway, recorder.Code)
+		require.Equal(t, attempt == 1, recorder.Header().Get(idempotentReplayedHeaderKey) == "true")
+	}
+}
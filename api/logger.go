@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	requestIDHeaderKey = "X-Request-ID"
+	requestIDKey       = "request_id"
+)
+
+// Request IDs sent by clients are kept when they look like one
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Query parameters whose values never make it into the logs
+var sensitiveQueryParams = []string{"access_token", "token", "password", "secret"}
+
+// Gives every request an ID, taken from the X-Request-ID header or generated, and
+// a logger that adds it and the trace ID to every line. The ID is echoed in the response header.
+func requestIDMiddleware(logger zerolog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(requestIDHeaderKey)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		ctx.Set(requestIDKey, id)
+		ctx.Header(requestIDHeaderKey, id)
+
+		logContext := logger.With().Str(requestIDKey, id)
+		if span := trace.SpanContextFromContext(ctx.Request.Context()); span.IsValid() {
+			logContext = logContext.Str("trace_id", span.TraceID().String())
+		}
+		requestLogger := logContext.Logger()
+		ctx.Request = ctx.Request.WithContext(requestLogger.WithContext(ctx.Request.Context()))
+		ctx.Next()
+	}
+}
+
+// Returns the ID given to the request by requestIDMiddleware
+func requestID(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+// Returns the logger of the request, whose lines carry its ID
+func requestLogger(ctx *gin.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx.Request.Context())
+}
+
+// Logs a line for every request once it is handled, with the errors handlers reported
+func loggerMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		event := requestLogger(ctx).Info()
+		if status >= http.StatusInternalServerError {
+			event = requestLogger(ctx).Error()
+		} else if status >= http.StatusBadRequest {
+			event = requestLogger(ctx).Warn()
+		}
+
+		event = event.
+			Str("method", ctx.Request.Method).
+			Str("path", redactURL(ctx.Request.URL)).
+			Int("status", status).
+			Dur("duration", time.Since(start)).
+			Int("size", ctx.Writer.Size()).
+			Str("client_ip", ctx.ClientIP())
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			event = event.Str("username", payload.(*token.Payload).Username)
+		}
+		if len(ctx.Errors) > 0 {
+			event = event.Strs("errors", ctx.Errors.Errors())
+		}
+		event.Msg("handled request")
+	}
+}
+
+// Logs panics and answers them with a 500 problem
+func recoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(ctx *gin.Context, recovered interface{}) {
+		requestLogger(ctx).Error().
+			Interface("panic", recovered).
+			Str("stack", string(debug.Stack())).
+			Msg("recovered from panic")
+		abortWithError(ctx, http.StatusInternalServerError, fmt.Errorf("panic: %v", recovered))
+	})
+}
+
+// Returns the path and query of u with the values of sensitive parameters replaced
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for key := range query {
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(key, sensitive) {
+				query[key] = []string{"REDACTED"}
+			}
+		}
+	}
+	return u.Path + "?" + query.Encode()
+}
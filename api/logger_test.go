@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Returns the JSON log lines written to buffer
+func logLines(t *testing.T, buffer *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &fields))
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requestID string
+		checkID   func(t *testing.T, id string)
+	}{
+		{
+			name:      "Propagated",
+			requestID: "abc-123",
+			checkID: func(t *testing.T, id string) {
+				require.Equal(t, "abc-123", id)
+			},
+		},
+		{
+			name: "Generated",
+			checkID: func(t *testing.T, id string) {
+				require.Len(t, id, 36)
+			},
+		},
+		{
+			name:      "Invalid",
+			requestID: "bad id\nforged: line",
+			checkID: func(t *testing.T, id string) {
+				require.Len(t, id, 36)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+			var logs bytes.Buffer
+			server.logger = zerolog.New(&logs)
+			server.setUpRouter()
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/v1/me", nil)
+			require.NoError(t, err)
+			if tc.requestID != "" {
+				request.Header.Set(requestIDHeaderKey, tc.requestID)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+			id := recorder.Header().Get(requestIDHeaderKey)
+			tc.checkID(t, id)
+			require.Equal(t, id, decodeProblem(t, recorder).RequestID)
+
+			lines := logLines(t, &logs)
+			require.Len(t, lines, 1)
+			require.Equal(t, id, lines[0]["request_id"])
+			require.Equal(t, "warn", lines[0]["level"])
+			require.Equal(t, float64(http.StatusUnauthorized), lines[0]["status"])
+			require.Equal(t, []interface{}{"authorization header is not provided"}, lines[0]["errors"])
+		})
+	}
+}
+
+func TestLoggerRedactsTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	var logs bytes.Buffer
+	server.logger = zerolog.New(&logs)
+	server.setUpRouter()
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/events?access_token=secret-token&since=1", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.NotContains(t, logs.String(), "secret-token")
+	require.Equal(t, "/events?access_token=REDACTED&since=1", logLines(t, &logs)[0]["path"])
+}
+
+func TestRecovery(t *testing.T) {
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(requestIDMiddleware(zerolog.New(&logs)), loggerMiddleware(), recoveryMiddleware())
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/panic", nil)
+	require.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	require.NotEmpty(t, decodeProblem(t, recorder).RequestID)
+
+	lines := logLines(t, &logs)
+	require.Len(t, lines, 2)
+	require.Equal(t, "boom", lines[0]["panic"])
+	require.Equal(t, "error", lines[1]["level"])
+}
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/events"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,7 +19,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 		AccessTokenDuration: time.Minute,
 	}
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, events.NewBroker(), util.NewMemoryRateLimiter(), zerolog.Nop())
 	require.NoError(t, err)
 
 	return server
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Route label of the requests that match no route, so unknown paths don't each get a series
+const unmatchedRoute = "unmatched"
+
+// Counts the requests and observes their latency by route and status
+func metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(ctx.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
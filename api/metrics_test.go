@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	for _, path := range []string{"/v1/entries/1", "/does/not/exist"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		server.router.ServeHTTP(recorder, request)
+	}
+
+	// the metrics are served on their own address, not by the public router
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	require.Contains(t, body, `budgetapp_http_requests_total{method="GET",route="/v1/entries/:id",status="401"}`)
+	require.Contains(t, body, `budgetapp_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	require.Contains(t, body, `budgetapp_http_request_duration_seconds_bucket{method="GET",route="/v1/entries/:id",status="401"`)
+	require.NotContains(t, body, "/does/not/exist")
+}
@@ -2,8 +2,10 @@ package api
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/LeandroEstevez/budgetAppAPI/token"
 	"github.com/gin-gonic/gin"
@@ -20,32 +22,55 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			abortWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		ctx.Set(authorizationPayloadKey, payload)
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+// When the routes outside /v1 were deprecated
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Marks a route as deprecated and links to the route replacing it. successors maps
+// a route to its successor when the path changed, other routes move under /v1.
+func deprecationMiddleware(successors map[string]string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", legacyRoutesDeprecatedAt.Unix())
+
+	return func(ctx *gin.Context) {
+		successor, ok := successors[ctx.FullPath()]
+		if !ok {
+			successor = "/v1" + ctx.FullPath()
+		}
+		for _, param := range ctx.Params {
+			successor = strings.ReplaceAll(successor, ":"+param.Key, param.Value)
+		}
+
+		ctx.Header("Deprecation", deprecation)
+		ctx.Header("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		ctx.Next()
+	}
+}
@@ -0,0 +1,674 @@
+package api
+
+import (
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed swagger.html
+var swaggerUI []byte
+
+// openAPIOperation documents what a handler expects and returns. The schemas
+// are generated from the request structs so binding constraints stay in sync.
+type openAPIOperation struct {
+	Summary  string
+	Tag      string
+	Public   bool          // doesn't need an access token
+	URI      []interface{} // structs bound with ShouldBindUri
+	Query    interface{}   // struct bound with ShouldBindQuery
+	Body     interface{}   // struct bound with ShouldBindJSON
+	IfMatch  bool          // accepts an entry version in the If-Match header
+	ReadOnly bool          // POST that changes nothing, so it takes no Idempotency-Key
+	Stream   string        // media type of a streamed response, the response is JSON otherwise
+	Status   int           // success status, 200 when 0
+	Response interface{}   // success response, nil when there is no body
+}
+
+type openAPIRoute struct {
+	Method     string
+	Path       string
+	Operation  openAPIOperation
+	Deprecated bool
+}
+
+var (
+	createUserOperation = openAPIOperation{
+		Summary: "Create a user", Tag: "users", Public: true,
+		Body: createUserRequest{}, Response: userResponse{},
+	}
+	logInUserOperation = openAPIOperation{
+		Summary: "Log in and get an access token", Tag: "users", Public: true,
+		Body: logInUserRequest{}, Response: userResponse{},
+	}
+	restoreUserOperation = openAPIOperation{
+		Summary: "Restore a deleted account", Tag: "users", Public: true,
+		Body: restoreUserRequest{}, Response: userResponse{},
+	}
+	forgotPasswordOperation = openAPIOperation{
+		Summary: "Email a password reset link", Tag: "users", Public: true,
+		Body: forgotPasswordRequest{}, Response: "",
+	}
+	resetPasswordOperation = openAPIOperation{
+		Summary: "Change the password", Tag: "users",
+		Body: resetPasswordRequest{}, Response: "",
+	}
+	getEntryOperation = openAPIOperation{
+		Summary: "Get an entry", Tag: "entries",
+		URI: []interface{}{getEntryRequest{}}, Response: db.Entry{},
+	}
+	deleteEntryOperation = openAPIOperation{
+		Summary: "Move an entry to the trash", Tag: "entries",
+		URI: []interface{}{deleteEntryRequest{}}, Query: deleteEntryQuery{}, IfMatch: true,
+		Response: deleteEntryResponse{},
+	}
+	batchEntriesOperation = openAPIOperation{
+		Summary: "Create, update and delete several entries", Tag: "entries",
+		Body: batchEntriesRequest{}, Response: batchEntriesResponse{},
+	}
+)
+
+// Documented routes, in the same groups as setUpRouter
+func openAPIRoutes() []openAPIRoute {
+	routes := []openAPIRoute{
+		{http.MethodGet, "/openapi.json", openAPIOperation{Summary: "This OpenAPI document", Tag: "docs", Public: true, Response: map[string]interface{}{}}, false},
+		{http.MethodGet, "/docs", openAPIOperation{Summary: "Swagger UI for this document", Tag: "docs", Public: true, Response: ""}, false},
+		{http.MethodGet, "/healthz", openAPIOperation{Summary: "Liveness probe", Tag: "monitoring", Public: true, Response: healthResponse{}}, false},
+		{http.MethodGet, "/readyz", openAPIOperation{Summary: "Readiness probe, 503 until the database is reachable and migrated", Tag: "monitoring", Public: true, Response: healthResponse{}}, false},
+		{http.MethodPost, "/graphql", openAPIOperation{
+			Summary: "Run a GraphQL query over the user's data", Tag: "graphql", ReadOnly: true,
+			Body: graphQLRequest{}, Response: graphQLResponse{},
+		}, false},
+		{http.MethodGet, "/events", openAPIOperation{
+			Summary: "Stream the changes to the user's entries and total", Tag: "events",
+			Query: eventsRequest{}, Stream: "text/event-stream", Response: "",
+		}, false},
+
+		{http.MethodPost, "/v1/users", createUserOperation, false},
+		{http.MethodPost, "/v1/users/login", logInUserOperation, false},
+		{http.MethodPost, "/v1/users/restore", restoreUserOperation, false},
+		{http.MethodPost, "/v1/users/forgot-password", forgotPasswordOperation, false},
+		{http.MethodGet, "/v1/entries", openAPIOperation{
+			Summary: "List entries", Tag: "entries", Response: []db.Entry{},
+		}, false},
+		{http.MethodPost, "/v1/entries", openAPIOperation{
+			Summary: "Create an entry", Tag: "entries",
+			Body: entryRequest{}, Status: http.StatusCreated, Response: entryResultResponse{},
+		}, false},
+		{http.MethodPost, "/v1/entries/batch", batchEntriesOperation, false},
+		{http.MethodGet, "/v1/entries/:id", getEntryOperation, false},
+		{http.MethodPatch, "/v1/entries/:id", openAPIOperation{
+			Summary: "Update an entry", Tag: "entries",
+			URI: []interface{}{patchEntryURI{}}, Body: patchEntryRequest{}, IfMatch: true, Response: db.Entry{},
+		}, false},
+		{http.MethodDelete, "/v1/entries/:id", deleteEntryOperation, false},
+		{http.MethodGet, "/v1/categories", openAPIOperation{
+			Summary: "List the categories in use", Tag: "entries", Response: []sql.NullString{},
+		}, false},
+		{http.MethodGet, "/v1/me", openAPIOperation{
+			Summary: "Get the authenticated user", Tag: "users", Response: userResponse{},
+		}, false},
+		{http.MethodPatch, "/v1/me", openAPIOperation{
+			Summary: "Update the authenticated user", Tag: "users",
+			Body: updateMeRequest{}, Response: userResponse{},
+		}, false},
+		{http.MethodDelete, "/v1/me", openAPIOperation{
+			Summary: "Move the authenticated user to the trash", Tag: "users", Status: http.StatusNoContent,
+		}, false},
+		{http.MethodPut, "/v1/me/password", resetPasswordOperation, false},
+		{http.MethodPost, "/v1/webhooks", openAPIOperation{
+			Summary: "Register a webhook, the response holds its signing secret", Tag: "webhooks",
+			Body: createWebhookRequest{}, Status: http.StatusCreated, Response: createWebhookResponse{},
+		}, false},
+		{http.MethodGet, "/v1/webhooks", openAPIOperation{
+			Summary: "List webhooks", Tag: "webhooks", Response: []webhookResponse{},
+		}, false},
+		{http.MethodGet, "/v1/webhooks/:id", openAPIOperation{
+			Summary: "Get a webhook", Tag: "webhooks",
+			URI: []interface{}{webhookRequest{}}, Response: webhookResponse{},
+		}, false},
+		{http.MethodDelete, "/v1/webhooks/:id", openAPIOperation{
+			Summary: "Delete a webhook and its delivery log", Tag: "webhooks",
+			URI: []interface{}{webhookRequest{}}, Status: http.StatusNoContent,
+		}, false},
+		{http.MethodGet, "/v1/webhooks/:id/deliveries", openAPIOperation{
+			Summary: "List the deliveries of a webhook, newest first", Tag: "webhooks",
+			URI: []interface{}{webhookRequest{}}, Query: listWebhookDeliveriesQuery{}, Response: []db.WebhookDelivery{},
+		}, false},
+		{http.MethodPost, "/v1/webhooks/:id/test", openAPIOperation{
+			Summary: "Send a webhook.test event to a webhook", Tag: "webhooks",
+			URI: []interface{}{webhookRequest{}}, Status: http.StatusAccepted, Response: db.WebhookDelivery{},
+		}, false},
+	}
+	routes = append(routes, sharedOpenAPIRoutes("/v1", false)...)
+
+	legacy := []openAPIRoute{
+		{http.MethodPost, "/forgotpassword", forgotPasswordOperation, true},
+		{http.MethodPost, "/user", createUserOperation, true},
+		{http.MethodPost, "/user/login", logInUserOperation, true},
+		{http.MethodPost, "/user/restore", restoreUserOperation, true},
+		{http.MethodPost, "/entry", openAPIOperation{
+			Summary: "Create an entry", Tag: "entries",
+			Body: createEntryRequest{}, Response: entryResultResponse{},
+		}, true},
+		{http.MethodGet, "/entry/:id", getEntryOperation, true},
+		{http.MethodPatch, "/updateEntry", openAPIOperation{
+			Summary: "Update an entry", Tag: "entries",
+			Body: updateEntryRequest{}, IfMatch: true, Response: db.Entry{},
+		}, true},
+		{http.MethodDelete, "/deleteEntry/:id", deleteEntryOperation, true},
+		{http.MethodGet, "/entries", openAPIOperation{
+			Summary: "List entries", Tag: "entries",
+			Query: getEntriesRequest{}, Response: []db.Entry{},
+		}, true},
+		{http.MethodPost, "/entries/batch", batchEntriesOperation, true},
+		{http.MethodGet, "/categories", openAPIOperation{
+			Summary: "List the categories in use", Tag: "entries",
+			Query: getCategoriesRequest{}, Response: []sql.NullString{},
+		}, true},
+		{http.MethodDelete, "/deleteUser/:username", openAPIOperation{
+			Summary: "Move the authenticated user to the trash", Tag: "users",
+			URI: []interface{}{deleteUserRequest{}}, Response: "",
+		}, true},
+		{http.MethodPatch, "/resetPassword", resetPasswordOperation, true},
+		{http.MethodPatch, "/updateAccount", openAPIOperation{
+			Summary: "Update the authenticated user", Tag: "users",
+			Body: updateAccountRequest{}, Response: db.User{},
+		}, true},
+		{http.MethodGet, "/user/:username", openAPIOperation{
+			Summary: "Get the authenticated user", Tag: "users",
+			URI: []interface{}{getUserRequest{}}, Response: userResponse{},
+		}, true},
+	}
+	routes = append(routes, legacy...)
+	return append(routes, sharedOpenAPIRoutes("", true)...)
+}
+
+func sharedOpenAPIRoutes(prefix string, deprecated bool) []openAPIRoute {
+	household := []interface{}{householdURI{}}
+	routes := []openAPIRoute{
+		{http.MethodGet, "/audit", openAPIOperation{
+			Summary: "List the changes made by the user", Tag: "audit",
+			Query: listAuditEventsRequest{}, Response: []db.AuditEvent{},
+		}, deprecated},
+		{http.MethodGet, "/trash", openAPIOperation{
+			Summary: "List the entries in the trash", Tag: "trash", Response: []db.Entry{},
+		}, deprecated},
+		{http.MethodPost, "/trash/:id/restore", openAPIOperation{
+			Summary: "Restore an entry from the trash", Tag: "trash",
+			URI: []interface{}{restoreEntryRequest{}}, Response: entryResultResponse{},
+		}, deprecated},
+		{http.MethodPost, "/households", openAPIOperation{
+			Summary: "Create a household", Tag: "households",
+			Body: createHouseholdRequest{}, Response: db.CreateHouseholdTxResult{},
+		}, deprecated},
+		{http.MethodGet, "/households", openAPIOperation{
+			Summary: "List the user's households", Tag: "households", Response: []db.Household{},
+		}, deprecated},
+		{http.MethodGet, "/invitations", openAPIOperation{
+			Summary: "List the invitations sent to the user", Tag: "households", Response: []db.HouseholdInvitation{},
+		}, deprecated},
+		{http.MethodPost, "/invitations/:id/accept", openAPIOperation{
+			Summary: "Accept an invitation", Tag: "households",
+			URI: []interface{}{acceptHouseholdInvitationRequest{}}, Response: db.AcceptHouseholdInvitationTxResult{},
+		}, deprecated},
+		{http.MethodPost, "/splits", openAPIOperation{
+			Summary: "Split an entry between users", Tag: "splits",
+			Body: splitExpenseRequest{}, Response: db.SplitExpenseTxResult{},
+		}, deprecated},
+		{http.MethodGet, "/balances", openAPIOperation{
+			Summary: "List what the user owes and is owed", Tag: "splits", Response: []balanceResponse{},
+		}, deprecated},
+		{http.MethodPost, "/settlements", openAPIOperation{
+			Summary: "Record a repayment", Tag: "splits",
+			Body: settleUpRequest{}, Response: db.SettleUpTxResult{},
+		}, deprecated},
+		{http.MethodGet, "/settlements", openAPIOperation{
+			Summary: "List the user's repayments", Tag: "splits", Response: []db.Settlement{},
+		}, deprecated},
+		{http.MethodGet, "/settlements/suggestions", openAPIOperation{
+			Summary: "Suggest the fewest repayments that settle every balance", Tag: "splits", Response: []util.Transfer{},
+		}, deprecated},
+		{http.MethodGet, "/households/:household_id", openAPIOperation{
+			Summary: "Get a household", Tag: "households",
+			URI: household, Response: householdResponse{},
+		}, deprecated},
+		{http.MethodGet, "/households/:household_id/entries", openAPIOperation{
+			Summary: "List the household's entries", Tag: "households",
+			URI: household, Response: []db.Entry{},
+		}, deprecated},
+		{http.MethodPost, "/households/:household_id/entries", openAPIOperation{
+			Summary: "Create a household entry", Tag: "households",
+			URI: household, Body: createHouseholdEntryRequest{}, Response: db.Entry{},
+		}, deprecated},
+		{http.MethodPatch, "/households/:household_id/entries", openAPIOperation{
+			Summary: "Update a household entry", Tag: "households",
+			URI: household, Body: updateHouseholdEntryRequest{}, IfMatch: true, Response: db.Entry{},
+		}, deprecated},
+		{http.MethodDelete, "/households/:household_id/entries/:id", openAPIOperation{
+			Summary: "Move a household entry to the trash", Tag: "households",
+			URI: []interface{}{householdURI{}, deleteHouseholdEntryRequest{}}, Query: deleteEntryQuery{}, IfMatch: true, Response: "",
+		}, deprecated},
+		{http.MethodGet, "/households/:household_id/trash", openAPIOperation{
+			Summary: "List the household entries in the trash", Tag: "households",
+			URI: household, Response: []db.Entry{},
+		}, deprecated},
+		{http.MethodPost, "/households/:household_id/trash/:id/restore", openAPIOperation{
+			Summary: "Restore a household entry from the trash", Tag: "households",
+			URI: []interface{}{householdURI{}, restoreEntryRequest{}}, Response: db.Entry{},
+		}, deprecated},
+		{http.MethodGet, "/households/:household_id/members", openAPIOperation{
+			Summary: "List the household's members", Tag: "households",
+			URI: household, Response: []db.HouseholdMember{},
+		}, deprecated},
+		{http.MethodPatch, "/households/:household_id/members/:username", openAPIOperation{
+			Summary: "Change the role of a member", Tag: "households",
+			URI: []interface{}{householdURI{}, householdMemberRequest{}}, Body: updateHouseholdMemberRequest{}, Response: db.HouseholdMember{},
+		}, deprecated},
+		{http.MethodDelete, "/households/:household_id/members/:username", openAPIOperation{
+			Summary: "Remove a member", Tag: "households",
+			URI: []interface{}{householdURI{}, householdMemberRequest{}}, Response: "",
+		}, deprecated},
+		{http.MethodPost, "/households/:household_id/invitations", openAPIOperation{
+			Summary: "Invite someone to the household", Tag: "households",
+			URI: household, Body: inviteHouseholdMemberRequest{}, Response: db.HouseholdInvitation{},
+		}, deprecated},
+	}
+
+	for i := range routes {
+		routes[i].Path = prefix + routes[i].Path
+	}
+	return routes
+}
+
+// openAPISchema is the subset of the OpenAPI 3.0 schema object the API needs
+type openAPISchema struct {
+	Ref              string                    `json:"$ref,omitempty"`
+	Type             string                    `json:"type,omitempty"`
+	Format           string                    `json:"format,omitempty"`
+	Description      string                    `json:"description,omitempty"`
+	Pattern          string                    `json:"pattern,omitempty"`
+	Enum             []string                  `json:"enum,omitempty"`
+	MinLength        *int64                    `json:"minLength,omitempty"`
+	MaxLength        *int64                    `json:"maxLength,omitempty"`
+	Minimum          *int64                    `json:"minimum,omitempty"`
+	Maximum          *int64                    `json:"maximum,omitempty"`
+	ExclusiveMinimum bool                      `json:"exclusiveMinimum,omitempty"`
+	MinItems         *int64                    `json:"minItems,omitempty"`
+	MaxItems         *int64                    `json:"maxItems,omitempty"`
+	Items            *openAPISchema            `json:"items,omitempty"`
+	Properties       map[string]*openAPISchema `json:"properties,omitempty"`
+	Required         []string                  `json:"required,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIHeader struct {
+	Description string         `json:"description"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description,omitempty"`
+	Headers     map[string]openAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+	Ref         string                      `json:"$ref,omitempty"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIOperationObject struct {
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	OperationID string                     `json:"operationId"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                        `json:"openapi"`
+	Info       map[string]string                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperationObject `json:"paths"`
+	Components struct {
+		Schemas         map[string]*openAPISchema         `json:"schemas"`
+		Responses       map[string]openAPIResponse        `json:"responses"`
+		SecuritySchemes map[string]map[string]interface{} `json:"securitySchemes"`
+	} `json:"components"`
+}
+
+// Converts a gin path like /entries/:id to an OpenAPI path like /entries/{id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Builds the OpenAPI document of the routes
+func newOpenAPIDocument(routes []openAPIRoute) *openAPIDocument {
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: map[string]string{
+			"title":   "Budget App API",
+			"version": "1",
+		},
+		Paths: map[string]map[string]*openAPIOperationObject{},
+	}
+	schemas := openAPISchemas{components: map[string]*openAPISchema{}, types: map[string]reflect.Type{}}
+
+	for _, route := range routes {
+		op := route.Operation
+		object := &openAPIOperationObject{
+			Summary:     op.Summary,
+			Tags:        []string{op.Tag},
+			OperationID: openAPIOperationID(route.Method, route.Path),
+			Deprecated:  route.Deprecated,
+			Responses:   map[string]openAPIResponse{"default": {Ref: "#/components/responses/Problem"}},
+		}
+		if !op.Public {
+			object.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+
+		for _, uri := range op.URI {
+			object.Parameters = append(object.Parameters, schemas.parameters(uri, "path", "uri")...)
+		}
+		if op.Query != nil {
+			object.Parameters = append(object.Parameters, schemas.parameters(op.Query, "query", "form")...)
+		}
+		if op.IfMatch {
+			object.Parameters = append(object.Parameters, openAPIParameter{
+				Name: "If-Match", In: "header", Schema: &openAPISchema{Type: "string", Description: "ETag of the entry version being changed"},
+			})
+		}
+		if !op.Public && !op.ReadOnly && route.Method == http.MethodPost {
+			object.Parameters = append(object.Parameters, openAPIParameter{
+				Name: idempotencyKeyHeaderKey, In: "header", Schema: &openAPISchema{Type: "string", MaxLength: int64Ptr(maxIdempotencyKeyLength)},
+			})
+		}
+		if op.Body != nil {
+			object.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: schemas.schema(reflect.TypeOf(op.Body))}},
+			}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := openAPIResponse{Description: http.StatusText(status)}
+		if op.Response != nil {
+			mediaType := "application/json"
+			if op.Stream != "" {
+				mediaType = op.Stream
+			}
+			response.Content = map[string]openAPIMediaType{mediaType: {Schema: schemas.schema(reflect.TypeOf(op.Response))}}
+		}
+		if status == http.StatusCreated {
+			response.Headers = map[string]openAPIHeader{"Location": {Description: "URL of the created resource", Schema: &openAPISchema{Type: "string"}}}
+		}
+		if route.Deprecated {
+			response.Headers = map[string]openAPIHeader{
+				"Deprecation": {Description: "When the route was deprecated", Schema: &openAPISchema{Type: "string"}},
+				"Link":        {Description: "The successor-version of the route", Schema: &openAPISchema{Type: "string"}},
+			}
+		}
+		object.Responses[strconv.Itoa(status)] = response
+
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openAPIOperationObject{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = object
+	}
+
+	doc.Components.Schemas = schemas.components
+	doc.Components.Responses = map[string]openAPIResponse{
+		"Problem": {
+			Description: "RFC 7807 problem details",
+			Content:     map[string]openAPIMediaType{problemContentType: {Schema: schemas.schema(reflect.TypeOf(problem{}))}},
+		},
+	}
+	doc.Components.SecuritySchemes = map[string]map[string]interface{}{
+		"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "PASETO"},
+	}
+	return doc
+}
+
+// Derives a unique operation id such as getV1EntriesId from the method and path
+func openAPIOperationID(method string, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// openAPISchemas turns Go types into schemas, named structs become components
+type openAPISchemas struct {
+	components map[string]*openAPISchema
+	types      map[string]reflect.Type
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (s openAPISchemas) schema(t reflect.Type) *openAPISchema {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &openAPISchema{Description: "any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			object := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+			s.addProperties(object, t)
+			return object
+		}
+		return s.component(t)
+	}
+	return &openAPISchema{}
+}
+
+// Returns a reference to the component of a struct, adding it the first time
+func (s openAPISchemas) component(t reflect.Type) *openAPISchema {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	ref := &openAPISchema{Ref: "#/components/schemas/" + string(name)}
+
+	if existing, ok := s.types[string(name)]; ok {
+		if existing != t {
+			panic(fmt.Sprintf("openapi: %s and %s have the same component name", existing, t))
+		}
+		return ref
+	}
+	s.types[string(name)] = t
+
+	object := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+	s.components[string(name)] = object
+	s.addProperties(object, t)
+	sort.Strings(object.Required)
+	return ref
+}
+
+func (s openAPISchemas) addProperties(object *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			s.addProperties(object, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := s.schema(field.Type)
+		if applyBinding(property, field) {
+			object.Required = append(object.Required, name)
+		}
+		object.Properties[name] = property
+	}
+}
+
+// Turns the struct fields with the tag into parameters
+func (s openAPISchemas) parameters(v interface{}, in string, tag string) []openAPIParameter {
+	t := reflect.TypeOf(v)
+	var params []openAPIParameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get(tag)
+		if name == "" {
+			continue
+		}
+
+		schema := s.schema(field.Type)
+		required := applyBinding(schema, field)
+		params = append(params, openAPIParameter{
+			Name:     name,
+			In:       in,
+			Required: required || in == "path",
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+// Adds the binding constraints of the field to its schema and reports whether it is required
+func applyBinding(schema *openAPISchema, field reflect.StructField) bool {
+	binding := field.Tag.Get("binding")
+	if binding == "" {
+		return false
+	}
+
+	if format := field.Tag.Get("time_format"); format == YYYYMMDD {
+		schema.Format = "date"
+	}
+
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		if rule == "dive" {
+			// the following rules apply to the items, structs carry their own
+			break
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		n, _ := strconv.ParseInt(param, 10, 64)
+		switch name {
+		case "required":
+			required = true
+		case "required_unless":
+			unlessField, unlessValue, _ := strings.Cut(param, " ")
+			schema.Description = fmt.Sprintf("required unless %s is %s", strings.ToLower(unlessField), unlessValue)
+		case "min", "max":
+			limit := int64Ptr(n)
+			switch {
+			case schema.Type == "string" && name == "min":
+				schema.MinLength = limit
+			case schema.Type == "string":
+				schema.MaxLength = limit
+			case schema.Type == "array" && name == "min":
+				schema.MinItems = limit
+			case schema.Type == "array":
+				schema.MaxItems = limit
+			case name == "min":
+				schema.Minimum = limit
+			default:
+				schema.Maximum = limit
+			}
+		case "gt":
+			schema.Minimum = int64Ptr(n)
+			schema.ExclusiveMinimum = true
+		case "email":
+			schema.Format = "email"
+		case "alphanum":
+			schema.Pattern = "^[a-zA-Z0-9]+$"
+		case "alphaunicode":
+			schema.Pattern = `^[\p{L}]+$`
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "role":
+			schema.Enum = []string{util.OwnerRole, util.EditorRole, util.ViewerRole}
+		case "split_type":
+			schema.Enum = []string{util.EqualSplit, util.ExactSplit, util.PercentageSplit}
+		}
+	}
+	return required
+}
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func (server *Server) getOpenAPI(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, server.openAPI)
+}
+
+func (server *Server) getDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", swaggerUI)
+}
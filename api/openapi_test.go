@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	server := newTestServer(t, nil)
+
+	registered := map[string]bool{}
+	for _, route := range server.router.Routes() {
+		method := strings.ToLower(route.Method)
+		path := openAPIPath(route.Path)
+		registered[method+" "+path] = true
+
+		require.Contains(t, server.openAPI.Paths, path, "route %s %s is missing from the OpenAPI document", route.Method, route.Path)
+		require.Contains(t, server.openAPI.Paths[path], method, "route %s %s is missing from the OpenAPI document", route.Method, route.Path)
+	}
+
+	for path, operations := range server.openAPI.Paths {
+		for method := range operations {
+			require.True(t, registered[method+" "+path], "documented route %s %s is not registered", method, path)
+		}
+	}
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var doc openAPIDocument
+	err = json.Unmarshal(recorder.Body.Bytes(), &doc)
+	require.NoError(t, err)
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+
+	// binding constraints of the request become schema constraints
+	createUser := doc.Components.Schemas["CreateUserRequest"]
+	require.NotNil(t, createUser)
+	require.Equal(t, []string{"email", "full_name", "password", "username"}, createUser.Required)
+	username := createUser.Properties["username"]
+	require.Equal(t, int64(1), *username.MinLength)
+	require.Equal(t, int64(15), *username.MaxLength)
+	require.Equal(t, "email", createUser.Properties["email"].Format)
+
+	batch := doc.Components.Schemas["BatchEntriesRequest"].Properties["operations"]
+	require.Equal(t, int64(100), *batch.MaxItems)
+	require.Equal(t, "#/components/schemas/EntryOperationRequest", batch.Items.Ref)
+
+	getEntry := doc.Paths["/v1/entries/{id}"]["get"]
+	require.False(t, getEntry.Deprecated)
+	require.Equal(t, "id", getEntry.Parameters[0].Name)
+	require.Equal(t, "path", getEntry.Parameters[0].In)
+	require.Equal(t, "#/components/schemas/Entry", getEntry.Responses["200"].Content["application/json"].Schema.Ref)
+	require.NotEmpty(t, getEntry.Security)
+
+	// responses carrying the user leave out its password hash
+	createEntry := doc.Paths["/v1/entries"]["post"]
+	require.Equal(t, "#/components/schemas/EntryResultResponse", createEntry.Responses["201"].Content["application/json"].Schema.Ref)
+	require.Equal(t, "#/components/schemas/UserResponse", doc.Components.Schemas["EntryResultResponse"].Properties["user"].Ref)
+	require.Equal(t, "#/components/schemas/UserResponse", doc.Components.Schemas["DeleteEntryResponse"].Properties["user"].Ref)
+
+	require.True(t, doc.Paths["/entry/{id}"]["get"].Deprecated)
+	require.Empty(t, doc.Paths["/v1/users/login"]["post"].Security)
+}
+
+func TestGetDocs(t *testing.T) {
+	server := newTestServer(t, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/docs", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), "/openapi.json")
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	rateLimitLimitHeaderKey     = "RateLimit-Limit"
+	rateLimitRemainingHeaderKey = "RateLimit-Remaining"
+	rateLimitResetHeaderKey     = "RateLimit-Reset"
+	retryAfterHeaderKey         = "Retry-After"
+)
+
+// Where the token buckets are kept
+const (
+	MemoryRateLimitStore   = "memory"
+	PostgresRateLimitStore = "postgres"
+)
+
+var errRateLimited = errors.New("too many requests, retry later")
+
+// NewRateLimiter returns the limiter keeping the buckets where config.RateLimitStore says
+func NewRateLimiter(config util.Config, store db.Store) (util.RateLimiter, error) {
+	switch config.RateLimitStore {
+	case "", MemoryRateLimitStore:
+		return util.NewMemoryRateLimiter(), nil
+	case PostgresRateLimitStore:
+		return storeRateLimiter{store: store}, nil
+	}
+	return nil, fmt.Errorf("unsupported rate limit store %q", config.RateLimitStore)
+}
+
+// storeRateLimiter keeps the buckets in the database, so replicas share the quotas
+type storeRateLimiter struct {
+	store db.Store
+}
+
+func (limiter storeRateLimiter) Take(ctx context.Context, key string, limit util.RateLimit) (util.RateLimitResult, error) {
+	arg := db.TakeRateLimitTokenTxParams{
+		Key:   key,
+		Limit: limit,
+		Now:   time.Now(),
+	}
+	return limiter.store.TakeRateLimitTokenTx(ctx, arg)
+}
+
+// Rejects requests over limit with 429. Clients are told apart by their username
+// after authMiddleware and by their IP on public routes. Routes sharing a name
+// share the quota.
+func (server *Server) rateLimit(name string, limit util.RateLimit) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !limit.Enabled() {
+			ctx.Next()
+			return
+		}
+
+		key := name + ":ip:" + ctx.ClientIP()
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			key = name + ":user:" + payload.(*token.Payload).Username
+		}
+
+		result, err := server.rateLimiter.Take(ctx, key, limit)
+		if err != nil {
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.Header(rateLimitLimitHeaderKey, strconv.Itoa(result.Limit))
+		ctx.Header(rateLimitRemainingHeaderKey, strconv.Itoa(result.Remaining))
+		ctx.Header(rateLimitResetHeaderKey, strconv.Itoa(seconds(result.Reset)))
+		if !result.Allowed {
+			ctx.Header(retryAfterHeaderKey, strconv.Itoa(seconds(result.RetryAfter)))
+			abortWithError(ctx, http.StatusTooManyRequests, errRateLimited)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Quota of every route but logging in and resetting passwords
+func (server *Server) defaultRateLimit() gin.HandlerFunc {
+	return server.rateLimit("default", util.RateLimit{
+		Requests: server.config.RateLimitRequests,
+		Period:   server.config.RateLimitPeriod,
+	})
+}
+
+// Stricter quota of the routes that guess passwords or send emails
+func (server *Server) authRateLimit() gin.HandlerFunc {
+	return server.rateLimit("auth", util.RateLimit{
+		Requests: server.config.AuthRateLimitRequests,
+		Period:   server.config.AuthRateLimitPeriod,
+	})
+}
+
+// Rounds d up to whole seconds, as the rate limit headers expect
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/events"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedTestServer(t *testing.T, store db.Store, rateLimitStore string) *Server {
+	config := util.Config{
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		RateLimitStore:        rateLimitStore,
+		RateLimitRequests:     2,
+		RateLimitPeriod:       time.Minute,
+		AuthRateLimitRequests: 1,
+		AuthRateLimitPeriod:   time.Minute,
+	}
+
+	limiter, err := NewRateLimiter(config, store)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, events.NewBroker(), limiter, zerolog.Nop())
+	require.NoError(t, err)
+
+	return server
+}
+
+func TestRateLimitPublicRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newRateLimitedTestServer(t, store, MemoryRateLimitStore)
+
+	serve := func(method string, url string, remoteAddr string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		request.RemoteAddr = remoteAddr
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// logging in has the stricter quota, which the legacy route shares
+	recorder := serve(http.MethodPost, "/v1/users/login", "10.0.0.1:1234")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Equal(t, "1", recorder.Header().Get(rateLimitLimitHeaderKey))
+	require.Equal(t, "0", recorder.Header().Get(rateLimitRemainingHeaderKey))
+	require.Equal(t, "60", recorder.Header().Get(rateLimitResetHeaderKey))
+
+	recorder = serve(http.MethodPost, "/user/login", "10.0.0.1:1234")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "60", recorder.Header().Get(retryAfterHeaderKey))
+	require.Equal(t, errRateLimited.Error(), decodeProblem(t, recorder).Detail)
+
+	// other clients and other routes have their own quota
+	recorder = serve(http.MethodPost, "/v1/users/login", "10.0.0.2:1234")
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = serve(http.MethodGet, "/openapi.json", "10.0.0.1:1234")
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "2", recorder.Header().Get(rateLimitLimitHeaderKey))
+	require.Equal(t, "1", recorder.Header().Get(rateLimitRemainingHeaderKey))
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	login := func(server *Server, remoteAddr string, forwardedFor string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/v1/users/login", nil)
+		require.NoError(t, err)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set("X-Forwarded-For", forwardedFor)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// no proxy is trusted by default, a spoofed header doesn't get a new quota
+	server := newRateLimitedTestServer(t, store, MemoryRateLimitStore)
+	require.Equal(t, http.StatusBadRequest, login(server, "203.0.113.7:1234", "198.51.100.1").Code)
+	require.Equal(t, http.StatusTooManyRequests, login(server, "203.0.113.7:1234", "198.51.100.2").Code)
+
+	// behind a trusted proxy every forwarded client has its own quota
+	config := util.Config{
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		RateLimitStore:        MemoryRateLimitStore,
+		AuthRateLimitRequests: 1,
+		AuthRateLimitPeriod:   time.Minute,
+		TrustedProxies:        []string{"10.0.0.0/8"},
+	}
+	server, err := NewServer(config, store, events.NewBroker(), util.NewMemoryRateLimiter(), zerolog.Nop())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, login(server, "10.0.0.1:1234", "198.51.100.1").Code)
+	require.Equal(t, http.StatusBadRequest, login(server, "10.0.0.1:1234", "198.51.100.2").Code)
+	require.Equal(t, http.StatusTooManyRequests, login(server, "10.0.0.1:1234", "198.51.100.2").Code)
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	user1 := CreateRandomUser()
+	user2 := CreateRandomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhooks(gomock.Any(), gomock.Any()).
+		Times(3).
+		Return([]db.Webhook{}, nil)
+
+	server := newRateLimitedTestServer(t, store, MemoryRateLimitStore)
+
+	serve := func(username string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/v1/webhooks", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	require.Equal(t, http.StatusOK, serve(user1.Username).Code)
+	require.Equal(t, http.StatusOK, serve(user1.Username).Code)
+	require.Equal(t, http.StatusTooManyRequests, serve(user1.Username).Code)
+
+	// the quota follows the user, not the IP they share
+	require.Equal(t, http.StatusOK, serve(user2.Username).Code)
+}
+
+func TestRateLimitPostgresStore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		result        util.RateLimitResult
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Allowed",
+			result: util.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "1", recorder.Header().Get(rateLimitRemainingHeaderKey))
+				require.Equal(t, "30", recorder.Header().Get(rateLimitResetHeaderKey))
+			},
+		},
+		{
+			name:   "Limited",
+			result: util.RateLimitResult{Limit: 2, RetryAfter: 1500 * time.Millisecond, Reset: time.Minute},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "2", recorder.Header().Get(retryAfterHeaderKey))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				TakeRateLimitTokenTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ interface{}, arg db.TakeRateLimitTokenTxParams) (util.RateLimitResult, error) {
+					require.Equal(t, "default:ip:10.0.0.1", arg.Key)
+					require.Equal(t, util.RateLimit{Requests: 2, Period: time.Minute}, arg.Limit)
+					return tc.result, nil
+				})
+
+			server := newRateLimitedTestServer(t, store, PostgresRateLimitStore)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+			require.NoError(t, err)
+			request.RemoteAddr = "10.0.0.1:1234"
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	_, err := NewRateLimiter(util.Config{RateLimitStore: "redis"}, nil)
+	require.Error(t, err)
+}
@@ -1,90 +1,240 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/events"
+	"github.com/LeandroEstevez/budgetAppAPI/graph"
 	"github.com/LeandroEstevez/budgetAppAPI/token"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
 )
 
 // Server serves HTTP requests
 type Server struct {
-	config     util.Config
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
+	config      util.Config
+	store       db.Store
+	tokenMaker  token.Maker
+	router      *gin.Engine
+	openAPI     *openAPIDocument
+	graphQL     *graph.Schema
+	events      *events.Broker
+	rateLimiter util.RateLimiter
+	cors        corsPolicy
+	logger      zerolog.Logger
+	httpServer  *http.Server
+	// sendEmail delivers the emails of the handlers, util.SendEmail unless replaced in tests
+	sendEmail func(data *util.EmailData, templateName string) error
 }
 
-func CORSMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, PATCH, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+// Creates a new HTTP server and setup routing. The events of broker are
+// streamed to the clients of their user, and the quotas are taken from limiter.
+func NewServer(config util.Config, store db.Store, broker *events.Broker, limiter util.RateLimiter, logger zerolog.Logger) (*Server, error) {
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
 
-		c.Next()
+	graphQL, err := graph.NewSchema(store, config.GraphQLMaxDepth, config.GraphQLMaxComplexity)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create graphql schema: %w", err)
 	}
-}
 
-// Creates a new HTTP server and setup routing
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	cors, err := newCORSPolicy(config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create token maker: %w", err)
+		return nil, err
 	}
 
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:      config,
+		store:       store,
+		tokenMaker:  tokenMaker,
+		graphQL:     graphQL,
+		events:      broker,
+		rateLimiter: limiter,
+		cors:        cors,
+		logger:      logger,
+		sendEmail:   util.SendEmail,
+	}
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterValidation("role", validRole)
+		v.RegisterValidation("split_type", validSplitType)
+		v.RegisterValidation("webhook_event", validWebhookEvent)
+		v.RegisterValidation("webhook_url", validWebhookURL)
+		v.RegisterTagNameFunc(requestFieldName)
 	}
 
 	server.setUpRouter()
+	err = server.router.SetTrustedProxies(trustedProxies(config))
+	if err != nil {
+		return nil, fmt.Errorf("cannot set the trusted proxies: %w", err)
+	}
+
+	server.httpServer = &http.Server{Handler: server.router}
 	return server, nil
 }
 
+// Returns the proxies whose X-Forwarded-For header gives the client IP, which
+// rate limits and audit events key on. None by default, anyone could
+// otherwise pick the IP they are seen with.
+func trustedProxies(config util.Config) []string {
+	proxies := []string{}
+	for _, proxy := range config.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func (server *Server) setUpRouter() {
-	router := gin.Default()
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowAllOrigins = true
-	corsConfig.AllowHeaders = []string{"*"}
-	corsConfig.AllowCredentials = true
-	corsConfig.AllowMethods = []string{"GET", "POST", "PATCH", "DELETE"}
-	// router.Use(cors.New(corsConfig))
-	router.Use(CORSMiddleware())
-
-	router.POST("/forgotpassword", server.forgotPassword)
-	router.POST("/user", server.createUser)
-	router.POST("/user/login", server.logInUser)
-
-	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker))
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(tracingMiddleware(), requestIDMiddleware(server.logger), loggerMiddleware(), metricsMiddleware(), recoveryMiddleware())
+	router.Use(corsMiddleware(server.cors))
+	router.Use(auditMiddleware())
+
+	server.openAPI = newOpenAPIDocument(openAPIRoutes())
+	router.GET("/openapi.json", server.defaultRateLimit(), server.getOpenAPI)
+	router.GET("/docs", server.defaultRateLimit(), server.getDocs)
+	router.GET("/healthz", server.getHealth)
+	router.GET("/readyz", server.getReadiness)
+	router.POST("/graphql", authMiddleware(server.tokenMaker), server.defaultRateLimit(), server.executeGraphQL)
+	router.GET("/events", queryTokenMiddleware(), authMiddleware(server.tokenMaker), server.defaultRateLimit(), server.streamEvents)
+
+	server.setUpV1Routes(router.Group("/v1"))
+	server.setUpLegacyRoutes(router.Group("/", deprecationMiddleware(legacySuccessors)))
+
+	server.router = router
+}
+
+func (server *Server) setUpV1Routes(router *gin.RouterGroup) {
+	router.POST("/users", server.defaultRateLimit(), server.createUser)
+	router.POST("/users/login", server.authRateLimit(), server.logInUser)
+	router.POST("/users/restore", server.authRateLimit(), server.restoreUser)
+	router.POST("/users/forgot-password", server.authRateLimit(), server.forgotPassword)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), server.defaultRateLimit(), server.idempotency())
+	authRoutes.GET("/entries", server.listEntries)
+	authRoutes.POST("/entries", server.createEntry)
+	authRoutes.POST("/entries/batch", server.batchEntries)
+	authRoutes.GET("/entries/:id", server.getEntry)
+	authRoutes.PATCH("/entries/:id", server.patchEntry)
+	authRoutes.DELETE("/entries/:id", server.deleteEntry)
+	authRoutes.GET("/categories", server.listCategories)
+
+	authRoutes.GET("/me", server.getMe)
+	authRoutes.PATCH("/me", server.updateMe)
+	authRoutes.DELETE("/me", server.deleteMe)
+	authRoutes.PUT("/me/password", server.resetPassword)
+
+	authRoutes.POST("/webhooks", server.createWebhook)
+	authRoutes.GET("/webhooks", server.listWebhooks)
+	authRoutes.GET("/webhooks/:id", server.getWebhook)
+	authRoutes.DELETE("/webhooks/:id", server.deleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", server.listWebhookDeliveries)
+	authRoutes.POST("/webhooks/:id/test", server.sendTestWebhook)
+
+	server.setUpSharedRoutes(router, authRoutes)
+}
+
+// Routes predating /v1, they answer with Deprecation headers pointing to their v1 successor
+func (server *Server) setUpLegacyRoutes(router *gin.RouterGroup) {
+	router.POST("/forgotpassword", server.authRateLimit(), server.forgotPassword)
+	router.POST("/user", server.defaultRateLimit(), server.createUser)
+	router.POST("/user/login", server.authRateLimit(), server.logInUser)
+	router.POST("/user/restore", server.authRateLimit(), server.restoreUser)
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), server.defaultRateLimit(), server.idempotency())
 	authRoutes.POST("/entry", server.addEntry)
+	authRoutes.GET("/entry/:id", server.getEntry)
 	authRoutes.PATCH("/updateEntry", server.updateEntry)
 	authRoutes.DELETE("/deleteEntry/:id", server.deleteEntry)
 	authRoutes.GET("/entries", server.getEntries)
+	authRoutes.POST("/entries/batch", server.batchEntries)
 	authRoutes.GET("/categories", server.getCategories)
 	authRoutes.DELETE("/deleteUser/:username", server.deleteUser)
 	authRoutes.PATCH("/resetPassword", server.resetPassword)
 	authRoutes.PATCH("/updateAccount", server.updateAccount)
-
 	authRoutes.GET("/user/:username", server.getUser)
 
-	server.router = router
+	server.setUpSharedRoutes(router, authRoutes)
+}
+
+// Legacy routes whose v1 successor is not the same path under /v1
+var legacySuccessors = map[string]string{
+	"/forgotpassword":       "/v1/users/forgot-password",
+	"/user":                 "/v1/users",
+	"/user/login":           "/v1/users/login",
+	"/user/restore":         "/v1/users/restore",
+	"/entry":                "/v1/entries",
+	"/entry/:id":            "/v1/entries/:id",
+	"/updateEntry":          "/v1/entries",
+	"/deleteEntry/:id":      "/v1/entries/:id",
+	"/deleteUser/:username": "/v1/me",
+	"/resetPassword":        "/v1/me/password",
+	"/updateAccount":        "/v1/me",
+	"/user/:username":       "/v1/me",
+}
+
+// Routes that are the same in v1 and the legacy API
+func (server *Server) setUpSharedRoutes(router *gin.RouterGroup, authRoutes gin.IRoutes) {
+	authRoutes.GET("/audit", server.listAuditEvents)
+	authRoutes.GET("/trash", server.getTrash)
+	authRoutes.POST("/trash/:id/restore", server.restoreEntry)
+
+	authRoutes.POST("/households", server.createHousehold)
+	authRoutes.GET("/households", server.listHouseholds)
+	authRoutes.GET("/invitations", server.listHouseholdInvitations)
+	authRoutes.POST("/invitations/:id/accept", server.acceptHouseholdInvitation)
+
+	authRoutes.POST("/splits", server.splitExpense)
+	authRoutes.GET("/balances", server.getBalances)
+	authRoutes.POST("/settlements", server.settleUp)
+	authRoutes.GET("/settlements", server.getSettlements)
+	authRoutes.GET("/settlements/suggestions", server.suggestSettlements)
+
+	householdRoutes := router.Group("/households/:household_id").Use(authMiddleware(server.tokenMaker), server.defaultRateLimit(), server.idempotency())
+	householdRoutes.GET("", householdAuthorization(server.store, util.ViewerRole), server.getHousehold)
+	householdRoutes.GET("/entries", householdAuthorization(server.store, util.ViewerRole), server.getHouseholdEntries)
+	householdRoutes.POST("/entries", householdAuthorization(server.store, util.EditorRole), server.addHouseholdEntry)
+	householdRoutes.PATCH("/entries", householdAuthorization(server.store, util.EditorRole), server.updateHouseholdEntry)
+	householdRoutes.DELETE("/entries/:id", householdAuthorization(server.store, util.EditorRole), server.deleteHouseholdEntry)
+	householdRoutes.GET("/trash", householdAuthorization(server.store, util.ViewerRole), server.getHouseholdTrash)
+	householdRoutes.POST("/trash/:id/restore", householdAuthorization(server.store, util.EditorRole), server.restoreHouseholdEntry)
+	householdRoutes.GET("/members", householdAuthorization(server.store, util.ViewerRole), server.listHouseholdMembers)
+	householdRoutes.PATCH("/members/:username", householdAuthorization(server.store, util.OwnerRole), server.updateHouseholdMember)
+	householdRoutes.DELETE("/members/:username", householdAuthorization(server.store, util.OwnerRole), server.removeHouseholdMember)
+	householdRoutes.POST("/invitations", householdAuthorization(server.store, util.OwnerRole), server.inviteHouseholdMember)
+}
+
+func (server *Server) idempotency() gin.HandlerFunc {
+	return idempotencyMiddleware(server.store, server.config.IdempotencyKeyTTL)
 }
 
-// Runs the HTTP server on a specific address
+// Runs the HTTP server on a specific address until Shutdown is called
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	server.httpServer.Addr = address
+	err := server.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// Stops accepting connections and waits for the running requests to finish
+// until ctx is done. The event streams are closed so they don't hold it up.
+func (server *Server) Shutdown(ctx context.Context) error {
+	server.events.Close()
+	return server.httpServer.Shutdown(ctx)
 }
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+type expenseShareRequest struct {
+	Username string `json:"username" binding:"required,alphanum,min=1,max=15"`
+	Value    int64  `json:"value" binding:"min=0"`
+}
+
+type splitExpenseRequest struct {
+	EntryID   int32                 `json:"entry_id" binding:"required,gt=0"`
+	SplitType string                `json:"split_type" binding:"required,split_type"`
+	Shares    []expenseShareRequest `json:"shares" binding:"required,min=1,dive"`
+}
+
+func (server *Server) splitExpense(ctx *gin.Context) {
+	var req splitExpenseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.SplitExpenseTxParams{
+		Username:  authPayload.Username,
+		EntryID:   req.EntryID,
+		SplitType: req.SplitType,
+		Shares:    make([]db.ExpenseShareParams, len(req.Shares)),
+	}
+	for i, share := range req.Shares {
+		arg.Shares[i] = db.ExpenseShareParams{
+			Username: share.Username,
+			Value:    share.Value,
+		}
+	}
+
+	result, err := server.store.SplitExpenseTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidSplit) || errors.Is(err, db.ErrShareNotHouseholdMember) {
+			writeError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+type balanceResponse struct {
+	Username string `json:"username"`
+	Amount   int64  `json:"amount"`
+}
+
+// Balances from the point of view of username: a positive amount means the
+// other user owes username, a negative one that username owes the other user.
+func newBalanceResponses(username string, balances []db.Balance) []balanceResponse {
+	rsp := make([]balanceResponse, len(balances))
+	for i, balance := range balances {
+		if balance.UserA == username {
+			rsp[i] = balanceResponse{Username: balance.UserB, Amount: balance.Amount}
+		} else {
+			rsp[i] = balanceResponse{Username: balance.UserA, Amount: -balance.Amount}
+		}
+	}
+	return rsp
+}
+
+func (server *Server) getBalances(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	balances, err := server.store.ListBalances(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBalanceResponses(authPayload.Username, balances))
+}
+
+type settleUpRequest struct {
+	ToUsername string `json:"to_username" binding:"required,alphanum,min=1,max=15"`
+	Amount     int64  `json:"amount" binding:"required,gt=0"`
+}
+
+func (server *Server) settleUp(ctx *gin.Context) {
+	var req settleUpRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.ToUsername == authPayload.Username {
+		err := errors.New("cannot settle up with yourself")
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	arg := db.SettleUpTxParams{
+		FromUsername: authPayload.Username,
+		ToUsername:   req.ToUsername,
+		Amount:       req.Amount,
+	}
+
+	result, err := server.store.SettleUpTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrShareNotHouseholdMember) || errors.Is(err, db.ErrSettlementExceedsDebt) {
+			writeError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) getSettlements(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	settlements, err := server.store.ListSettlements(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settlements)
+}
+
+// Suggests the fewest transfers that settle every balance between the
+// user and the people the user shares expenses with
+func (server *Server) suggestSettlements(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	balances, err := server.store.ListBalances(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	usernames := []string{authPayload.Username}
+	for _, balance := range newBalanceResponses(authPayload.Username, balances) {
+		usernames = append(usernames, balance.Username)
+	}
+
+	groupBalances, err := server.store.ListBalancesAmong(ctx, usernames)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	net := map[string]int64{}
+	for _, balance := range groupBalances {
+		net[balance.UserA] += balance.Amount
+		net[balance.UserB] -= balance.Amount
+	}
+
+	ctx.JSON(http.StatusOK, util.MinimizeTransfers(net))
+}
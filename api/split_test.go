@@ -0,0 +1,334 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitExpense(t *testing.T) {
+	payer := CreateRandomUser()
+	friend := CreateRandomUser()
+	entry := createRandomEntry(payer)
+
+	arg := db.SplitExpenseTxParams{
+		Username:  payer.Username,
+		EntryID:   entry.ID,
+		SplitType: util.EqualSplit,
+		Shares: []db.ExpenseShareParams{
+			{Username: payer.Username},
+			{Username: friend.Username},
+		},
+	}
+
+	body := gin.H{
+		"entry_id":   entry.ID,
+		"split_type": util.EqualSplit,
+		"shares": []gin.H{
+			{"username": payer.Username},
+			{"username": friend.Username},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SplitExpenseTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedSplitType",
+			body: gin.H{
+				"entry_id":   entry.ID,
+				"split_type": "random",
+				"shares":     []gin.H{{"username": friend.Username}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoShares",
+			body: gin.H{
+				"entry_id":   entry.ID,
+				"split_type": util.EqualSplit,
+				"shares":     []gin.H{},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSplit",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SplitExpenseTxResult{}, util.ErrInvalidSplit)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotHouseholdMember",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SplitExpenseTxResult{}, db.ErrShareNotHouseholdMember)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), codeNotHouseholdMember)
+			},
+		},
+		{
+			name: "EntryNotFound",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SplitExpenseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SplitExpenseTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/splits", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	debtor := CreateRandomUser()
+	creditor := CreateRandomUser()
+
+	arg := db.SettleUpTxParams{
+		FromUsername: debtor.Username,
+		ToUsername:   creditor.Username,
+		Amount:       10,
+	}
+
+	body := gin.H{
+		"to_username": creditor.Username,
+		"amount":      10,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SettleUpTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithYourself",
+			body: gin.H{
+				"to_username": debtor.Username,
+				"amount":      10,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotHouseholdMember",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SettleUpTxResult{}, db.ErrShareNotHouseholdMember)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), codeNotHouseholdMember)
+			},
+		},
+		{
+			name: "ExceedsDebt",
+			body: body,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleUpTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.SettleUpTxResult{}, db.ErrSettlementExceedsDebt)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), codeSettlementExceedsDebt)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/settlements", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, debtor.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	balances := []db.Balance{
+		{UserA: "alice", UserB: "bob", Amount: 30},
+		{UserA: "bob", UserB: "carol", Amount: 10},
+	}
+	store.EXPECT().
+		ListBalances(gomock.Any(), gomock.Eq("bob")).
+		Times(1).
+		Return(balances, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/balances", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "bob", time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var gotBalances []balanceResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &gotBalances)
+	require.NoError(t, err)
+	require.Equal(t, []balanceResponse{
+		{Username: "alice", Amount: -30},
+		{Username: "carol", Amount: 10},
+	}, gotBalances)
+}
+
+func TestSuggestSettlements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	balances := []db.Balance{
+		{UserA: "alice", UserB: "bob", Amount: 30},
+		{UserA: "bob", UserB: "carol", Amount: 30},
+	}
+	store.EXPECT().
+		ListBalances(gomock.Any(), gomock.Eq("bob")).
+		Times(1).
+		Return(balances, nil)
+	store.EXPECT().
+		ListBalancesAmong(gomock.Any(), gomock.Eq([]string{"bob", "alice", "carol"})).
+		Times(1).
+		Return(balances, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/settlements/suggestions", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "bob", time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// bob's debt to alice and carol's debt to bob cancel out into a single transfer
+	var transfers []util.Transfer
+	err = json.Unmarshal(recorder.Body.Bytes(), &transfers)
+	require.NoError(t, err)
+	require.Equal(t, []util.Transfer{{From: "carol", To: "alice", Amount: 30}}, transfers)
+}
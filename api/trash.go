@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+func (server *Server) getTrash(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	entries, err := server.store.ListDeletedEntries(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+type restoreEntryRequest struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+func (server *Server) restoreEntry(ctx *gin.Context) {
+	var req restoreEntryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.RestoreEntryTxParams{
+		Username: authPayload.Username,
+		ID:       req.ID,
+	}
+
+	result, err := server.store.RestoreEntryTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setEntryETag(ctx, result.Entry)
+	rsp := entryResultResponse{
+		Entry: result.Entry,
+		User:  newUserResponse(result.User),
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type restoreUserRequest struct {
+	Username string `json:"username" binding:"required,alphanum,min=6,max=10"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// Restores a deleted account that has not been purged yet, the user proves
+// ownership with the account's password since deleted users can't log in
+func (server *Server) restoreUser(ctx *gin.Context) {
+	var req restoreUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := server.store.GetDeletedUser(ctx, req.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = util.CheckPassword(req.Password, user.HashedPassword)
+	if err != nil {
+		writeError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	user, err = server.store.RestoreUserTx(ctx, user.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
@@ -3,15 +3,14 @@ package api
 import (
 	"database/sql"
 	"errors"
-	"fmt"
 	"net/http"
 	"strings"
 
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
 	"github.com/LeandroEstevez/budgetAppAPI/token"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
 type createUserRequest struct {
@@ -47,13 +46,13 @@ func (server *Server) createUser(ctx *gin.Context) {
 
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	hasedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -65,21 +64,15 @@ func (server *Server) createUser(ctx *gin.Context) {
 		TotalExpenses:  0,
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	user, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
-		if pqError, ok := err.(*pq.Error); ok {
-			if pqError.Code.Name() == "users_pkey" {
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -101,24 +94,20 @@ type getUserRequest struct {
 func (server *Server) getUser(ctx *gin.Context) {
 	var req getUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	if user.Username != authPayload.Username {
 		err := errors.New("account doesn't belong to the authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		writeError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -139,29 +128,29 @@ type logInUserResponse struct {
 func (server *Server) logInUser(ctx *gin.Context) {
 	var req logInUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
+		if errors.Is(err, sql.ErrNoRows) {
+			metrics.LoginsFailed.WithLabelValues(metrics.LoginUnknownUser).Inc()
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		metrics.LoginsFailed.WithLabelValues(metrics.LoginWrongPassword).Inc()
+		writeError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
 	accessToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -194,7 +183,7 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 
 	var req deleteUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -202,13 +191,7 @@ func (server *Server) deleteUser(ctx *gin.Context) {
 
 	err := server.store.DeleteUserTx(ctx, authPayload.Username)
 	if err != nil {
-		if pqError, ok := err.(*pq.Error); ok {
-			if pqError.Code.Name() == "users_pkey" {
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -222,32 +205,22 @@ type forgotPasswordRequest struct {
 func (server *Server) forgotPassword(ctx *gin.Context) {
 	var req forgotPasswordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	fmt.Println("Binding json request")
-
 	user, err := server.store.GetEmail(ctx, req.Username)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	fmt.Println("Got User from DB", user)
-
 	resetToken, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	fmt.Println("Made the token")
-
 	var firstName = user.FullName
 	if strings.Contains(firstName, " ") {
 		firstName = strings.Split(firstName, " ")[1]
@@ -255,20 +228,20 @@ func (server *Server) forgotPassword(ctx *gin.Context) {
 
 	// ? Send Email
 	emailData := util.EmailData{
-		URL:       "http://localhost:3001" + "/resetpassword/" + resetToken,
+		URL:       server.config.FrontendURL + "/resetpassword/" + resetToken,
 		FirstName: firstName,
 		Subject:   "Your password reset token (valid for 15min)",
 		ToEmail:   user.Email,
 	}
 
-	fmt.Println("Trying to send the email")
-
-	err = util.SendEmail(&emailData, "resetPassword.html")
+	err = server.sendEmail(&emailData, "resetPassword.html")
 	if err != nil {
-		ctx.JSON(http.StatusBadGateway, gin.H{"status": "success", "message": "There was an error sending email"})
+		writeError(ctx, http.StatusBadGateway, err)
 		return
 	}
 
+	requestLogger(ctx).Info().Str("username", user.Username).Msg("sent password reset email")
+
 	ctx.JSON(http.StatusOK, "You will receive a reset email if username exists")
 }
 
@@ -279,39 +252,30 @@ type resetPasswordRequest struct {
 func (server *Server) resetPassword(ctx *gin.Context) {
 	var req resetPasswordRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
 		return
 	}
-	fmt.Println("got the req")
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	fmt.Println("got the payload")
 
 	hasedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
-	fmt.Println("hashed the password")
 
 	arg := db.ResetPasswordParams{
 		Username:       authPayload.Username,
 		HashedPassword: hasedPassword,
 	}
 
-	fmt.Println("got the arg", arg)
-
-	err = server.store.ResetPassword(ctx, arg)
+	err = server.store.ResetPasswordTx(ctx, arg)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	fmt.Println("changed the password")
+	requestLogger(ctx).Info().Str("username", authPayload.Username).Msg("changed password")
 
 	ctx.JSON(http.StatusOK, "Password data updated successfully")
 }
@@ -326,7 +290,14 @@ type updateAccountRequest struct {
 func (server *Server) updateAccount(ctx *gin.Context) {
 	var req updateAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.OrigUsername != authPayload.Username {
+		err := errors.New("account doesn't belong to the authenticated user")
+		writeError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -338,7 +309,7 @@ func (server *Server) updateAccount(ctx *gin.Context) {
 	}
 	updateUserResult, err := server.store.UpdateAccountTx(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		writeError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -182,7 +182,7 @@ func TestCreateUser(t *testing.T) {
 					Email: user.Email,
 				}
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParams(arg2, user.HashedPassword)).
+					CreateUserTx(gomock.Any(), EqCreateUserParams(arg2, user.HashedPassword)).
 					Times(1).
 					Return(user, nil)
 			},
@@ -195,7 +195,7 @@ func TestCreateUser(t *testing.T) {
 		{
 			name: "InvalidUsername",
 			body: gin.H{
-				"username":  "xyz",
+				"username":  "xyz!",
 				"password":  user.HashedPassword,
 				"full_name": user.FullName,
 				"email":     user.Email,
@@ -203,7 +203,7 @@ func TestCreateUser(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				// build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -222,7 +222,7 @@ func TestCreateUser(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				// build stub
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.User{}, sql.ErrConnDone)
 			},
@@ -280,4 +280,70 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	require.Equal(t, user.Username, gotUser.Username)
 	require.Equal(t, user.FullName, gotUser.FullName)
 	require.Equal(t, user.Email, gotUser.Email)
-}
\ No newline at end of file
+}
+func TestUpdateAccount(t *testing.T) {
+	user := CreateRandomUser()
+
+	testCases := []struct {
+		name          string
+		origUsername  string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "OK",
+			origUsername: user.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateAccountTxResult{User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:         "OtherAccount",
+			origUsername: "someone",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"origusername": tc.origUsername,
+				"username":     user.Username,
+				"full_name":    user.FullName,
+				"email":        user.Email,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/updateAccount", bytes.NewBuffer(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers of the v1 routes whose legacy counterparts take the username from the
+// request. In v1 the user is always the one of the access token.
+
+func (server *Server) listEntries(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	entries, err := server.store.GetEntries(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+type entryRequest struct {
+	Name     string `json:"name" binding:"required,alphanum,min=1"`
+	DueDate  string `json:"due_date" binding:"required" time_format:"2006-01-02"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Category string `json:"category" binding:"max=15"`
+}
+
+func (server *Server) createEntry(ctx *gin.Context) {
+	var req entryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
+	if err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.AddEntryTxParams{
+		Username: authPayload.Username,
+		Name:     req.Name,
+		DueDate:  dueDate,
+		Amount:   req.Amount,
+		Category: req.Category,
+	}
+
+	entryResult, err := server.store.AddEntryTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	setEntryETag(ctx, entryResult.Entry)
+	ctx.Header("Location", fmt.Sprintf("/v1/entries/%d", entryResult.Entry.ID))
+	rsp := entryResultResponse{
+		Entry: entryResult.Entry,
+		User:  newUserResponse(entryResult.User),
+	}
+	ctx.JSON(http.StatusCreated, rsp)
+}
+
+type patchEntryURI struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+type patchEntryRequest struct {
+	entryRequest
+	Version int32 `json:"version" binding:"omitempty,gt=0"`
+}
+
+func (server *Server) patchEntry(ctx *gin.Context) {
+	var uri patchEntryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req patchEntryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	dueDate, err := time.Parse(YYYYMMDD, req.DueDate)
+	if err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.UpdateEntryTxParams{
+		Username: authPayload.Username,
+		ID:       uri.ID,
+		Name:     req.Name,
+		DueDate:  dueDate,
+		Amount:   req.Amount,
+		Category: req.Category,
+	}
+
+	updateEntryResult, ok := server.runUpdateEntryTx(ctx, arg, req.Version)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updateEntryResult.Entry)
+}
+
+func (server *Server) listCategories(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	categories, err := server.store.GetCategories(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, categories)
+}
+
+func (server *Server) getMe(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+type updateMeRequest struct {
+	Username string `json:"username" binding:"required,alphanum,min=1,max=15"`
+	FullName string `json:"full_name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+func (server *Server) updateMe(ctx *gin.Context) {
+	var req updateMeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.UpdateAccountTxParams{
+		OrigUsername: authPayload.Username,
+		Username:     req.Username,
+		FullName:     req.FullName,
+		Email:        req.Email,
+	}
+
+	result, err := server.store.UpdateAccountTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(result.User))
+}
+
+func (server *Server) deleteMe(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	err := server.store.DeleteUserTx(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
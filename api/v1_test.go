@@ -0,0 +1,348 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV1Entries(t *testing.T) {
+	user := CreateRandomUser()
+	entry := createRandomEntry(user)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/v1/entries",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEntries(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]db.Entry{entry}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Deprecation"))
+
+				var gotEntries []db.Entry
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotEntries)
+				require.NoError(t, err)
+				require.Len(t, gotEntries, 1)
+				require.Equal(t, entry.ID, gotEntries[0].ID)
+			},
+		},
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    "/v1/entries",
+			body: gin.H{
+				"name":     entry.Name,
+				"due_date": "2022-12-11",
+				"amount":   entry.Amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.AddEntryTxParams{
+					Username: user.Username,
+					Name:     entry.Name,
+					DueDate:  entry.DueDate,
+					Amount:   entry.Amount,
+				}
+				store.EXPECT().
+					AddEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AddEntryTxResult{Entry: entry, User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, fmt.Sprintf("/v1/entries/%d", entry.ID), recorder.Header().Get("Location"))
+				require.Equal(t, entryETag(entry.Version), recorder.Header().Get("ETag"))
+
+				var rsp entryResultResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, entry.ID, rsp.Entry.ID)
+				require.Equal(t, user.TotalExpenses, rsp.User.TotalExpenses)
+				require.NotContains(t, recorder.Body.String(), "hashed_password")
+			},
+		},
+		{
+			name:   "CreateInvalidDate",
+			method: http.MethodPost,
+			url:    "/v1/entries",
+			body: gin.H{
+				"name":     entry.Name,
+				"due_date": "2008-14-14",
+				"amount":   entry.Amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "Patch",
+			method: http.MethodPatch,
+			url:    fmt.Sprintf("/v1/entries/%d", entry.ID),
+			body: gin.H{
+				"name":     entry.Name,
+				"due_date": "2022-12-11",
+				"amount":   entry.Amount + 1,
+				"version":  entry.Version,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateEntryTxParams{
+					Username: user.Username,
+					ID:       entry.ID,
+					Name:     entry.Name,
+					DueDate:  entry.DueDate,
+					Amount:   entry.Amount + 1,
+					Version:  entry.Version,
+				}
+				updated := entry
+				updated.Amount++
+				updated.Version++
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateEntryTxResult{Entry: updated, User: user}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, entryETag(entry.Version+1), recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name:   "PatchWithoutVersion",
+			method: http.MethodPatch,
+			url:    fmt.Sprintf("/v1/entries/%d", entry.ID),
+			body: gin.H{
+				"name":     entry.Name,
+				"due_date": "2022-12-11",
+				"amount":   entry.Amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+			},
+		},
+		{
+			name:   "Categories",
+			method: http.MethodGet,
+			url:    "/v1/categories",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCategories(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return([]sql.NullString{{String: "food", Valid: true}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				err := json.NewEncoder(&body).Encode(tc.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestV1Me(t *testing.T) {
+	user := CreateRandomUser()
+
+	testCases := []struct {
+		name          string
+		method        string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+				require.NotContains(t, recorder.Body.String(), "hashed_password")
+			},
+		},
+		{
+			name:   "Update",
+			method: http.MethodPatch,
+			body: gin.H{
+				"username":  "newname1",
+				"full_name": user.FullName,
+				"email":     user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateAccountTxParams{
+					OrigUsername: user.Username,
+					Username:     "newname1",
+					FullName:     user.FullName,
+					Email:        user.Email,
+				}
+				updated := user
+				updated.Username = "newname1"
+				store.EXPECT().
+					UpdateAccountTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateAccountTxResult{User: updated}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "UpdateShortUsername",
+			method: http.MethodPatch,
+			body: gin.H{
+				"username":  "ab",
+				"full_name": user.FullName,
+				"email":     user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := user
+				updated.Username = "ab"
+				store.EXPECT().
+					UpdateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateAccountTxResult{User: updated}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteUserTx(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				err := json.NewEncoder(&body).Encode(tc.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(tc.method, "/v1/me", &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestLegacyRoutesDeprecation(t *testing.T) {
+	user := CreateRandomUser()
+	entry := createRandomEntry(user)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetEntry(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(entry, nil)
+
+	server := newTestServer(t, store)
+
+	// renamed route
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/entry/%d", entry.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, fmt.Sprintf("@%d", legacyRoutesDeprecatedAt.Unix()), recorder.Header().Get("Deprecation"))
+	require.Equal(t, fmt.Sprintf(`</v1/entries/%d>; rel="successor-version"`, entry.ID), recorder.Header().Get("Link"))
+
+	// route kept as is under /v1, rejected before reaching a handler
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/trash", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, `</v1/trash>; rel="successor-version"`, recorder.Header().Get("Link"))
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/go-playground/validator/v10"
+)
+
+var validRole validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if role, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedRole(role)
+	}
+	return false
+}
+
+var validSplitType validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if splitType, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedSplitType(splitType)
+	}
+	return false
+}
+
+var validWebhookEvent validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if event, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedWebhookEvent(event)
+	}
+	return false
+}
+
+var validWebhookURL validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if rawURL, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsWebhookURL(rawURL)
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+)
+
+var errWebhookBudgetRequired = errors.New("a budget is required to subscribe to " + util.BudgetExceededEvent)
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2048,webhook_url"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,webhook_event"`
+	Budget     int64    `json:"budget" binding:"omitempty,gt=0"`
+}
+
+// webhookResponse leaves out the secret, which is only shown when the webhook is created
+type webhookResponse struct {
+	ID         int32     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Budget     int64     `json:"budget"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(webhook db.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:         webhook.ID,
+		URL:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		Budget:     webhook.Budget,
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+func (server *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Budget == 0 {
+		for _, eventType := range req.EventTypes {
+			if eventType == util.BudgetExceededEvent {
+				writeError(ctx, http.StatusBadRequest, errWebhookBudgetRequired)
+				return
+			}
+		}
+	}
+
+	secret, err := util.NewWebhookSecret()
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.CreateWebhookParams{
+		Owner:      authPayload.Username,
+		Url:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Budget:     req.Budget,
+	}
+
+	webhook, err := server.store.CreateWebhookTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Header("Location", fmt.Sprintf("/v1/webhooks/%d", webhook.ID))
+	rsp := createWebhookResponse{
+		webhookResponse: newWebhookResponse(webhook),
+		Secret:          webhook.Secret,
+	}
+	ctx.JSON(http.StatusCreated, rsp)
+}
+
+func (server *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	webhooks, err := server.store.ListWebhooks(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := make([]webhookResponse, len(webhooks))
+	for i, webhook := range webhooks {
+		rsp[i] = newWebhookResponse(webhook)
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type webhookRequest struct {
+	ID int32 `uri:"id" binding:"required,gt=0"`
+}
+
+func (server *Server) getWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, ok := server.getOwnWebhook(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookResponse(webhook))
+}
+
+func (server *Server) deleteWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.DeleteWebhookParams{
+		Owner: authPayload.Username,
+		ID:    req.ID,
+	}
+
+	err := server.store.DeleteWebhookTx(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, fmt.Errorf("webhook %d: %w", req.ID, err))
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+type listWebhookDeliveriesQuery struct {
+	PageID   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=50"`
+}
+
+func (server *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var query listWebhookDeliveriesQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, ok := server.getOwnWebhook(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	arg := db.ListWebhookDeliveriesParams{
+		WebhookID: webhook.ID,
+		Limit:     query.PageSize,
+		Offset:    (query.PageID - 1) * query.PageSize,
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// Queues a webhook.test delivery so users can check their endpoint and signature verification
+func (server *Server) sendTestWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		writeError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, ok := server.getOwnWebhook(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(gin.H{"webhook_id": webhook.ID, "sent_at": now})
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	arg := db.CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		EventType: util.WebhookTestEvent,
+		DedupeKey: fmt.Sprintf("test:%d", now.UnixNano()),
+		Payload:   payload,
+	}
+
+	delivery, err := server.store.CreateWebhookDelivery(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+// Returns the webhook of the authenticated user, responding with the error when it can't
+func (server *Server) getOwnWebhook(ctx *gin.Context, id int32) (db.Webhook, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.GetWebhookParams{
+		Owner: authPayload.Username,
+		ID:    id,
+	}
+
+	webhook, err := server.store.GetWebhook(ctx, arg)
+	if err != nil {
+		writeError(ctx, http.StatusInternalServerError, err)
+		return webhook, false
+	}
+
+	return webhook, true
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	user := CreateRandomUser()
+	webhook := createRandomWebhook(user)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"url":         webhook.Url,
+				"event_types": webhook.EventTypes,
+				"budget":      webhook.Budget,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, user.Username, arg.Owner)
+						require.Equal(t, webhook.Url, arg.Url)
+						require.Equal(t, webhook.EventTypes, arg.EventTypes)
+						require.Len(t, arg.Secret, 64)
+						return webhook, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+				require.Equal(t, fmt.Sprintf("/v1/webhooks/%d", webhook.ID), recorder.Header().Get("Location"))
+
+				var rsp createWebhookResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, webhook.ID, rsp.ID)
+				require.Equal(t, webhook.Secret, rsp.Secret)
+			},
+		},
+		{
+			name: "UnsupportedEvent",
+			body: gin.H{
+				"url":         webhook.Url,
+				"event_types": []string{util.WebhookTestEvent},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				p := decodeProblem(t, recorder)
+				require.Equal(t, codeValidationFailed, p.Code)
+				require.Equal(t, "event_types[0]", p.Errors[0].Field)
+				require.Equal(t, "webhook_event", p.Errors[0].Code)
+			},
+		},
+		{
+			name: "InvalidURL",
+			body: gin.H{
+				"url":         "not a url",
+				"event_types": []string{util.EntryCreatedEvent},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalURL",
+			body: gin.H{
+				"url":         "http://169.254.169.254/latest/meta-data",
+				"event_types": []string{util.EntryCreatedEvent},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				p := decodeProblem(t, recorder)
+				require.Equal(t, "url", p.Errors[0].Field)
+				require.Equal(t, "webhook_url", p.Errors[0].Code)
+			},
+		},
+		{
+			name: "BudgetRequired",
+			body: gin.H{
+				"url":         webhook.Url,
+				"event_types": []string{util.BudgetExceededEvent},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, errWebhookBudgetRequired.Error(), decodeProblem(t, recorder).Detail)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"url":         webhook.Url,
+				"event_types": webhook.EventTypes,
+				"budget":      webhook.Budget,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Webhook{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/v1/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListWebhooksHidesSecret(t *testing.T) {
+	user := CreateRandomUser()
+	webhook := createRandomWebhook(user)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListWebhooks(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return([]db.Webhook{webhook}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/webhooks", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), webhook.Secret)
+	require.NotContains(t, recorder.Body.String(), "secret")
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	user := CreateRandomUser()
+	webhook := createRandomWebhook(user)
+
+	testCases := []struct {
+		name          string
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			err:  sql.ErrNoRows,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			arg := db.DeleteWebhookParams{
+				Owner: user.Username,
+				ID:    webhook.ID,
+			}
+			store.EXPECT().
+				DeleteWebhookTx(gomock.Any(), gomock.Eq(arg)).
+				Times(1).
+				Return(tc.err)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/v1/webhooks/%d", webhook.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSendTestWebhook(t *testing.T) {
+	user := CreateRandomUser()
+	webhook := createRandomWebhook(user)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(db.GetWebhookParams{Owner: user.Username, ID: webhook.ID})).
+					Times(1).
+					Return(webhook, nil)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+						require.Equal(t, webhook.ID, arg.WebhookID)
+						require.Equal(t, util.WebhookTestEvent, arg.EventType)
+						return db.WebhookDelivery{ID: 1, WebhookID: arg.WebhookID, EventType: arg.EventType, Payload: arg.Payload, Status: "pending"}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var delivery db.WebhookDelivery
+				err := json.Unmarshal(recorder.Body.Bytes(), &delivery)
+				require.NoError(t, err)
+				require.Equal(t, util.WebhookTestEvent, delivery.EventType)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Webhook{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/v1/webhooks/%d/test", webhook.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func createRandomWebhook(user db.User) db.Webhook {
+	return db.Webhook{
+		ID:         int32(util.RandomInt(1, 1000)),
+		Owner:      user.Username,
+		Url:        "https://example.com/" + util.RandomString(6),
+		Secret:     util.RandomString(64),
+		EventTypes: []string{util.EntryCreatedEvent, util.BudgetExceededEvent},
+		Budget:     util.RandomInt(1, 1000),
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,6 @@
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "created_by";
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "household_id";
+
+DROP TABLE IF EXISTS household_invitations;
+DROP TABLE IF EXISTS household_members;
+DROP TABLE IF EXISTS households;
@@ -0,0 +1,48 @@
+CREATE TABLE "households" (
+  "id" SERIAL PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "household_members" (
+  "household_id" integer NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("household_id", "username")
+);
+
+CREATE TABLE "household_invitations" (
+  "id" SERIAL PRIMARY KEY,
+  "household_id" integer NOT NULL,
+  "email" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "invited_by" varchar NOT NULL,
+  "accepted" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "entries" ADD COLUMN "household_id" integer;
+ALTER TABLE "entries" ADD COLUMN "created_by" varchar;
+
+CREATE INDEX ON "household_members" ("username");
+
+CREATE INDEX ON "household_invitations" ("email");
+
+CREATE INDEX ON "entries" ("household_id");
+
+COMMENT ON COLUMN "household_members"."role" IS 'owner, editor or viewer';
+
+COMMENT ON COLUMN "entries"."created_by" IS 'member who created the entry';
+
+ALTER TABLE "household_members" ADD CONSTRAINT "household_members_role_check" CHECK ("role" IN ('owner', 'editor', 'viewer'));
+
+ALTER TABLE "household_invitations" ADD CONSTRAINT "household_invitations_role_check" CHECK ("role" IN ('owner', 'editor', 'viewer'));
+
+ALTER TABLE "household_members" ADD FOREIGN KEY ("household_id") REFERENCES "households" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "household_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "household_invitations" ADD FOREIGN KEY ("household_id") REFERENCES "households" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("household_id") REFERENCES "households" ("id") ON DELETE CASCADE;
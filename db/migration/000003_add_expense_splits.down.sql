@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS settlements;
+DROP TABLE IF EXISTS balances;
+DROP TABLE IF EXISTS expense_shares;
+DROP TABLE IF EXISTS shared_expenses;
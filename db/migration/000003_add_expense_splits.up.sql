@@ -0,0 +1,68 @@
+CREATE TABLE "shared_expenses" (
+  "id" SERIAL PRIMARY KEY,
+  "entry_id" integer UNIQUE NOT NULL,
+  "paid_by" varchar NOT NULL,
+  "split_type" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "expense_shares" (
+  "expense_id" integer NOT NULL,
+  "username" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  PRIMARY KEY ("expense_id", "username")
+);
+
+CREATE TABLE "balances" (
+  "user_a" varchar NOT NULL,
+  "user_b" varchar NOT NULL,
+  "amount" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("user_a", "user_b")
+);
+
+CREATE TABLE "settlements" (
+  "id" SERIAL PRIMARY KEY,
+  "from_username" varchar NOT NULL,
+  "to_username" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "expense_shares" ("username");
+
+CREATE INDEX ON "balances" ("user_b");
+
+CREATE INDEX ON "settlements" ("from_username");
+
+CREATE INDEX ON "settlements" ("to_username");
+
+COMMENT ON COLUMN "shared_expenses"."split_type" IS 'equal, exact or percentage';
+
+COMMENT ON COLUMN "expense_shares"."amount" IS 'part of the entry amount owed by the user';
+
+COMMENT ON COLUMN "balances"."amount" IS 'positive when user_b owes user_a';
+
+COMMENT ON COLUMN "settlements"."amount" IS 'must be positive';
+
+ALTER TABLE "shared_expenses" ADD CONSTRAINT "shared_expenses_split_type_check" CHECK ("split_type" IN ('equal', 'exact', 'percentage'));
+
+ALTER TABLE "balances" ADD CONSTRAINT "balances_ordered_check" CHECK ("user_a" < "user_b");
+
+ALTER TABLE "settlements" ADD CONSTRAINT "settlements_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "shared_expenses" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "shared_expenses" ADD FOREIGN KEY ("paid_by") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "expense_shares" ADD FOREIGN KEY ("expense_id") REFERENCES "shared_expenses" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "expense_shares" ADD FOREIGN KEY ("username") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "balances" ADD FOREIGN KEY ("user_a") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "balances" ADD FOREIGN KEY ("user_b") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("from_username") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE "settlements" ADD FOREIGN KEY ("to_username") REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE;
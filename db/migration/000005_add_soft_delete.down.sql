@@ -0,0 +1,8 @@
+DELETE FROM "entries" WHERE "deleted_at" IS NOT NULL OR "owner" IN (SELECT "username" FROM "users" WHERE "deleted_at" IS NOT NULL);
+DELETE FROM "users" WHERE "deleted_at" IS NOT NULL;
+
+DROP INDEX IF EXISTS "entries_name_key";
+ALTER TABLE IF EXISTS "entries" ADD CONSTRAINT "entries_name_key" UNIQUE ("name");
+
+ALTER TABLE IF EXISTS "entries" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "deleted_at";
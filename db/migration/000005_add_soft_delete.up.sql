@@ -0,0 +1,14 @@
+ALTER TABLE "entries" ADD COLUMN "deleted_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+
+ALTER TABLE "entries" DROP CONSTRAINT "entries_name_key";
+
+CREATE UNIQUE INDEX "entries_name_key" ON "entries" ("name") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX ON "entries" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE INDEX ON "users" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+COMMENT ON COLUMN "entries"."deleted_at" IS 'set while the entry is in the trash';
+
+COMMENT ON COLUMN "users"."deleted_at" IS 'set while the account is in the trash';
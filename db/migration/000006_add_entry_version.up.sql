@@ -0,0 +1,3 @@
+ALTER TABLE "entries" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "entries"."version" IS 'incremented on every change, used for optimistic concurrency';
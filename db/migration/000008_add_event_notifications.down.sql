@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS "users_notify_total_event" ON "users";
+DROP FUNCTION IF EXISTS notify_total_event;
+DROP TRIGGER IF EXISTS "entries_notify_event" ON "entries";
+DROP FUNCTION IF EXISTS notify_entry_event;
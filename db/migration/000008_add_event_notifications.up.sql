@@ -0,0 +1,64 @@
+-- Changes are published on the budget_events channel so every replica can
+-- push them to the clients of the user. Payloads are limited to 8000 bytes.
+
+CREATE FUNCTION notify_entry_event() RETURNS trigger AS $$
+DECLARE
+  event_type varchar;
+  changed entries;
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    event_type := 'entry.created';
+    changed := NEW;
+  ELSIF TG_OP = 'DELETE' THEN
+    -- purged entries were already deleted when they went to the trash
+    IF OLD.deleted_at IS NOT NULL THEN
+      RETURN OLD;
+    END IF;
+    event_type := 'entry.deleted';
+    changed := OLD;
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    event_type := 'entry.deleted';
+    changed := NEW;
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    event_type := 'entry.created';
+    changed := NEW;
+  ELSIF NEW.deleted_at IS NULL THEN
+    event_type := 'entry.updated';
+    changed := NEW;
+  ELSE
+    RETURN NEW;
+  END IF;
+
+  PERFORM pg_notify('budget_events', json_build_object(
+    'type', event_type,
+    'username', changed.owner,
+    'entry', row_to_json(changed)
+  )::text);
+
+  IF TG_OP = 'DELETE' THEN
+    RETURN OLD;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entries_notify_event"
+AFTER INSERT OR UPDATE OR DELETE ON "entries"
+FOR EACH ROW EXECUTE FUNCTION notify_entry_event();
+
+CREATE FUNCTION notify_total_event() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('budget_events', json_build_object(
+    'type', 'total.updated',
+    'username', NEW.username,
+    'total_expenses', NEW.total_expenses
+  )::text);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "users_notify_total_event"
+AFTER UPDATE OF "total_expenses" ON "users"
+FOR EACH ROW
+WHEN (OLD.total_expenses IS DISTINCT FROM NEW.total_expenses)
+EXECUTE FUNCTION notify_total_event();
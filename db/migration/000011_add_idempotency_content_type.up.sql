@@ -0,0 +1,3 @@
+ALTER TABLE "idempotency_keys" ADD COLUMN "content_type" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "idempotency_keys"."content_type" IS 'Content-Type of the stored response';
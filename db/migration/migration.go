@@ -0,0 +1,169 @@
+// Package migration embeds the SQL migrations so the server binary can apply
+// them without the migrate CLI
+package migration
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/sqlite"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migrator applies the embedded migrations to a database
+type Migrator struct {
+	migrate *migrate.Migrate
+	driver  string
+}
+
+// Status is the state of the migrations of a database
+type Status struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+// Pending reports whether the database is behind the embedded migrations
+func (status Status) Pending() bool {
+	return status.Version < status.Latest
+}
+
+// Returns the migrations of the schema for driver, SQLite has its own
+func migrationFiles(driver string) fs.FS {
+	if driver == sqlite.DriverName {
+		return sqlite.Migrations()
+	}
+	return files
+}
+
+// Opens its own connection to dbSource with driver, Close releases it
+func New(driver string, dbSource string) (*Migrator, error) {
+	source, err := iofs.New(migrationFiles(driver), ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read migrations: %w", err)
+	}
+
+	var m *migrate.Migrate
+	if driver == sqlite.DriverName {
+		m, err = newSQLiteMigrate(source, dbSource)
+	} else {
+		m, err = migrate.NewWithSourceInstance("iofs", source, dbSource)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create migrator: %w", err)
+	}
+	m.Log = logger{}
+
+	return &Migrator{migrate: m, driver: driver}, nil
+}
+
+// The SQLite database is opened like the app opens it, so both read dbSource the same way
+func newSQLiteMigrate(source source.Driver, dbSource string) (*migrate.Migrate, error) {
+	conn, err := sqlite.Open(dbSource)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := sqlitemigrate.WithInstance(conn, &sqlitemigrate.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", source, sqlite.DriverName, instance)
+}
+
+// Applies the next steps pending migrations, all of them when steps is negative.
+// The postgres driver holds an advisory lock meanwhile, so replicas starting
+// together apply them once.
+func (migrator *Migrator) Up(steps int) error {
+	if steps < 0 {
+		return ignoreNoChange(migrator.migrate.Up())
+	}
+	return ignoreNoChange(migrator.migrate.Steps(steps))
+}
+
+// Reverts the last steps migrations, all of them when steps is negative
+func (migrator *Migrator) Down(steps int) error {
+	if steps < 0 {
+		return ignoreNoChange(migrator.migrate.Down())
+	}
+	return ignoreNoChange(migrator.migrate.Steps(-steps))
+}
+
+// Sets the version without running any migration, to recover from a dirty one
+func (migrator *Migrator) Force(version int) error {
+	return migrator.migrate.Force(version)
+}
+
+func (migrator *Migrator) Status() (Status, error) {
+	latest, err := LatestVersion(migrator.driver)
+	if err != nil {
+		return Status{}, err
+	}
+
+	version, dirty, err := migrator.migrate.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return Status{}, err
+	}
+
+	return Status{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+func (migrator *Migrator) Close() error {
+	sourceErr, dbErr := migrator.migrate.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+	return dbErr
+}
+
+// Returns the version of the last embedded migration for driver, the one the
+// queries are written against
+func LatestVersion(driver string) (uint, error) {
+	files := migrationFiles(driver)
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, name := range names {
+		m, err := source.Parse(name)
+		if err != nil {
+			return 0, fmt.Errorf("cannot parse migration %s: %w", name, err)
+		}
+		if m.Version > latest {
+			latest = m.Version
+		}
+	}
+	return latest, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// logger writes the progress of the migrations to the app's log
+type logger struct{}
+
+func (logger) Printf(format string, v ...interface{}) {
+	log.Info().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (logger) Verbose() bool {
+	return true
+}
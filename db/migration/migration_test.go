@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+const postgresDriver = "postgres"
+
+func TestMigrationsArePaired(t *testing.T) {
+	for _, driver := range []string{postgresDriver, sqlite.DriverName} {
+		files := migrationFiles(driver)
+		ups, err := fs.Glob(files, "*.up.sql")
+		require.NoError(t, err)
+		downs, err := fs.Glob(files, "*.down.sql")
+		require.NoError(t, err)
+
+		require.NotEmpty(t, ups, driver)
+		require.Len(t, downs, len(ups), driver)
+		for i, up := range ups {
+			require.Equal(t, strings.TrimSuffix(up, ".up.sql"), strings.TrimSuffix(downs[i], ".down.sql"))
+		}
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	ups, err := fs.Glob(files, "*.up.sql")
+	require.NoError(t, err)
+
+	latest, err := LatestVersion(postgresDriver)
+	require.NoError(t, err)
+	require.Equal(t, uint(len(ups)), latest)
+
+	// the queries are shared, so both schemas must be at the same version
+	sqliteLatest, err := LatestVersion(sqlite.DriverName)
+	require.NoError(t, err)
+	require.Equal(t, latest, sqliteLatest)
+}
+
+func TestStatusPending(t *testing.T) {
+	require.True(t, Status{Version: 9, Latest: 10}.Pending())
+	require.False(t, Status{Version: 10, Latest: 10}.Pending())
+	// a newer replica may have migrated further already
+	require.False(t, Status{Version: 11, Latest: 10}.Pending())
+}
+
+func TestSQLiteUpAndDown(t *testing.T) {
+	source := "file:" + filepath.Join(t.TempDir(), "budgetapp.db")
+
+	migrator, err := New(sqlite.DriverName, source)
+	require.NoError(t, err)
+	defer migrator.Close()
+
+	require.NoError(t, migrator.Up(-1))
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	require.Equal(t, status.Latest, status.Version)
+	require.False(t, status.Dirty)
+	require.False(t, status.Pending())
+
+	require.NoError(t, migrator.Down(-1))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	require.Zero(t, status.Version)
+	require.True(t, status.Pending())
+}
@@ -8,8 +8,10 @@ import (
 	context "context"
 	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	util "github.com/LeandroEstevez/budgetAppAPI/util"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,254 +38,1575 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptHouseholdInvitation mocks base method.
+func (m *MockStore) AcceptHouseholdInvitation(arg0 context.Context, arg1 int32) (db.HouseholdInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptHouseholdInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptHouseholdInvitation indicates an expected call of AcceptHouseholdInvitation.
+func (mr *MockStoreMockRecorder) AcceptHouseholdInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptHouseholdInvitation", reflect.TypeOf((*MockStore)(nil).AcceptHouseholdInvitation), arg0, arg1)
+}
+
+// AcceptHouseholdInvitationTx mocks base method.
+func (m *MockStore) AcceptHouseholdInvitationTx(arg0 context.Context, arg1 db.AcceptHouseholdInvitationTxParams) (db.AcceptHouseholdInvitationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptHouseholdInvitationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AcceptHouseholdInvitationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptHouseholdInvitationTx indicates an expected call of AcceptHouseholdInvitationTx.
+func (mr *MockStoreMockRecorder) AcceptHouseholdInvitationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptHouseholdInvitationTx", reflect.TypeOf((*MockStore)(nil).AcceptHouseholdInvitationTx), arg0, arg1)
+}
+
 // AddEntryTx mocks base method.
 func (m *MockStore) AddEntryTx(arg0 context.Context, arg1 db.AddEntryTxParams) (db.AddEntryTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddEntryTx", arg0, arg1)
-	ret0, _ := ret[0].(db.AddEntryTxResult)
+	ret := m.ctrl.Call(m, "AddEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AddEntryTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEntryTx indicates an expected call of AddEntryTx.
+func (mr *MockStoreMockRecorder) AddEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntryTx", reflect.TypeOf((*MockStore)(nil).AddEntryTx), arg0, arg1)
+}
+
+// AddHouseholdMember mocks base method.
+func (m *MockStore) AddHouseholdMember(arg0 context.Context, arg1 db.AddHouseholdMemberParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHouseholdMember", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddHouseholdMember indicates an expected call of AddHouseholdMember.
+func (mr *MockStoreMockRecorder) AddHouseholdMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHouseholdMember", reflect.TypeOf((*MockStore)(nil).AddHouseholdMember), arg0, arg1)
+}
+
+// AddToBalance mocks base method.
+func (m *MockStore) AddToBalance(arg0 context.Context, arg1 db.AddToBalanceParams) (db.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddToBalance indicates an expected call of AddToBalance.
+func (mr *MockStoreMockRecorder) AddToBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToBalance", reflect.TypeOf((*MockStore)(nil).AddToBalance), arg0, arg1)
+}
+
+// BatchEntriesTx mocks base method.
+func (m *MockStore) BatchEntriesTx(arg0 context.Context, arg1 db.BatchEntriesTxParams) (db.BatchEntriesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchEntriesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchEntriesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchEntriesTx indicates an expected call of BatchEntriesTx.
+func (mr *MockStoreMockRecorder) BatchEntriesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchEntriesTx", reflect.TypeOf((*MockStore)(nil).BatchEntriesTx), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 db.ClaimWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// CompleteWebhookDelivery mocks base method.
+func (m *MockStore) CompleteWebhookDelivery(arg0 context.Context, arg1 db.CompleteWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteWebhookDelivery indicates an expected call of CompleteWebhookDelivery.
+func (mr *MockStoreMockRecorder) CompleteWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CompleteWebhookDelivery), arg0, arg1)
+}
+
+// CreateAuditEvent mocks base method.
+func (m *MockStore) CreateAuditEvent(arg0 context.Context, arg1 db.CreateAuditEventParams) (db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEvent indicates an expected call of CreateAuditEvent.
+func (mr *MockStoreMockRecorder) CreateAuditEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEvent", reflect.TypeOf((*MockStore)(nil).CreateAuditEvent), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateExpenseShare mocks base method.
+func (m *MockStore) CreateExpenseShare(arg0 context.Context, arg1 db.CreateExpenseShareParams) (db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExpenseShare", arg0, arg1)
+	ret0, _ := ret[0].(db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExpenseShare indicates an expected call of CreateExpenseShare.
+func (mr *MockStoreMockRecorder) CreateExpenseShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpenseShare", reflect.TypeOf((*MockStore)(nil).CreateExpenseShare), arg0, arg1)
+}
+
+// CreateHousehold mocks base method.
+func (m *MockStore) CreateHousehold(arg0 context.Context, arg1 string) (db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHousehold", arg0, arg1)
+	ret0, _ := ret[0].(db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHousehold indicates an expected call of CreateHousehold.
+func (mr *MockStoreMockRecorder) CreateHousehold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHousehold", reflect.TypeOf((*MockStore)(nil).CreateHousehold), arg0, arg1)
+}
+
+// CreateHouseholdEntry mocks base method.
+func (m *MockStore) CreateHouseholdEntry(arg0 context.Context, arg1 db.CreateHouseholdEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdEntry indicates an expected call of CreateHouseholdEntry.
+func (mr *MockStoreMockRecorder) CreateHouseholdEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdEntry", reflect.TypeOf((*MockStore)(nil).CreateHouseholdEntry), arg0, arg1)
+}
+
+// CreateHouseholdEntryTx mocks base method.
+func (m *MockStore) CreateHouseholdEntryTx(arg0 context.Context, arg1 db.CreateHouseholdEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdEntryTx indicates an expected call of CreateHouseholdEntryTx.
+func (mr *MockStoreMockRecorder) CreateHouseholdEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdEntryTx", reflect.TypeOf((*MockStore)(nil).CreateHouseholdEntryTx), arg0, arg1)
+}
+
+// CreateHouseholdInvitation mocks base method.
+func (m *MockStore) CreateHouseholdInvitation(arg0 context.Context, arg1 db.CreateHouseholdInvitationParams) (db.HouseholdInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdInvitation", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdInvitation indicates an expected call of CreateHouseholdInvitation.
+func (mr *MockStoreMockRecorder) CreateHouseholdInvitation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdInvitation", reflect.TypeOf((*MockStore)(nil).CreateHouseholdInvitation), arg0, arg1)
+}
+
+// CreateHouseholdInvitationTx mocks base method.
+func (m *MockStore) CreateHouseholdInvitationTx(arg0 context.Context, arg1 db.CreateHouseholdInvitationParams) (db.HouseholdInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdInvitationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdInvitationTx indicates an expected call of CreateHouseholdInvitationTx.
+func (mr *MockStoreMockRecorder) CreateHouseholdInvitationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdInvitationTx", reflect.TypeOf((*MockStore)(nil).CreateHouseholdInvitationTx), arg0, arg1)
+}
+
+// CreateHouseholdTx mocks base method.
+func (m *MockStore) CreateHouseholdTx(arg0 context.Context, arg1 db.CreateHouseholdTxParams) (db.CreateHouseholdTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHouseholdTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateHouseholdTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHouseholdTx indicates an expected call of CreateHouseholdTx.
+func (mr *MockStoreMockRecorder) CreateHouseholdTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHouseholdTx", reflect.TypeOf((*MockStore)(nil).CreateHouseholdTx), arg0, arg1)
+}
+
+// CreateIdempotencyKey mocks base method.
+func (m *MockStore) CreateIdempotencyKey(arg0 context.Context, arg1 db.CreateIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotencyKey indicates an expected call of CreateIdempotencyKey.
+func (mr *MockStoreMockRecorder) CreateIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyKey", reflect.TypeOf((*MockStore)(nil).CreateIdempotencyKey), arg0, arg1)
+}
+
+// CreateRateLimitBucket mocks base method.
+func (m *MockStore) CreateRateLimitBucket(arg0 context.Context, arg1 db.CreateRateLimitBucketParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRateLimitBucket", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRateLimitBucket indicates an expected call of CreateRateLimitBucket.
+func (mr *MockStoreMockRecorder) CreateRateLimitBucket(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRateLimitBucket", reflect.TypeOf((*MockStore)(nil).CreateRateLimitBucket), arg0, arg1)
+}
+
+// CreateSettlement mocks base method.
+func (m *MockStore) CreateSettlement(arg0 context.Context, arg1 db.CreateSettlementParams) (db.Settlement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSettlement", arg0, arg1)
+	ret0, _ := ret[0].(db.Settlement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSettlement indicates an expected call of CreateSettlement.
+func (mr *MockStoreMockRecorder) CreateSettlement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSettlement", reflect.TypeOf((*MockStore)(nil).CreateSettlement), arg0, arg1)
+}
+
+// CreateSharedExpense mocks base method.
+func (m *MockStore) CreateSharedExpense(arg0 context.Context, arg1 db.CreateSharedExpenseParams) (db.SharedExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSharedExpense", arg0, arg1)
+	ret0, _ := ret[0].(db.SharedExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSharedExpense indicates an expected call of CreateSharedExpense.
+func (mr *MockStoreMockRecorder) CreateSharedExpense(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSharedExpense", reflect.TypeOf((*MockStore)(nil).CreateSharedExpense), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), arg0, arg1)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(arg0 context.Context, arg1 db.CreateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), arg0, arg1)
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockStore) CreateWebhookDelivery(arg0 context.Context, arg1 db.CreateWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockStoreMockRecorder) CreateWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockStore)(nil).CreateWebhookDelivery), arg0, arg1)
+}
+
+// CreateWebhookTx mocks base method.
+func (m *MockStore) CreateWebhookTx(arg0 context.Context, arg1 db.CreateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookTx indicates an expected call of CreateWebhookTx.
+func (mr *MockStoreMockRecorder) CreateWebhookTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookTx", reflect.TypeOf((*MockStore)(nil).CreateWebhookTx), arg0, arg1)
+}
+
+// DeleteEntries mocks base method.
+func (m *MockStore) DeleteEntries(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntries indicates an expected call of DeleteEntries.
+func (mr *MockStoreMockRecorder) DeleteEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntries", reflect.TypeOf((*MockStore)(nil).DeleteEntries), arg0, arg1)
+}
+
+// DeleteEntry mocks base method.
+func (m *MockStore) DeleteEntry(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEntry indicates an expected call of DeleteEntry.
+func (mr *MockStoreMockRecorder) DeleteEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockStore)(nil).DeleteEntry), arg0, arg1)
+}
+
+// DeleteEntryTx mocks base method.
+func (m *MockStore) DeleteEntryTx(arg0 context.Context, arg1 db.DeleteEntryTxParams) (db.DeleteEntryTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DeleteEntryTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEntryTx indicates an expected call of DeleteEntryTx.
+func (mr *MockStoreMockRecorder) DeleteEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryTx", reflect.TypeOf((*MockStore)(nil).DeleteEntryTx), arg0, arg1)
+}
+
+// DeleteExpiredIdempotencyKeys mocks base method.
+func (m *MockStore) DeleteExpiredIdempotencyKeys(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyKeys", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdempotencyKeys indicates an expected call of DeleteExpiredIdempotencyKeys.
+func (mr *MockStoreMockRecorder) DeleteExpiredIdempotencyKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyKeys", reflect.TypeOf((*MockStore)(nil).DeleteExpiredIdempotencyKeys), arg0, arg1)
+}
+
+// DeleteHousehold mocks base method.
+func (m *MockStore) DeleteHousehold(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHousehold", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHousehold indicates an expected call of DeleteHousehold.
+func (mr *MockStoreMockRecorder) DeleteHousehold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHousehold", reflect.TypeOf((*MockStore)(nil).DeleteHousehold), arg0, arg1)
+}
+
+// DeleteHouseholdEntry mocks base method.
+func (m *MockStore) DeleteHouseholdEntry(arg0 context.Context, arg1 db.DeleteHouseholdEntryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdEntry", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdEntry indicates an expected call of DeleteHouseholdEntry.
+func (mr *MockStoreMockRecorder) DeleteHouseholdEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdEntry", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdEntry), arg0, arg1)
+}
+
+// DeleteHouseholdEntryTx mocks base method.
+func (m *MockStore) DeleteHouseholdEntryTx(arg0 context.Context, arg1 db.DeleteHouseholdEntryTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdEntryTx indicates an expected call of DeleteHouseholdEntryTx.
+func (mr *MockStoreMockRecorder) DeleteHouseholdEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdEntryTx", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdEntryTx), arg0, arg1)
+}
+
+// DeleteHouseholdMember mocks base method.
+func (m *MockStore) DeleteHouseholdMember(arg0 context.Context, arg1 db.DeleteHouseholdMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdMember indicates an expected call of DeleteHouseholdMember.
+func (mr *MockStoreMockRecorder) DeleteHouseholdMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdMember", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdMember), arg0, arg1)
+}
+
+// DeleteHouseholdMemberTx mocks base method.
+func (m *MockStore) DeleteHouseholdMemberTx(arg0 context.Context, arg1 db.DeleteHouseholdMemberTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHouseholdMemberTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHouseholdMemberTx indicates an expected call of DeleteHouseholdMemberTx.
+func (mr *MockStoreMockRecorder) DeleteHouseholdMemberTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHouseholdMemberTx", reflect.TypeOf((*MockStore)(nil).DeleteHouseholdMemberTx), arg0, arg1)
+}
+
+// DeleteIdempotencyKey mocks base method.
+func (m *MockStore) DeleteIdempotencyKey(arg0 context.Context, arg1 db.DeleteIdempotencyKeyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyKey indicates an expected call of DeleteIdempotencyKey.
+func (mr *MockStoreMockRecorder) DeleteIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyKey", reflect.TypeOf((*MockStore)(nil).DeleteIdempotencyKey), arg0, arg1)
+}
+
+// DeleteIdleRateLimitBuckets mocks base method.
+func (m *MockStore) DeleteIdleRateLimitBuckets(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleRateLimitBuckets", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdleRateLimitBuckets indicates an expected call of DeleteIdleRateLimitBuckets.
+func (mr *MockStoreMockRecorder) DeleteIdleRateLimitBuckets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleRateLimitBuckets", reflect.TypeOf((*MockStore)(nil).DeleteIdleRateLimitBuckets), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteUserTx mocks base method.
+func (m *MockStore) DeleteUserTx(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTx indicates an expected call of DeleteUserTx.
+func (mr *MockStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockStore)(nil).DeleteUserTx), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(arg0 context.Context, arg1 db.DeleteWebhookParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// DeleteWebhookTx mocks base method.
+func (m *MockStore) DeleteWebhookTx(arg0 context.Context, arg1 db.DeleteWebhookParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookTx indicates an expected call of DeleteWebhookTx.
+func (mr *MockStoreMockRecorder) DeleteWebhookTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookTx", reflect.TypeOf((*MockStore)(nil).DeleteWebhookTx), arg0, arg1)
+}
+
+// EnqueueBillDue mocks base method.
+func (m *MockStore) EnqueueBillDue(arg0 context.Context, arg1 db.EnqueueBillDueParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueBillDue", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueBillDue indicates an expected call of EnqueueBillDue.
+func (mr *MockStoreMockRecorder) EnqueueBillDue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueBillDue", reflect.TypeOf((*MockStore)(nil).EnqueueBillDue), arg0, arg1)
+}
+
+// EnqueueBudgetExceeded mocks base method.
+func (m *MockStore) EnqueueBudgetExceeded(arg0 context.Context, arg1 db.EnqueueBudgetExceededParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueBudgetExceeded", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueBudgetExceeded indicates an expected call of EnqueueBudgetExceeded.
+func (mr *MockStoreMockRecorder) EnqueueBudgetExceeded(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueBudgetExceeded", reflect.TypeOf((*MockStore)(nil).EnqueueBudgetExceeded), arg0, arg1)
+}
+
+// EnqueueWebhookEvent mocks base method.
+func (m *MockStore) EnqueueWebhookEvent(arg0 context.Context, arg1 db.EnqueueWebhookEventParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookEvent", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueWebhookEvent indicates an expected call of EnqueueWebhookEvent.
+func (mr *MockStoreMockRecorder) EnqueueWebhookEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookEvent", reflect.TypeOf((*MockStore)(nil).EnqueueWebhookEvent), arg0, arg1)
+}
+
+// FailWebhookDelivery mocks base method.
+func (m *MockStore) FailWebhookDelivery(arg0 context.Context, arg1 db.FailWebhookDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailWebhookDelivery indicates an expected call of FailWebhookDelivery.
+func (mr *MockStoreMockRecorder) FailWebhookDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailWebhookDelivery", reflect.TypeOf((*MockStore)(nil).FailWebhookDelivery), arg0, arg1)
+}
+
+// GetBalanceForUpdate mocks base method.
+func (m *MockStore) GetBalanceForUpdate(arg0 context.Context, arg1 db.GetBalanceForUpdateParams) (db.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceForUpdate indicates an expected call of GetBalanceForUpdate.
+func (mr *MockStoreMockRecorder) GetBalanceForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceForUpdate", reflect.TypeOf((*MockStore)(nil).GetBalanceForUpdate), arg0, arg1)
+}
+
+// GetCategories mocks base method.
+func (m *MockStore) GetCategories(arg0 context.Context, arg1 string) ([]sql.NullString, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", arg0, arg1)
+	ret0, _ := ret[0].([]sql.NullString)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockStoreMockRecorder) GetCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockStore)(nil).GetCategories), arg0, arg1)
+}
+
+// GetDeletedEntryForUpdate mocks base method.
+func (m *MockStore) GetDeletedEntryForUpdate(arg0 context.Context, arg1 db.GetDeletedEntryForUpdateParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedEntryForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedEntryForUpdate indicates an expected call of GetDeletedEntryForUpdate.
+func (mr *MockStoreMockRecorder) GetDeletedEntryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedEntryForUpdate", reflect.TypeOf((*MockStore)(nil).GetDeletedEntryForUpdate), arg0, arg1)
+}
+
+// GetDeletedHouseholdEntryForUpdate mocks base method.
+func (m *MockStore) GetDeletedHouseholdEntryForUpdate(arg0 context.Context, arg1 db.GetDeletedHouseholdEntryForUpdateParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedHouseholdEntryForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedHouseholdEntryForUpdate indicates an expected call of GetDeletedHouseholdEntryForUpdate.
+func (mr *MockStoreMockRecorder) GetDeletedHouseholdEntryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedHouseholdEntryForUpdate", reflect.TypeOf((*MockStore)(nil).GetDeletedHouseholdEntryForUpdate), arg0, arg1)
+}
+
+// GetDeletedUser mocks base method.
+func (m *MockStore) GetDeletedUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedUser indicates an expected call of GetDeletedUser.
+func (mr *MockStoreMockRecorder) GetDeletedUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedUser", reflect.TypeOf((*MockStore)(nil).GetDeletedUser), arg0, arg1)
+}
+
+// GetEmail mocks base method.
+func (m *MockStore) GetEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmail indicates an expected call of GetEmail.
+func (mr *MockStoreMockRecorder) GetEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmail", reflect.TypeOf((*MockStore)(nil).GetEmail), arg0, arg1)
+}
+
+// GetEntries mocks base method.
+func (m *MockStore) GetEntries(arg0 context.Context, arg1 string) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntries indicates an expected call of GetEntries.
+func (mr *MockStoreMockRecorder) GetEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntries", reflect.TypeOf((*MockStore)(nil).GetEntries), arg0, arg1)
+}
+
+// GetEntriesOfHouseholds mocks base method.
+func (m *MockStore) GetEntriesOfHouseholds(arg0 context.Context, arg1 []int32) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesOfHouseholds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesOfHouseholds indicates an expected call of GetEntriesOfHouseholds.
+func (mr *MockStoreMockRecorder) GetEntriesOfHouseholds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesOfHouseholds", reflect.TypeOf((*MockStore)(nil).GetEntriesOfHouseholds), arg0, arg1)
+}
+
+// GetEntriesTotal mocks base method.
+func (m *MockStore) GetEntriesTotal(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntriesTotal", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntriesTotal indicates an expected call of GetEntriesTotal.
+func (mr *MockStoreMockRecorder) GetEntriesTotal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntriesTotal", reflect.TypeOf((*MockStore)(nil).GetEntriesTotal), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 db.GetEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetEntryForUpdate mocks base method.
+func (m *MockStore) GetEntryForUpdate(arg0 context.Context, arg1 db.GetEntryForUpdateParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntryForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntryForUpdate indicates an expected call of GetEntryForUpdate.
+func (mr *MockStoreMockRecorder) GetEntryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryForUpdate", reflect.TypeOf((*MockStore)(nil).GetEntryForUpdate), arg0, arg1)
+}
+
+// GetHousehold mocks base method.
+func (m *MockStore) GetHousehold(arg0 context.Context, arg1 int32) (db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHousehold", arg0, arg1)
+	ret0, _ := ret[0].(db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHousehold indicates an expected call of GetHousehold.
+func (mr *MockStoreMockRecorder) GetHousehold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHousehold", reflect.TypeOf((*MockStore)(nil).GetHousehold), arg0, arg1)
+}
+
+// GetHouseholdEntries mocks base method.
+func (m *MockStore) GetHouseholdEntries(arg0 context.Context, arg1 sql.NullInt32) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdEntries indicates an expected call of GetHouseholdEntries.
+func (mr *MockStoreMockRecorder) GetHouseholdEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdEntries", reflect.TypeOf((*MockStore)(nil).GetHouseholdEntries), arg0, arg1)
+}
+
+// GetHouseholdEntry mocks base method.
+func (m *MockStore) GetHouseholdEntry(arg0 context.Context, arg1 db.GetHouseholdEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdEntry indicates an expected call of GetHouseholdEntry.
+func (mr *MockStoreMockRecorder) GetHouseholdEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdEntry", reflect.TypeOf((*MockStore)(nil).GetHouseholdEntry), arg0, arg1)
+}
+
+// GetHouseholdEntryForUpdate mocks base method.
+func (m *MockStore) GetHouseholdEntryForUpdate(arg0 context.Context, arg1 db.GetHouseholdEntryForUpdateParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdEntryForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdEntryForUpdate indicates an expected call of GetHouseholdEntryForUpdate.
+func (mr *MockStoreMockRecorder) GetHouseholdEntryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdEntryForUpdate", reflect.TypeOf((*MockStore)(nil).GetHouseholdEntryForUpdate), arg0, arg1)
+}
+
+// GetHouseholdInvitationForUpdate mocks base method.
+func (m *MockStore) GetHouseholdInvitationForUpdate(arg0 context.Context, arg1 int32) (db.HouseholdInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdInvitationForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdInvitationForUpdate indicates an expected call of GetHouseholdInvitationForUpdate.
+func (mr *MockStoreMockRecorder) GetHouseholdInvitationForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdInvitationForUpdate", reflect.TypeOf((*MockStore)(nil).GetHouseholdInvitationForUpdate), arg0, arg1)
+}
+
+// GetHouseholdMember mocks base method.
+func (m *MockStore) GetHouseholdMember(arg0 context.Context, arg1 db.GetHouseholdMemberParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdMember", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdMember indicates an expected call of GetHouseholdMember.
+func (mr *MockStoreMockRecorder) GetHouseholdMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdMember", reflect.TypeOf((*MockStore)(nil).GetHouseholdMember), arg0, arg1)
+}
+
+// GetHouseholdMemberForUpdate mocks base method.
+func (m *MockStore) GetHouseholdMemberForUpdate(arg0 context.Context, arg1 db.GetHouseholdMemberForUpdateParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdMemberForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdMemberForUpdate indicates an expected call of GetHouseholdMemberForUpdate.
+func (mr *MockStoreMockRecorder) GetHouseholdMemberForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdMemberForUpdate", reflect.TypeOf((*MockStore)(nil).GetHouseholdMemberForUpdate), arg0, arg1)
+}
+
+// GetHouseholdTotal mocks base method.
+func (m *MockStore) GetHouseholdTotal(arg0 context.Context, arg1 sql.NullInt32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHouseholdTotal", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHouseholdTotal indicates an expected call of GetHouseholdTotal.
+func (mr *MockStoreMockRecorder) GetHouseholdTotal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholdTotal", reflect.TypeOf((*MockStore)(nil).GetHouseholdTotal), arg0, arg1)
+}
+
+// GetIdempotencyKey mocks base method.
+func (m *MockStore) GetIdempotencyKey(arg0 context.Context, arg1 db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyKey", arg0, arg1)
+	ret0, _ := ret[0].(db.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyKey indicates an expected call of GetIdempotencyKey.
+func (mr *MockStoreMockRecorder) GetIdempotencyKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyKey", reflect.TypeOf((*MockStore)(nil).GetIdempotencyKey), arg0, arg1)
+}
+
+// GetMigrationVersion mocks base method.
+func (m *MockStore) GetMigrationVersion(arg0 context.Context) (db.MigrationVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMigrationVersion", arg0)
+	ret0, _ := ret[0].(db.MigrationVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMigrationVersion indicates an expected call of GetMigrationVersion.
+func (mr *MockStoreMockRecorder) GetMigrationVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMigrationVersion", reflect.TypeOf((*MockStore)(nil).GetMigrationVersion), arg0)
+}
+
+// GetRateLimitBucketForUpdate mocks base method.
+func (m *MockStore) GetRateLimitBucketForUpdate(arg0 context.Context, arg1 string) (db.RateLimitBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRateLimitBucketForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.RateLimitBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRateLimitBucketForUpdate indicates an expected call of GetRateLimitBucketForUpdate.
+func (mr *MockStoreMockRecorder) GetRateLimitBucketForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRateLimitBucketForUpdate", reflect.TypeOf((*MockStore)(nil).GetRateLimitBucketForUpdate), arg0, arg1)
+}
+
+// GetSharedExpenseByEntry mocks base method.
+func (m *MockStore) GetSharedExpenseByEntry(arg0 context.Context, arg1 int32) (db.SharedExpense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSharedExpenseByEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.SharedExpense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSharedExpenseByEntry indicates an expected call of GetSharedExpenseByEntry.
+func (mr *MockStoreMockRecorder) GetSharedExpenseByEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedExpenseByEntry", reflect.TypeOf((*MockStore)(nil).GetSharedExpenseByEntry), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetUsers mocks base method.
+func (m *MockStore) GetUsers(arg0 context.Context, arg1 []string) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockStoreMockRecorder) GetUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(arg0 context.Context, arg1 db.GetWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), arg0, arg1)
+}
+
+// GetWebhookForDelivery mocks base method.
+func (m *MockStore) GetWebhookForDelivery(arg0 context.Context, arg1 int32) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookForDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookForDelivery indicates an expected call of GetWebhookForDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookForDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookForDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookForDelivery), arg0, arg1)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockStore) ListAuditEvents(arg0 context.Context, arg1 db.ListAuditEventsParams) ([]db.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEvents", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockStoreMockRecorder) ListAuditEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockStore)(nil).ListAuditEvents), arg0, arg1)
+}
+
+// ListBalances mocks base method.
+func (m *MockStore) ListBalances(arg0 context.Context, arg1 string) ([]db.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalances", arg0, arg1)
+	ret0, _ := ret[0].([]db.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalances indicates an expected call of ListBalances.
+func (mr *MockStoreMockRecorder) ListBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalances", reflect.TypeOf((*MockStore)(nil).ListBalances), arg0, arg1)
+}
+
+// ListBalancesAmong mocks base method.
+func (m *MockStore) ListBalancesAmong(arg0 context.Context, arg1 []string) ([]db.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalancesAmong", arg0, arg1)
+	ret0, _ := ret[0].([]db.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalancesAmong indicates an expected call of ListBalancesAmong.
+func (mr *MockStoreMockRecorder) ListBalancesAmong(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalancesAmong", reflect.TypeOf((*MockStore)(nil).ListBalancesAmong), arg0, arg1)
+}
+
+// ListDeletedEntries mocks base method.
+func (m *MockStore) ListDeletedEntries(arg0 context.Context, arg1 string) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedEntries indicates an expected call of ListDeletedEntries.
+func (mr *MockStoreMockRecorder) ListDeletedEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedEntries", reflect.TypeOf((*MockStore)(nil).ListDeletedEntries), arg0, arg1)
+}
+
+// ListDeletedHouseholdEntries mocks base method.
+func (m *MockStore) ListDeletedHouseholdEntries(arg0 context.Context, arg1 sql.NullInt32) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedHouseholdEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedHouseholdEntries indicates an expected call of ListDeletedHouseholdEntries.
+func (mr *MockStoreMockRecorder) ListDeletedHouseholdEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedHouseholdEntries", reflect.TypeOf((*MockStore)(nil).ListDeletedHouseholdEntries), arg0, arg1)
+}
+
+// ListExpenseShares mocks base method.
+func (m *MockStore) ListExpenseShares(arg0 context.Context, arg1 int32) ([]db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpenseShares", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpenseShares indicates an expected call of ListExpenseShares.
+func (mr *MockStoreMockRecorder) ListExpenseShares(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpenseShares", reflect.TypeOf((*MockStore)(nil).ListExpenseShares), arg0, arg1)
+}
+
+// ListHouseholdInvitations mocks base method.
+func (m *MockStore) ListHouseholdInvitations(arg0 context.Context, arg1 string) ([]db.HouseholdInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholdInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]db.HouseholdInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholdInvitations indicates an expected call of ListHouseholdInvitations.
+func (mr *MockStoreMockRecorder) ListHouseholdInvitations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholdInvitations", reflect.TypeOf((*MockStore)(nil).ListHouseholdInvitations), arg0, arg1)
+}
+
+// ListHouseholdMembers mocks base method.
+func (m *MockStore) ListHouseholdMembers(arg0 context.Context, arg1 int32) ([]db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholdMembers", arg0, arg1)
+	ret0, _ := ret[0].([]db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholdMembers indicates an expected call of ListHouseholdMembers.
+func (mr *MockStoreMockRecorder) ListHouseholdMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholdMembers", reflect.TypeOf((*MockStore)(nil).ListHouseholdMembers), arg0, arg1)
+}
+
+// ListHouseholds mocks base method.
+func (m *MockStore) ListHouseholds(arg0 context.Context, arg1 string) ([]db.Household, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHouseholds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Household)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHouseholds indicates an expected call of ListHouseholds.
+func (mr *MockStoreMockRecorder) ListHouseholds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHouseholds", reflect.TypeOf((*MockStore)(nil).ListHouseholds), arg0, arg1)
+}
+
+// ListMembersOfHouseholds mocks base method.
+func (m *MockStore) ListMembersOfHouseholds(arg0 context.Context, arg1 []int32) ([]db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembersOfHouseholds", arg0, arg1)
+	ret0, _ := ret[0].([]db.HouseholdMember)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddEntryTx indicates an expected call of AddEntryTx.
-func (mr *MockStoreMockRecorder) AddEntryTx(arg0, arg1 interface{}) *gomock.Call {
+// ListMembersOfHouseholds indicates an expected call of ListMembersOfHouseholds.
+func (mr *MockStoreMockRecorder) ListMembersOfHouseholds(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEntryTx", reflect.TypeOf((*MockStore)(nil).AddEntryTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembersOfHouseholds", reflect.TypeOf((*MockStore)(nil).ListMembersOfHouseholds), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+// ListSettlements mocks base method.
+func (m *MockStore) ListSettlements(arg0 context.Context, arg1 string) ([]db.Settlement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "ListSettlements", arg0, arg1)
+	ret0, _ := ret[0].([]db.Settlement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+// ListSettlements indicates an expected call of ListSettlements.
+func (mr *MockStoreMockRecorder) ListSettlements(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSettlements", reflect.TypeOf((*MockStore)(nil).ListSettlements), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// ListTotalExpensesDrift mocks base method.
+func (m *MockStore) ListTotalExpensesDrift(arg0 context.Context) ([]db.ListTotalExpensesDriftRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListTotalExpensesDrift", arg0)
+	ret0, _ := ret[0].([]db.ListTotalExpensesDriftRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// ListTotalExpensesDrift indicates an expected call of ListTotalExpensesDrift.
+func (mr *MockStoreMockRecorder) ListTotalExpensesDrift(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTotalExpensesDrift", reflect.TypeOf((*MockStore)(nil).ListTotalExpensesDrift), arg0)
 }
 
-// DeleteEntries mocks base method.
-func (m *MockStore) DeleteEntries(arg0 context.Context, arg1 string) error {
+// ListUsers mocks base method.
+func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntries", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteEntries indicates an expected call of DeleteEntries.
-func (mr *MockStoreMockRecorder) DeleteEntries(arg0, arg1 interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntries", reflect.TypeOf((*MockStore)(nil).DeleteEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
 }
 
-// DeleteEntry mocks base method.
-func (m *MockStore) DeleteEntry(arg0 context.Context, arg1 int32) error {
+// ListWebhookDeliveries mocks base method.
+func (m *MockStore) ListWebhookDeliveries(arg0 context.Context, arg1 db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntry", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteEntry indicates an expected call of DeleteEntry.
-func (mr *MockStoreMockRecorder) DeleteEntry(arg0, arg1 interface{}) *gomock.Call {
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntry", reflect.TypeOf((*MockStore)(nil).DeleteEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveries), arg0, arg1)
 }
 
-// DeleteEntryTx mocks base method.
-func (m *MockStore) DeleteEntryTx(arg0 context.Context, arg1 db.DeleteEntryTxParams) (db.DeleteEntryTxResult, error) {
+// ListWebhooks mocks base method.
+func (m *MockStore) ListWebhooks(arg0 context.Context, arg1 string) ([]db.Webhook, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteEntryTx", arg0, arg1)
-	ret0, _ := ret[0].(db.DeleteEntryTxResult)
+	ret := m.ctrl.Call(m, "ListWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Webhook)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteEntryTx indicates an expected call of DeleteEntryTx.
-func (mr *MockStoreMockRecorder) DeleteEntryTx(arg0, arg1 interface{}) *gomock.Call {
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockStoreMockRecorder) ListWebhooks(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEntryTx", reflect.TypeOf((*MockStore)(nil).DeleteEntryTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockStore)(nil).ListWebhooks), arg0, arg1)
 }
 
-// DeleteUser mocks base method.
-func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) error {
+// Ping mocks base method.
+func (m *MockStore) Ping(arg0 context.Context) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "Ping", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteUser indicates an expected call of DeleteUser.
-func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+// Ping indicates an expected call of Ping.
+func (mr *MockStoreMockRecorder) Ping(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStore)(nil).Ping), arg0)
 }
 
-// DeleteUserTx mocks base method.
-func (m *MockStore) DeleteUserTx(arg0 context.Context, arg1 string) error {
+// PurgeEntries mocks base method.
+func (m *MockStore) PurgeEntries(arg0 context.Context, arg1 sql.NullTime) ([]db.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "PurgeEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteUserTx indicates an expected call of DeleteUserTx.
-func (mr *MockStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+// PurgeEntries indicates an expected call of PurgeEntries.
+func (mr *MockStoreMockRecorder) PurgeEntries(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockStore)(nil).DeleteUserTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEntries", reflect.TypeOf((*MockStore)(nil).PurgeEntries), arg0, arg1)
 }
 
-// GetCategories mocks base method.
-func (m *MockStore) GetCategories(arg0 context.Context, arg1 string) ([]sql.NullString, error) {
+// PurgeTrashTx mocks base method.
+func (m *MockStore) PurgeTrashTx(arg0 context.Context, arg1 time.Time) (db.PurgeTrashTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategories", arg0, arg1)
-	ret0, _ := ret[0].([]sql.NullString)
+	ret := m.ctrl.Call(m, "PurgeTrashTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PurgeTrashTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCategories indicates an expected call of GetCategories.
-func (mr *MockStoreMockRecorder) GetCategories(arg0, arg1 interface{}) *gomock.Call {
+// PurgeTrashTx indicates an expected call of PurgeTrashTx.
+func (mr *MockStoreMockRecorder) PurgeTrashTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockStore)(nil).GetCategories), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrashTx", reflect.TypeOf((*MockStore)(nil).PurgeTrashTx), arg0, arg1)
 }
 
-// GetEmail mocks base method.
-func (m *MockStore) GetEmail(arg0 context.Context, arg1 string) (db.User, error) {
+// PurgeUsers mocks base method.
+func (m *MockStore) PurgeUsers(arg0 context.Context, arg1 sql.NullTime) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEmail", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "PurgeUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEmail indicates an expected call of GetEmail.
-func (mr *MockStoreMockRecorder) GetEmail(arg0, arg1 interface{}) *gomock.Call {
+// PurgeUsers indicates an expected call of PurgeUsers.
+func (mr *MockStoreMockRecorder) PurgeUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmail", reflect.TypeOf((*MockStore)(nil).GetEmail), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUsers", reflect.TypeOf((*MockStore)(nil).PurgeUsers), arg0, arg1)
 }
 
-// GetEntries mocks base method.
-func (m *MockStore) GetEntries(arg0 context.Context, arg1 string) ([]db.Entry, error) {
+// ReconcileTotalExpensesTx mocks base method.
+func (m *MockStore) ReconcileTotalExpensesTx(arg0 context.Context, arg1 string) (db.ReconcileTotalExpensesTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "ReconcileTotalExpensesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ReconcileTotalExpensesTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntries indicates an expected call of GetEntries.
-func (mr *MockStoreMockRecorder) GetEntries(arg0, arg1 interface{}) *gomock.Call {
+// ReconcileTotalExpensesTx indicates an expected call of ReconcileTotalExpensesTx.
+func (mr *MockStoreMockRecorder) ReconcileTotalExpensesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntries", reflect.TypeOf((*MockStore)(nil).GetEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileTotalExpensesTx", reflect.TypeOf((*MockStore)(nil).ReconcileTotalExpensesTx), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 db.GetEntryParams) (db.Entry, error) {
+// ResetPassword mocks base method.
+func (m *MockStore) ResetPassword(arg0 context.Context, arg1 db.ResetPasswordParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockStoreMockRecorder) ResetPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockStore)(nil).ResetPassword), arg0, arg1)
+}
+
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// RestoreEntry mocks base method.
+func (m *MockStore) RestoreEntry(arg0 context.Context, arg1 int32) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEntry", arg0, arg1)
 	ret0, _ := ret[0].(db.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// RestoreEntry indicates an expected call of RestoreEntry.
+func (mr *MockStoreMockRecorder) RestoreEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntry", reflect.TypeOf((*MockStore)(nil).RestoreEntry), arg0, arg1)
 }
 
-// GetEntryForUpdate mocks base method.
-func (m *MockStore) GetEntryForUpdate(arg0 context.Context, arg1 db.GetEntryForUpdateParams) (db.Entry, error) {
+// RestoreEntryTx mocks base method.
+func (m *MockStore) RestoreEntryTx(arg0 context.Context, arg1 db.RestoreEntryTxParams) (db.RestoreEntryTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntryForUpdate", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RestoreEntryTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEntryTx indicates an expected call of RestoreEntryTx.
+func (mr *MockStoreMockRecorder) RestoreEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEntryTx", reflect.TypeOf((*MockStore)(nil).RestoreEntryTx), arg0, arg1)
+}
+
+// RestoreHouseholdEntryTx mocks base method.
+func (m *MockStore) RestoreHouseholdEntryTx(arg0 context.Context, arg1 db.RestoreHouseholdEntryTxParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreHouseholdEntryTx", arg0, arg1)
 	ret0, _ := ret[0].(db.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntryForUpdate indicates an expected call of GetEntryForUpdate.
-func (mr *MockStoreMockRecorder) GetEntryForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// RestoreHouseholdEntryTx indicates an expected call of RestoreHouseholdEntryTx.
+func (mr *MockStoreMockRecorder) RestoreHouseholdEntryTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntryForUpdate", reflect.TypeOf((*MockStore)(nil).GetEntryForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreHouseholdEntryTx", reflect.TypeOf((*MockStore)(nil).RestoreHouseholdEntryTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// RestoreUser mocks base method.
+func (m *MockStore) RestoreUser(arg0 context.Context, arg1 string) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreUser", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockStoreMockRecorder) RestoreUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockStore)(nil).RestoreUser), arg0, arg1)
 }
 
-// GetUserForUpdate mocks base method.
-func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 string) (db.User, error) {
+// RestoreUserTx mocks base method.
+func (m *MockStore) RestoreUserTx(arg0 context.Context, arg1 string) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret := m.ctrl.Call(m, "RestoreUserTx", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserForUpdate indicates an expected call of GetUserForUpdate.
-func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// RestoreUserTx indicates an expected call of RestoreUserTx.
+func (mr *MockStoreMockRecorder) RestoreUserTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUserTx", reflect.TypeOf((*MockStore)(nil).RestoreUserTx), arg0, arg1)
 }
 
-// ListUsers mocks base method.
-func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
+// SaveIdempotencyResponse mocks base method.
+func (m *MockStore) SaveIdempotencyResponse(arg0 context.Context, arg1 db.SaveIdempotencyResponseParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
-	ret0, _ := ret[0].([]db.User)
+	ret := m.ctrl.Call(m, "SaveIdempotencyResponse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveIdempotencyResponse indicates an expected call of SaveIdempotencyResponse.
+func (mr *MockStoreMockRecorder) SaveIdempotencyResponse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdempotencyResponse", reflect.TypeOf((*MockStore)(nil).SaveIdempotencyResponse), arg0, arg1)
+}
+
+// SettleUpTx mocks base method.
+func (m *MockStore) SettleUpTx(arg0 context.Context, arg1 db.SettleUpTxParams) (db.SettleUpTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleUpTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SettleUpTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListUsers indicates an expected call of ListUsers.
-func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
+// SettleUpTx indicates an expected call of SettleUpTx.
+func (mr *MockStoreMockRecorder) SettleUpTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleUpTx", reflect.TypeOf((*MockStore)(nil).SettleUpTx), arg0, arg1)
 }
 
-// ResetPassword mocks base method.
-func (m *MockStore) ResetPassword(arg0 context.Context, arg1 db.ResetPasswordParams) error {
+// SplitExpenseTx mocks base method.
+func (m *MockStore) SplitExpenseTx(arg0 context.Context, arg1 db.SplitExpenseTxParams) (db.SplitExpenseTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetPassword", arg0, arg1)
+	ret := m.ctrl.Call(m, "SplitExpenseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SplitExpenseTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SplitExpenseTx indicates an expected call of SplitExpenseTx.
+func (mr *MockStoreMockRecorder) SplitExpenseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SplitExpenseTx", reflect.TypeOf((*MockStore)(nil).SplitExpenseTx), arg0, arg1)
+}
+
+// TakeRateLimitTokenTx mocks base method.
+func (m *MockStore) TakeRateLimitTokenTx(arg0 context.Context, arg1 db.TakeRateLimitTokenTxParams) (util.RateLimitResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitTokenTx", arg0, arg1)
+	ret0, _ := ret[0].(util.RateLimitResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitTokenTx indicates an expected call of TakeRateLimitTokenTx.
+func (mr *MockStoreMockRecorder) TakeRateLimitTokenTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitTokenTx", reflect.TypeOf((*MockStore)(nil).TakeRateLimitTokenTx), arg0, arg1)
+}
+
+// UpdateAccountTx mocks base method.
+func (m *MockStore) UpdateAccountTx(arg0 context.Context, arg1 db.UpdateAccountTxParams) (db.UpdateAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountTx indicates an expected call of UpdateAccountTx.
+func (mr *MockStoreMockRecorder) UpdateAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountTx", reflect.TypeOf((*MockStore)(nil).UpdateAccountTx), arg0, arg1)
+}
+
+// UpdateEntriesOwner mocks base method.
+func (m *MockStore) UpdateEntriesOwner(arg0 context.Context, arg1 db.UpdateEntriesOwnerParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEntriesOwner", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ResetPassword indicates an expected call of ResetPassword.
-func (mr *MockStoreMockRecorder) ResetPassword(arg0, arg1 interface{}) *gomock.Call {
+// UpdateEntriesOwner indicates an expected call of UpdateEntriesOwner.
+func (mr *MockStoreMockRecorder) UpdateEntriesOwner(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockStore)(nil).ResetPassword), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntriesOwner", reflect.TypeOf((*MockStore)(nil).UpdateEntriesOwner), arg0, arg1)
 }
 
 // UpdateEntry mocks base method.
@@ -316,6 +1639,95 @@ func (mr *MockStoreMockRecorder) UpdateEntryTx(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEntryTx", reflect.TypeOf((*MockStore)(nil).UpdateEntryTx), arg0, arg1)
 }
 
+// UpdateExpenseShare mocks base method.
+func (m *MockStore) UpdateExpenseShare(arg0 context.Context, arg1 db.UpdateExpenseShareParams) (db.ExpenseShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExpenseShare", arg0, arg1)
+	ret0, _ := ret[0].(db.ExpenseShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateExpenseShare indicates an expected call of UpdateExpenseShare.
+func (mr *MockStoreMockRecorder) UpdateExpenseShare(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpenseShare", reflect.TypeOf((*MockStore)(nil).UpdateExpenseShare), arg0, arg1)
+}
+
+// UpdateHouseholdEntry mocks base method.
+func (m *MockStore) UpdateHouseholdEntry(arg0 context.Context, arg1 db.UpdateHouseholdEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouseholdEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouseholdEntry indicates an expected call of UpdateHouseholdEntry.
+func (mr *MockStoreMockRecorder) UpdateHouseholdEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouseholdEntry", reflect.TypeOf((*MockStore)(nil).UpdateHouseholdEntry), arg0, arg1)
+}
+
+// UpdateHouseholdEntryTx mocks base method.
+func (m *MockStore) UpdateHouseholdEntryTx(arg0 context.Context, arg1 db.UpdateHouseholdEntryTxParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouseholdEntryTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouseholdEntryTx indicates an expected call of UpdateHouseholdEntryTx.
+func (mr *MockStoreMockRecorder) UpdateHouseholdEntryTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouseholdEntryTx", reflect.TypeOf((*MockStore)(nil).UpdateHouseholdEntryTx), arg0, arg1)
+}
+
+// UpdateHouseholdMemberRole mocks base method.
+func (m *MockStore) UpdateHouseholdMemberRole(arg0 context.Context, arg1 db.UpdateHouseholdMemberRoleParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouseholdMemberRole", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouseholdMemberRole indicates an expected call of UpdateHouseholdMemberRole.
+func (mr *MockStoreMockRecorder) UpdateHouseholdMemberRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouseholdMemberRole", reflect.TypeOf((*MockStore)(nil).UpdateHouseholdMemberRole), arg0, arg1)
+}
+
+// UpdateHouseholdMemberRoleTx mocks base method.
+func (m *MockStore) UpdateHouseholdMemberRoleTx(arg0 context.Context, arg1 db.UpdateHouseholdMemberRoleTxParams) (db.HouseholdMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateHouseholdMemberRoleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.HouseholdMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHouseholdMemberRoleTx indicates an expected call of UpdateHouseholdMemberRoleTx.
+func (mr *MockStoreMockRecorder) UpdateHouseholdMemberRoleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHouseholdMemberRoleTx", reflect.TypeOf((*MockStore)(nil).UpdateHouseholdMemberRoleTx), arg0, arg1)
+}
+
+// UpdateRateLimitBucket mocks base method.
+func (m *MockStore) UpdateRateLimitBucket(arg0 context.Context, arg1 db.UpdateRateLimitBucketParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRateLimitBucket", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRateLimitBucket indicates an expected call of UpdateRateLimitBucket.
+func (mr *MockStoreMockRecorder) UpdateRateLimitBucket(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRateLimitBucket", reflect.TypeOf((*MockStore)(nil).UpdateRateLimitBucket), arg0, arg1)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -330,3 +1742,18 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
+
+// UpdateUserInfo mocks base method.
+func (m *MockStore) UpdateUserInfo(arg0 context.Context, arg1 db.UpdateUserInfoParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserInfo", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserInfo indicates an expected call of UpdateUserInfo.
+func (mr *MockStoreMockRecorder) UpdateUserInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserInfo", reflect.TypeOf((*MockStore)(nil).UpdateUserInfo), arg0, arg1)
+}
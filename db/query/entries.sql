@@ -1,38 +1,39 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
-  owner, name, due_date, amount, category
+  owner, name, due_date, amount, category, created_by
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
 RETURNING *;
 
 -- name: GetEntries :many
 SELECT * FROM entries
-WHERE owner = $1;
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL;
 
 -- name: GetEntry :one
 SELECT * FROM entries
-WHERE owner = $1 AND id = $2;
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL;
 
 -- name: GetEntryForUpdate :one
 SELECT * FROM entries
-WHERE owner = $1 AND id = $2
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL
 FOR NO KEY UPDATE;
 
 -- name: GetCategories :many
 SELECT category FROM entries
-WHERE owner = $1 AND category != '' AND category IS NOT NULL
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL AND category != '' AND category IS NOT NULL
 GROUP BY category;
 
 -- name: UpdateEntry :one
 UPDATE entries
-SET name = $3, due_date = $4, amount = $5, category = $6
-WHERE owner = $1 AND id = $2
+SET name = $3, due_date = $4, amount = $5, category = $6, version = version + 1
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL
 RETURNING *;
 
 -- name: DeleteEntry :exec
-DELETE FROM entries
-WHERE id = $1;
+UPDATE entries
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: DeleteEntries :exec
 DELETE FROM entries
@@ -41,4 +42,82 @@ WHERE owner = $1;
 -- name: UpdateEntriesOwner :exec
 UPDATE entries
 SET owner = $2
-WHERE owner = $1;
\ No newline at end of file
+WHERE owner = $1;
+
+-- name: CreateHouseholdEntry :one
+INSERT INTO entries (
+  owner, name, due_date, amount, category, household_id, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $1
+)
+RETURNING *;
+
+-- name: GetHouseholdEntries :many
+SELECT * FROM entries
+WHERE household_id = $1 AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetEntriesOfHouseholds :many
+SELECT * FROM entries
+WHERE household_id = ANY(@household_ids::int[]) AND deleted_at IS NULL
+ORDER BY household_id, id;
+
+-- name: GetHouseholdEntry :one
+SELECT * FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL;
+
+-- name: GetHouseholdEntryForUpdate :one
+SELECT * FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+FOR NO KEY UPDATE;
+
+-- name: UpdateHouseholdEntry :one
+UPDATE entries
+SET name = $3, due_date = $4, amount = $5, category = $6, version = version + 1
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteHouseholdEntry :exec
+UPDATE entries
+SET deleted_at = now(), version = version + 1
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL;
+
+-- name: GetEntriesTotal :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL;
+
+-- name: GetHouseholdTotal :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE household_id = $1 AND deleted_at IS NULL;
+
+-- name: ListDeletedEntries :many
+SELECT * FROM entries
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetDeletedEntryForUpdate :one
+SELECT * FROM entries
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NOT NULL
+FOR NO KEY UPDATE;
+
+-- name: ListDeletedHouseholdEntries :many
+SELECT * FROM entries
+WHERE household_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetDeletedHouseholdEntryForUpdate :one
+SELECT * FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NOT NULL
+FOR NO KEY UPDATE;
+
+-- name: RestoreEntry :one
+UPDATE entries
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1
+RETURNING *;
+
+-- name: PurgeEntries :many
+DELETE FROM entries
+WHERE deleted_at < @deleted_before
+  OR owner IN (SELECT username FROM users WHERE deleted_at < @deleted_before)
+RETURNING *;
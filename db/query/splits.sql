@@ -0,0 +1,68 @@
+-- name: CreateSharedExpense :one
+INSERT INTO shared_expenses (
+  entry_id, paid_by, split_type
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetSharedExpenseByEntry :one
+SELECT * FROM shared_expenses
+WHERE entry_id = $1;
+
+-- name: CreateExpenseShare :one
+INSERT INTO expense_shares (
+  expense_id, username, amount
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListExpenseShares :many
+SELECT * FROM expense_shares
+WHERE expense_id = $1
+ORDER BY username;
+
+-- name: UpdateExpenseShare :one
+UPDATE expense_shares
+SET amount = $3
+WHERE expense_id = $1 AND username = $2
+RETURNING *;
+
+-- name: AddToBalance :one
+INSERT INTO balances (
+  user_a, user_b, amount
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (user_a, user_b) DO UPDATE
+SET amount = balances.amount + EXCLUDED.amount, updated_at = now()
+RETURNING *;
+
+-- name: GetBalanceForUpdate :one
+SELECT * FROM balances
+WHERE user_a = $1 AND user_b = $2
+FOR NO KEY UPDATE;
+
+-- name: ListBalances :many
+SELECT * FROM balances
+WHERE (user_a = @username OR user_b = @username) AND amount != 0
+ORDER BY user_a, user_b;
+
+-- name: ListBalancesAmong :many
+SELECT * FROM balances
+WHERE user_a = ANY(@usernames::varchar[]) AND user_b = ANY(@usernames::varchar[]) AND amount != 0
+ORDER BY user_a, user_b;
+
+-- name: CreateSettlement :one
+INSERT INTO settlements (
+  from_username, to_username, amount
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: ListSettlements :many
+SELECT * FROM settlements
+WHERE from_username = @username OR to_username = @username
+ORDER BY id DESC;
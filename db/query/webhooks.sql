@@ -0,0 +1,96 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  owner, url, secret, event_types, budget
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE owner = $1 AND id = $2;
+
+-- name: GetWebhookForDelivery :one
+SELECT * FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE owner = $1
+ORDER BY id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE owner = $1 AND id = $2;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  webhook_id, event_type, dedupe_key, payload
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: EnqueueWebhookEvent :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT id, @event_type::varchar, @dedupe_key::varchar, @payload::jsonb FROM webhooks
+WHERE owner = @owner AND @event_type = ANY(event_types)
+ON CONFLICT DO NOTHING;
+
+-- name: EnqueueBudgetExceeded :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT webhooks.id, 'budget.exceeded', @month::varchar,
+  jsonb_build_object('month', @month::varchar, 'total', month_total.total, 'budget', webhooks.budget)
+FROM webhooks, (
+  SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+  WHERE owner = @owner AND household_id IS NULL AND deleted_at IS NULL
+    AND due_date >= @month_start AND due_date < @month_end
+) AS month_total
+WHERE webhooks.owner = @owner AND 'budget.exceeded' = ANY(webhooks.event_types)
+  AND webhooks.budget > 0 AND month_total.total > webhooks.budget
+ON CONFLICT DO NOTHING;
+
+-- name: EnqueueBillDue :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT webhooks.id, 'bill.due', entries.id || ':' || to_char(entries.due_date, 'YYYY-MM-DD'),
+  jsonb_build_object('entry', jsonb_build_object(
+    'id', entries.id,
+    'name', entries.name,
+    'due_date', to_char(entries.due_date, 'YYYY-MM-DD'),
+    'amount', entries.amount,
+    'category', entries.category
+  ))
+FROM entries
+JOIN webhooks ON webhooks.owner = entries.owner AND 'bill.due' = ANY(webhooks.event_types)
+WHERE entries.household_id IS NULL AND entries.deleted_at IS NULL
+  AND entries.due_date >= @due_after AND entries.due_date < @due_before
+ON CONFLICT DO NOTHING;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, next_attempt_at = @lease_until
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY next_attempt_at
+  LIMIT @max_deliveries
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded', last_status_code = $2, last_error = '', delivered_at = now()
+WHERE id = $1;
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5
+WHERE id = $1;
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	// permanent deletion of what was in the trash
+	AuditActionPurge         = "purge"
+	AuditActionResetPassword = "reset_password"
+)
+
+// Entity types recorded in the audit log
+const (
+	AuditEntityEntry           = "entry"
+	AuditEntityUser            = "user"
+	AuditEntityHousehold       = "household"
+	AuditEntityHouseholdMember = "household_member"
+	AuditEntitySharedExpense   = "shared_expense"
+	AuditEntitySettlement      = "settlement"
+	AuditEntityInvitation      = "household_invitation"
+	AuditEntityWebhook         = "webhook"
+)
+
+// Actor of the changes made by the background jobs and admin commands rather
+// than a user. It isn't a valid username, so no user lists the events as theirs.
+const AuditActorSystem = "@system"
+
+type auditMetadataKey struct{}
+
+// AuditMetadata describes the request that triggered a data-changing transaction
+type AuditMetadata struct {
+	ClientIP  string
+	RequestID string
+}
+
+// WithAuditMetadata returns a copy of ctx carrying the request metadata for the audit log
+func WithAuditMetadata(ctx context.Context, metadata AuditMetadata) context.Context {
+	return context.WithValue(ctx, auditMetadataKey{}, metadata)
+}
+
+func auditMetadataFromContext(ctx context.Context) AuditMetadata {
+	metadata, _ := ctx.Value(auditMetadataKey{}).(AuditMetadata)
+	return metadata
+}
+
+// auditedUser is the part of a user that is safe to keep in the audit log
+type auditedUser struct {
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	Email         string `json:"email"`
+	TotalExpenses int64  `json:"total_expenses"`
+}
+
+func newAuditedUser(user User) auditedUser {
+	return auditedUser{
+		Username:      user.Username,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		TotalExpenses: user.TotalExpenses,
+	}
+}
+
+// auditedWebhook is the part of a webhook that is safe to keep in the audit log,
+// without its signing secret
+type auditedWebhook struct {
+	ID         int32    `json:"id"`
+	Owner      string   `json:"owner"`
+	Url        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Budget     int64    `json:"budget"`
+}
+
+func newAuditedWebhook(webhook Webhook) auditedWebhook {
+	return auditedWebhook{
+		ID:         webhook.ID,
+		Owner:      webhook.Owner,
+		Url:        webhook.Url,
+		EventTypes: webhook.EventTypes,
+		Budget:     webhook.Budget,
+	}
+}
+
+// Records a change in the audit log using the queries of the running transaction.
+// before and after are marshalled to JSON, nil is stored as JSON null.
+func recordAuditEvent(ctx context.Context, q Querier, actor string, action string, entityType string, entityID interface{}, before interface{}, after interface{}) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	metadata := auditMetadataFromContext(ctx)
+	arg := CreateAuditEventParams{
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Before:     beforeJSON,
+		After:      afterJSON,
+		ClientIp:   metadata.ClientIP,
+		RequestID:  metadata.RequestID,
+	}
+
+	_, err = q.CreateAuditEvent(ctx, arg)
+	return err
+}
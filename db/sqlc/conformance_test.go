@@ -0,0 +1,777 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/stretchr/testify/require"
+)
+
+// Behavior every Store implementation shares. The data is random so the suite
+// can run against a database other tests use.
+func testStoreConformance(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	t.Run("UniqueUsers", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+
+		_, err := store.CreateUser(ctx, CreateUserParams{
+			Username:       user.Username,
+			HashedPassword: "secret",
+			FullName:       util.RandomFullName(),
+			Email:          util.RandomEmail(),
+		})
+		requireViolation(t, err, UniqueViolation, "users_pkey")
+
+		_, err = store.CreateUser(ctx, CreateUserParams{
+			Username:       util.RandomString(8),
+			HashedPassword: "secret",
+			FullName:       util.RandomFullName(),
+			Email:          user.Email,
+		})
+		requireViolation(t, err, UniqueViolation, "users_email_key")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+
+		_, err := store.GetUser(ctx, util.RandomString(8))
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = store.GetEntry(ctx, GetEntryParams{Owner: user.Username, ID: -1})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = store.GetHousehold(ctx, -1)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = store.GetWebhook(ctx, GetWebhookParams{ID: -1, Owner: user.Username})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("EntriesAreScopedToTheirOwner", func(t *testing.T) {
+		owner := newConformanceUser(t, store)
+		other := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, owner.Username, 100)
+
+		_, err := store.GetEntry(ctx, GetEntryParams{Owner: other.Username, ID: entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		_, err = store.UpdateEntryTx(ctx, UpdateEntryTxParams{
+			Username: other.Username,
+			ID:       entry.ID,
+			Name:     entry.Name,
+			DueDate:  entry.DueDate,
+			Amount:   1,
+		})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		entries, err := store.GetEntries(ctx, other.Username)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("TotalExpenses", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+
+		added, err := store.AddEntryTx(ctx, AddEntryTxParams{
+			Username: user.Username,
+			Name:     util.RandomString(10),
+			DueDate:  conformanceDate,
+			Amount:   100,
+			Category: "bills",
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(100), added.User.TotalExpenses)
+		require.Equal(t, int32(1), added.Entry.Version)
+
+		updated, err := store.UpdateEntryTx(ctx, UpdateEntryTxParams{
+			Username: user.Username,
+			ID:       added.Entry.ID,
+			Name:     added.Entry.Name,
+			DueDate:  added.Entry.DueDate,
+			Amount:   40,
+			Version:  added.Entry.Version,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(40), updated.User.TotalExpenses)
+		require.Equal(t, int32(2), updated.Entry.Version)
+
+		_, err = store.DeleteEntryTx(ctx, DeleteEntryTxParams{
+			Username: user.Username,
+			ID:       added.Entry.ID,
+			Version:  added.Entry.Version,
+		})
+		var mismatch *VersionMismatchError
+		require.ErrorAs(t, err, &mismatch)
+		require.Equal(t, updated.Entry.Version, mismatch.Current.Version)
+
+		deleted, err := store.DeleteEntryTx(ctx, DeleteEntryTxParams{
+			Username: user.Username,
+			ID:       added.Entry.ID,
+		})
+		require.NoError(t, err)
+		require.Zero(t, deleted.User.TotalExpenses)
+
+		_, err = store.GetEntry(ctx, GetEntryParams{Owner: user.Username, ID: added.Entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		trash, err := store.ListDeletedEntries(ctx, user.Username)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+
+		restored, err := store.RestoreEntryTx(ctx, RestoreEntryTxParams{
+			Username: user.Username,
+			ID:       added.Entry.ID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(40), restored.User.TotalExpenses)
+
+		drifts, err := store.ListTotalExpensesDrift(ctx)
+		require.NoError(t, err)
+		for _, drift := range drifts {
+			require.NotEqual(t, user.Username, drift.Username)
+		}
+	})
+
+	t.Run("UniqueEntryNames", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, user.Username, 100)
+
+		_, err := store.AddEntryTx(ctx, AddEntryTxParams{
+			Username: user.Username,
+			Name:     entry.Name,
+			DueDate:  conformanceDate,
+			Amount:   1,
+		})
+		requireViolation(t, err, UniqueViolation, "entries_name_key")
+
+		// names are only unique outside of the trash
+		_, err = store.DeleteEntryTx(ctx, DeleteEntryTxParams{Username: user.Username, ID: entry.ID})
+		require.NoError(t, err)
+
+		added, err := store.AddEntryTx(ctx, AddEntryTxParams{
+			Username: user.Username,
+			Name:     entry.Name,
+			DueDate:  conformanceDate,
+			Amount:   1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), added.User.TotalExpenses)
+	})
+
+	t.Run("UnknownOwner", func(t *testing.T) {
+		_, err := store.CreateEntry(ctx, CreateEntryParams{
+			Owner:   util.RandomString(8),
+			Name:    util.RandomString(10),
+			DueDate: conformanceDate,
+			Amount:  1,
+		})
+		code, _ := ConstraintViolation(err)
+		require.Equal(t, ForeignKeyViolation, code)
+	})
+
+	t.Run("BatchEntries", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, user.Username, 100)
+
+		operations := []EntryOperation{
+			{Op: BatchCreate, Name: util.RandomString(10), DueDate: conformanceDate, Amount: 10},
+			{Op: BatchCreate, Name: entry.Name, DueDate: conformanceDate, Amount: 20},
+			{Op: BatchDelete, ID: entry.ID},
+		}
+
+		_, err := store.BatchEntriesTx(ctx, BatchEntriesTxParams{
+			Username:   user.Username,
+			Operations: operations,
+		})
+		var batchErr *BatchOperationError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, 1, batchErr.Index)
+
+		entries, err := store.GetEntries(ctx, user.Username)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		result, err := store.BatchEntriesTx(ctx, BatchEntriesTxParams{
+			Username:   user.Username,
+			Operations: operations,
+			BestEffort: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Results, 3)
+		require.NoError(t, result.Results[0].Err)
+		require.Error(t, result.Results[1].Err)
+		require.NoError(t, result.Results[2].Err)
+		require.Equal(t, int64(10), result.User.TotalExpenses)
+
+		entries, err = store.GetEntries(ctx, user.Username)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, operations[0].Name, entries[0].Name)
+	})
+
+	t.Run("Households", func(t *testing.T) {
+		owner := newConformanceUser(t, store)
+		invitee := newConformanceUser(t, store)
+		stranger := newConformanceUser(t, store)
+
+		created, err := store.CreateHouseholdTx(ctx, CreateHouseholdTxParams{
+			Name:     util.RandomString(8),
+			Username: owner.Username,
+		})
+		require.NoError(t, err)
+		require.Equal(t, util.OwnerRole, created.Member.Role)
+
+		_, err = store.AddHouseholdMember(ctx, AddHouseholdMemberParams{
+			HouseholdID: created.Household.ID,
+			Username:    owner.Username,
+			Role:        util.EditorRole,
+		})
+		requireViolation(t, err, UniqueViolation, "household_members_pkey")
+
+		invitation, err := store.CreateHouseholdInvitation(ctx, CreateHouseholdInvitationParams{
+			HouseholdID: created.Household.ID,
+			Email:       invitee.Email,
+			Role:        util.EditorRole,
+			InvitedBy:   owner.Username,
+		})
+		require.NoError(t, err)
+
+		_, err = store.AcceptHouseholdInvitationTx(ctx, AcceptHouseholdInvitationTxParams{
+			InvitationID: invitation.ID,
+			Username:     stranger.Username,
+			Email:        stranger.Email,
+		})
+		require.ErrorIs(t, err, ErrInvitationNotValid)
+
+		accepted, err := store.AcceptHouseholdInvitationTx(ctx, AcceptHouseholdInvitationTxParams{
+			InvitationID: invitation.ID,
+			Username:     invitee.Username,
+			Email:        invitee.Email,
+		})
+		require.NoError(t, err)
+		require.True(t, accepted.Invitation.Accepted)
+		require.Equal(t, util.EditorRole, accepted.Member.Role)
+
+		members, err := store.ListHouseholdMembers(ctx, created.Household.ID)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+
+		households, err := store.ListHouseholds(ctx, stranger.Username)
+		require.NoError(t, err)
+		require.Empty(t, households)
+
+		require.NoError(t, store.DeleteHousehold(ctx, created.Household.ID))
+
+		members, err = store.ListHouseholdMembers(ctx, created.Household.ID)
+		require.NoError(t, err)
+		require.Empty(t, members)
+	})
+
+	t.Run("HouseholdTrash", func(t *testing.T) {
+		owner := newConformanceUser(t, store)
+		household := newConformanceHousehold(t, store, owner.Username)
+		other := newConformanceHousehold(t, store, owner.Username)
+		householdID := sql.NullInt32{Int32: household.ID, Valid: true}
+
+		entry, err := store.CreateHouseholdEntryTx(ctx, CreateHouseholdEntryParams{
+			Owner:       owner.Username,
+			Name:        util.RandomString(10),
+			DueDate:     conformanceDate,
+			Amount:      100,
+			HouseholdID: householdID,
+		})
+		require.NoError(t, err)
+
+		// entries still in use are not in the trash
+		_, err = store.RestoreHouseholdEntryTx(ctx, RestoreHouseholdEntryTxParams{Username: owner.Username, HouseholdID: household.ID, ID: entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		err = store.DeleteHouseholdEntryTx(ctx, DeleteHouseholdEntryTxParams{Username: owner.Username, HouseholdID: household.ID, ID: entry.ID})
+		require.NoError(t, err)
+
+		trash, err := store.ListDeletedHouseholdEntries(ctx, householdID)
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		require.Equal(t, entry.ID, trash[0].ID)
+
+		// the household entry is neither in the personal trash nor in other households'
+		personal, err := store.ListDeletedEntries(ctx, owner.Username)
+		require.NoError(t, err)
+		require.Empty(t, personal)
+		_, err = store.RestoreHouseholdEntryTx(ctx, RestoreHouseholdEntryTxParams{Username: owner.Username, HouseholdID: other.ID, ID: entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		restored, err := store.RestoreHouseholdEntryTx(ctx, RestoreHouseholdEntryTxParams{Username: owner.Username, HouseholdID: household.ID, ID: entry.ID})
+		require.NoError(t, err)
+		require.False(t, restored.DeletedAt.Valid)
+		require.Equal(t, entry.Version+2, restored.Version)
+
+		trash, err = store.ListDeletedHouseholdEntries(ctx, householdID)
+		require.NoError(t, err)
+		require.Empty(t, trash)
+
+		total, err := store.GetHouseholdTotal(ctx, householdID)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), total)
+	})
+
+	t.Run("SplitsAndSettlements", func(t *testing.T) {
+		payer := newConformanceUser(t, store)
+		friend := newConformanceUser(t, store)
+		stranger := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, payer.Username, 100)
+		newConformanceHousehold(t, store, payer.Username, friend.Username)
+
+		// only members of the payer's households can be split with
+		_, err := store.SplitExpenseTx(ctx, SplitExpenseTxParams{
+			Username:  payer.Username,
+			EntryID:   entry.ID,
+			SplitType: util.EqualSplit,
+			Shares: []ExpenseShareParams{
+				{Username: payer.Username},
+				{Username: stranger.Username},
+			},
+		})
+		require.ErrorIs(t, err, ErrShareNotHouseholdMember)
+
+		split, err := store.SplitExpenseTx(ctx, SplitExpenseTxParams{
+			Username:  payer.Username,
+			EntryID:   entry.ID,
+			SplitType: util.EqualSplit,
+			Shares: []ExpenseShareParams{
+				{Username: payer.Username},
+				{Username: friend.Username},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, split.Shares, 2)
+
+		_, err = store.SplitExpenseTx(ctx, SplitExpenseTxParams{
+			Username:  payer.Username,
+			EntryID:   entry.ID,
+			SplitType: util.EqualSplit,
+			Shares:    []ExpenseShareParams{{Username: friend.Username}},
+		})
+		requireViolation(t, err, UniqueViolation, "shared_expenses_entry_id_key")
+
+		requireOwed(t, store, payer.Username, friend.Username, 50)
+
+		// changing the amount of the entry splits the new amount
+		_, err = store.UpdateEntryTx(ctx, UpdateEntryTxParams{
+			Username: payer.Username,
+			ID:       entry.ID,
+			Name:     entry.Name,
+			DueDate:  entry.DueDate,
+			Amount:   120,
+		})
+		require.NoError(t, err)
+		requireOwed(t, store, payer.Username, friend.Username, 60)
+
+		shares, err := store.ListExpenseShares(ctx, split.Expense.ID)
+		require.NoError(t, err)
+		require.Len(t, shares, 2)
+		require.Equal(t, int64(60), shares[0].Amount)
+		require.Equal(t, int64(60), shares[1].Amount)
+
+		settled, err := store.SettleUpTx(ctx, SettleUpTxParams{
+			FromUsername: friend.Username,
+			ToUsername:   payer.Username,
+			Amount:       30,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(30), settled.Settlement.Amount)
+		requireOwed(t, store, payer.Username, friend.Username, 30)
+
+		_, err = store.SettleUpTx(ctx, SettleUpTxParams{
+			FromUsername: friend.Username,
+			ToUsername:   payer.Username,
+			Amount:       0,
+		})
+		require.Error(t, err)
+
+		// no one repays more than they owe, nor anyone outside their households
+		_, err = store.SettleUpTx(ctx, SettleUpTxParams{
+			FromUsername: friend.Username,
+			ToUsername:   payer.Username,
+			Amount:       31,
+		})
+		require.ErrorIs(t, err, ErrSettlementExceedsDebt)
+		_, err = store.SettleUpTx(ctx, SettleUpTxParams{
+			FromUsername: payer.Username,
+			ToUsername:   friend.Username,
+			Amount:       1,
+		})
+		require.ErrorIs(t, err, ErrSettlementExceedsDebt)
+		_, err = store.SettleUpTx(ctx, SettleUpTxParams{
+			FromUsername: stranger.Username,
+			ToUsername:   payer.Username,
+			Amount:       1,
+		})
+		require.ErrorIs(t, err, ErrShareNotHouseholdMember)
+		requireOwed(t, store, payer.Username, friend.Username, 30)
+
+		// trashing the entry reverses its split
+		_, err = store.DeleteEntryTx(ctx, DeleteEntryTxParams{Username: payer.Username, ID: entry.ID})
+		require.NoError(t, err)
+		requireOwed(t, store, payer.Username, friend.Username, -30)
+	})
+
+	t.Run("IdempotencyKeys", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		arg := CreateIdempotencyKeyParams{
+			Username:    user.Username,
+			Key:         util.RandomString(12),
+			RequestHash: util.RandomString(12),
+		}
+
+		key, err := store.CreateIdempotencyKey(ctx, arg)
+		require.NoError(t, err)
+		require.Equal(t, arg.Key, key.Key)
+
+		// a key that already exists is left as is
+		_, err = store.CreateIdempotencyKey(ctx, arg)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		err = store.SaveIdempotencyResponse(ctx, SaveIdempotencyResponseParams{
+			Username:     arg.Username,
+			Key:          arg.Key,
+			StatusCode:   404,
+			ResponseBody: []byte(`{"status":404}`),
+			ContentType:  "application/problem+json",
+		})
+		require.NoError(t, err)
+
+		key, err = store.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{Username: arg.Username, Key: arg.Key})
+		require.NoError(t, err)
+		require.Equal(t, int32(404), key.StatusCode)
+		require.Equal(t, []byte(`{"status":404}`), key.ResponseBody)
+		require.Equal(t, "application/problem+json", key.ContentType)
+	})
+
+	t.Run("RateLimit", func(t *testing.T) {
+		arg := TakeRateLimitTokenTxParams{
+			Key:   util.RandomString(12),
+			Limit: util.RateLimit{Requests: 2, Period: time.Minute},
+			Now:   time.Now(),
+		}
+
+		for i := 0; i < 2; i++ {
+			result, err := store.TakeRateLimitTokenTx(ctx, arg)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+		}
+
+		result, err := store.TakeRateLimitTokenTx(ctx, arg)
+		require.NoError(t, err)
+		require.False(t, result.Allowed)
+		require.Positive(t, result.RetryAfter)
+	})
+
+	t.Run("Webhooks", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+
+		webhook, err := store.CreateWebhook(ctx, CreateWebhookParams{
+			Owner:      user.Username,
+			Url:        "https://example.com/hook",
+			Secret:     util.RandomString(16),
+			EventTypes: []string{util.EntryCreatedEvent},
+		})
+		require.NoError(t, err)
+
+		newConformanceEntry(t, store, user.Username, 100)
+
+		deliveries, err := store.ListWebhookDeliveries(ctx, ListWebhookDeliveriesParams{
+			WebhookID: webhook.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, util.EntryCreatedEvent, deliveries[0].EventType)
+		require.Equal(t, "pending", deliveries[0].Status)
+
+		_, err = store.CreateWebhookDelivery(ctx, CreateWebhookDeliveryParams{
+			WebhookID: webhook.ID,
+			EventType: deliveries[0].EventType,
+			DedupeKey: deliveries[0].DedupeKey,
+			Payload:   deliveries[0].Payload,
+		})
+		requireViolation(t, err, UniqueViolation, "webhook_deliveries_webhook_id_event_type_dedupe_key_idx")
+
+		deleted, err := store.DeleteWebhook(ctx, DeleteWebhookParams{ID: webhook.ID, Owner: user.Username})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		deliveries, err = store.ListWebhookDeliveries(ctx, ListWebhookDeliveriesParams{
+			WebhookID: webhook.ID,
+			Limit:     10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("ReconcileTotalExpenses", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		newConformanceEntry(t, store, user.Username, 100)
+
+		_, err := store.UpdateUser(ctx, UpdateUserParams{Username: user.Username, TotalExpenses: 7})
+		require.NoError(t, err)
+
+		result, err := store.ReconcileTotalExpensesTx(ctx, user.Username)
+		require.NoError(t, err)
+		require.True(t, result.Corrected)
+		require.Equal(t, int64(7), result.TotalExpenses)
+		require.Equal(t, int64(100), result.EntriesTotal)
+
+		reconciled, err := store.GetUser(ctx, user.Username)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), reconciled.TotalExpenses)
+
+		// the correction is audited as the system's
+		events, err := store.ListAuditEvents(ctx, ListAuditEventsParams{Actor: AuditActorSystem, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, AuditActionUpdate, events[0].Action)
+		require.Equal(t, AuditEntityUser, events[0].EntityType)
+		require.Equal(t, user.Username, events[0].EntityID)
+	})
+
+	t.Run("AuditEvents", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, user.Username, 100)
+
+		_, err := store.DeleteEntryTx(ctx, DeleteEntryTxParams{Username: user.Username, ID: entry.ID})
+		require.NoError(t, err)
+
+		events, err := store.ListAuditEvents(ctx, ListAuditEventsParams{Actor: user.Username, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, AuditActionDelete, events[0].Action)
+		require.Equal(t, AuditActionCreate, events[1].Action)
+	})
+
+	t.Run("AuditedChanges", func(t *testing.T) {
+		owner, err := store.CreateUserTx(ctx, CreateUserParams{
+			Username:       util.RandomString(8),
+			HashedPassword: "secret",
+			FullName:       util.RandomFullName(),
+			Email:          util.RandomEmail(),
+		})
+		require.NoError(t, err)
+		invitee := newConformanceUser(t, store)
+
+		err = store.ResetPasswordTx(ctx, ResetPasswordParams{Username: owner.Username, HashedPassword: "changed"})
+		require.NoError(t, err)
+		err = store.ResetPasswordTx(ctx, ResetPasswordParams{Username: util.RandomString(8), HashedPassword: "changed"})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		created, err := store.CreateHouseholdTx(ctx, CreateHouseholdTxParams{Name: util.RandomString(8), Username: owner.Username})
+		require.NoError(t, err)
+		householdID := created.Household.ID
+
+		invitation, err := store.CreateHouseholdInvitationTx(ctx, CreateHouseholdInvitationParams{
+			HouseholdID: householdID,
+			Email:       invitee.Email,
+			Role:        util.EditorRole,
+			InvitedBy:   owner.Username,
+		})
+		require.NoError(t, err)
+		_, err = store.AcceptHouseholdInvitationTx(ctx, AcceptHouseholdInvitationTxParams{
+			InvitationID: invitation.ID,
+			Username:     invitee.Username,
+			Email:        invitee.Email,
+		})
+		require.NoError(t, err)
+
+		member, err := store.UpdateHouseholdMemberRoleTx(ctx, UpdateHouseholdMemberRoleTxParams{
+			Username:    owner.Username,
+			HouseholdID: householdID,
+			Member:      invitee.Username,
+			Role:        util.ViewerRole,
+		})
+		require.NoError(t, err)
+		require.Equal(t, util.ViewerRole, member.Role)
+
+		entry, err := store.CreateHouseholdEntryTx(ctx, CreateHouseholdEntryParams{
+			Owner:       owner.Username,
+			Name:        util.RandomString(10),
+			DueDate:     conformanceDate,
+			Amount:      100,
+			HouseholdID: sql.NullInt32{Int32: householdID, Valid: true},
+		})
+		require.NoError(t, err)
+
+		updated, err := store.UpdateHouseholdEntryTx(ctx, UpdateHouseholdEntryTxParams{
+			Username:    owner.Username,
+			HouseholdID: householdID,
+			ID:          entry.ID,
+			Name:        entry.Name,
+			DueDate:     entry.DueDate,
+			Amount:      200,
+			Version:     entry.Version,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(200), updated.Amount)
+		require.Equal(t, entry.Version+1, updated.Version)
+
+		// a member working on a stale version doesn't overwrite the change
+		var mismatch *VersionMismatchError
+		_, err = store.UpdateHouseholdEntryTx(ctx, UpdateHouseholdEntryTxParams{
+			Username:    owner.Username,
+			HouseholdID: householdID,
+			ID:          entry.ID,
+			Name:        entry.Name,
+			DueDate:     entry.DueDate,
+			Amount:      300,
+			Version:     entry.Version,
+		})
+		require.ErrorAs(t, err, &mismatch)
+		require.Equal(t, updated.Version, mismatch.Current.Version)
+		err = store.DeleteHouseholdEntryTx(ctx, DeleteHouseholdEntryTxParams{Username: owner.Username, HouseholdID: householdID, ID: entry.ID, Version: entry.Version})
+		require.ErrorAs(t, err, &mismatch)
+
+		// entries of other households, and entries already deleted, are not found
+		err = store.DeleteHouseholdEntryTx(ctx, DeleteHouseholdEntryTxParams{Username: owner.Username, HouseholdID: householdID + 1, ID: entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		err = store.DeleteHouseholdEntryTx(ctx, DeleteHouseholdEntryTxParams{Username: owner.Username, HouseholdID: householdID, ID: entry.ID, Version: updated.Version})
+		require.NoError(t, err)
+		err = store.DeleteHouseholdEntryTx(ctx, DeleteHouseholdEntryTxParams{Username: owner.Username, HouseholdID: householdID, ID: entry.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		err = store.DeleteHouseholdMemberTx(ctx, DeleteHouseholdMemberTxParams{Username: owner.Username, HouseholdID: householdID, Member: invitee.Username})
+		require.NoError(t, err)
+		err = store.DeleteHouseholdMemberTx(ctx, DeleteHouseholdMemberTxParams{Username: owner.Username, HouseholdID: householdID, Member: invitee.Username})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+
+		webhook, err := store.CreateWebhookTx(ctx, CreateWebhookParams{
+			Owner:      owner.Username,
+			Url:        "https://example.com/hook",
+			Secret:     "signing-secret",
+			EventTypes: []string{util.EntryCreatedEvent},
+		})
+		require.NoError(t, err)
+		err = store.DeleteWebhookTx(ctx, DeleteWebhookParams{Owner: invitee.Username, ID: webhook.ID})
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		err = store.DeleteWebhookTx(ctx, DeleteWebhookParams{Owner: owner.Username, ID: webhook.ID})
+		require.NoError(t, err)
+
+		events, err := store.ListAuditEvents(ctx, ListAuditEventsParams{Actor: owner.Username, Limit: 20})
+		require.NoError(t, err)
+
+		// newest first, after the signup and the creation of the household
+		var changes []string
+		for _, event := range events {
+			changes = append(changes, event.Action+" "+event.EntityType)
+			require.NotContains(t, string(event.Before), "signing-secret")
+			require.NotContains(t, string(event.After), "signing-secret")
+			require.NotContains(t, string(event.After), "changed")
+		}
+		require.Equal(t, []string{
+			AuditActionDelete + " " + AuditEntityWebhook,
+			AuditActionCreate + " " + AuditEntityWebhook,
+			AuditActionDelete + " " + AuditEntityHouseholdMember,
+			AuditActionDelete + " " + AuditEntityEntry,
+			AuditActionUpdate + " " + AuditEntityEntry,
+			AuditActionCreate + " " + AuditEntityEntry,
+			AuditActionUpdate + " " + AuditEntityHouseholdMember,
+			AuditActionCreate + " " + AuditEntityInvitation,
+			AuditActionCreate + " " + AuditEntityHousehold,
+			AuditActionResetPassword + " " + AuditEntityUser,
+			AuditActionCreate + " " + AuditEntityUser,
+		}, changes)
+	})
+
+	t.Run("FailedTransactionsRollBack", func(t *testing.T) {
+		user := newConformanceUser(t, store)
+		entry := newConformanceEntry(t, store, user.Username, 100)
+
+		_, err := store.BatchEntriesTx(ctx, BatchEntriesTxParams{
+			Username: user.Username,
+			Operations: []EntryOperation{
+				{Op: BatchDelete, ID: entry.ID},
+				{Op: "rename"},
+			},
+		})
+		var batchErr *BatchOperationError
+		require.ErrorAs(t, err, &batchErr)
+		require.Equal(t, 1, batchErr.Index)
+
+		stored, err := store.GetUser(ctx, user.Username)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), stored.TotalExpenses)
+
+		_, err = store.GetEntry(ctx, GetEntryParams{Owner: user.Username, ID: entry.ID})
+		require.NoError(t, err)
+	})
+}
+
+var conformanceDate = time.Date(2022, 12, 11, 0, 0, 0, 0, time.UTC)
+
+func newConformanceUser(t *testing.T, store Store) User {
+	user, err := store.CreateUser(context.Background(), CreateUserParams{
+		Username:       util.RandomString(8),
+		HashedPassword: "secret",
+		FullName:       util.RandomFullName(),
+		Email:          util.RandomEmail(),
+	})
+	require.NoError(t, err)
+	return user
+}
+
+func newConformanceEntry(t *testing.T, store Store, username string, amount int64) Entry {
+	result, err := store.AddEntryTx(context.Background(), AddEntryTxParams{
+		Username: username,
+		Name:     util.RandomString(10),
+		DueDate:  conformanceDate,
+		Amount:   amount,
+	})
+	require.NoError(t, err)
+	return result.Entry
+}
+
+// Creates a household owned by the first user the others are members of
+func newConformanceHousehold(t *testing.T, store Store, owner string, members ...string) Household {
+	created, err := store.CreateHouseholdTx(context.Background(), CreateHouseholdTxParams{
+		Name:     util.RandomString(8),
+		Username: owner,
+	})
+	require.NoError(t, err)
+
+	for _, member := range members {
+		_, err = store.AddHouseholdMember(context.Background(), AddHouseholdMemberParams{
+			HouseholdID: created.Household.ID,
+			Username:    member,
+			Role:        util.EditorRole,
+		})
+		require.NoError(t, err)
+	}
+
+	return created.Household
+}
+
+func requireViolation(t *testing.T, err error, wantCode string, wantConstraint string) {
+	require.Error(t, err)
+	code, constraint := ConstraintViolation(err)
+	require.Equal(t, wantCode, code)
+	require.Equal(t, wantConstraint, constraint)
+}
+
+// Checks that debtor owes amount to creditor, a negative amount when it is the other way around
+func requireOwed(t *testing.T, store Store, creditor string, debtor string, amount int64) {
+	balances, err := store.ListBalancesAmong(context.Background(), []string{creditor, debtor})
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+
+	if balances[0].UserA == creditor {
+		require.Equal(t, amount, balances[0].Amount)
+	} else {
+		require.Equal(t, -amount, balances[0].Amount)
+	}
+}
+
+func TestSQLStoreConformance(t *testing.T) {
+	testStoreConformance(t, newTestStore())
+}
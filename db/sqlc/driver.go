@@ -0,0 +1,56 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/sqlite"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// Databases the store runs on, selected by DB_DRIVER
+const (
+	PostgresDriver = "postgres"
+	SQLiteDriver   = sqlite.DriverName
+)
+
+// Names of the constraint violations, as Postgres reports them
+const (
+	UniqueViolation     = sqlite.UniqueViolation
+	ForeignKeyViolation = sqlite.ForeignKeyViolation
+)
+
+// Opens the database of source with driver, Postgres or SQLite
+func Open(driver string, source string) (*sql.DB, error) {
+	switch driver {
+	case PostgresDriver:
+		return sql.Open(driver, source)
+	case SQLiteDriver:
+		return sqlite.Open(source)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// Creates the store of a database opened by Open with driver
+func NewStoreForDriver(driver string, db *sql.DB, logger zerolog.Logger) Store {
+	if driver == SQLiteDriver {
+		return NewSQLiteStore(db, logger)
+	}
+	return NewStore(db, logger)
+}
+
+// Returns the kind of constraint violation err reports, and the name of the
+// constraint when the database tells, alike for Postgres and SQLite. code is
+// empty for other errors.
+func ConstraintViolation(err error) (code string, constraint string) {
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		return pqError.Code.Name(), pqError.Constraint
+	}
+
+	code, constraint, _ = sqlite.ConstraintViolation(err)
+	return code, constraint
+}
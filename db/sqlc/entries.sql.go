@@ -9,23 +9,26 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
-  owner, name, due_date, amount, category
+  owner, name, due_date, amount, category, created_by
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6
 )
-RETURNING id, owner, name, due_date, amount, category
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
 `
 
 type CreateEntryParams struct {
-	Owner    string         `json:"owner"`
-	Name     string         `json:"name"`
-	DueDate  time.Time      `json:"due_date"`
-	Amount   int64          `json:"amount"`
-	Category sql.NullString `json:"category"`
+	Owner     string         `json:"owner"`
+	Name      string         `json:"name"`
+	DueDate   time.Time      `json:"due_date"`
+	Amount    int64          `json:"amount"`
+	Category  sql.NullString `json:"category"`
+	CreatedBy sql.NullString `json:"created_by"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
@@ -35,6 +38,7 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 		arg.DueDate,
 		arg.Amount,
 		arg.Category,
+		arg.CreatedBy,
 	)
 	var i Entry
 	err := row.Scan(
@@ -44,6 +48,53 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 		&i.DueDate,
 		&i.Amount,
 		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const createHouseholdEntry = `-- name: CreateHouseholdEntry :one
+INSERT INTO entries (
+  owner, name, due_date, amount, category, household_id, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $1
+)
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
+`
+
+type CreateHouseholdEntryParams struct {
+	Owner       string         `json:"owner"`
+	Name        string         `json:"name"`
+	DueDate     time.Time      `json:"due_date"`
+	Amount      int64          `json:"amount"`
+	Category    sql.NullString `json:"category"`
+	HouseholdID sql.NullInt32  `json:"household_id"`
+}
+
+func (q *Queries) CreateHouseholdEntry(ctx context.Context, arg CreateHouseholdEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, createHouseholdEntry,
+		arg.Owner,
+		arg.Name,
+		arg.DueDate,
+		arg.Amount,
+		arg.Category,
+		arg.HouseholdID,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -59,8 +110,9 @@ func (q *Queries) DeleteEntries(ctx context.Context, owner string) error {
 }
 
 const deleteEntry = `-- name: DeleteEntry :exec
-DELETE FROM entries
-WHERE id = $1
+UPDATE entries
+SET deleted_at = now(), version = version + 1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) DeleteEntry(ctx context.Context, id int32) error {
@@ -68,9 +120,25 @@ func (q *Queries) DeleteEntry(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteHouseholdEntry = `-- name: DeleteHouseholdEntry :exec
+UPDATE entries
+SET deleted_at = now(), version = version + 1
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+`
+
+type DeleteHouseholdEntryParams struct {
+	HouseholdID sql.NullInt32 `json:"household_id"`
+	ID          int32         `json:"id"`
+}
+
+func (q *Queries) DeleteHouseholdEntry(ctx context.Context, arg DeleteHouseholdEntryParams) error {
+	_, err := q.db.ExecContext(ctx, deleteHouseholdEntry, arg.HouseholdID, arg.ID)
+	return err
+}
+
 const getCategories = `-- name: GetCategories :many
 SELECT category FROM entries
-WHERE owner = $1 AND category != '' AND category IS NOT NULL
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL AND category != '' AND category IS NOT NULL
 GROUP BY category
 `
 
@@ -97,9 +165,67 @@ func (q *Queries) GetCategories(ctx context.Context, owner string) ([]sql.NullSt
 	return items, nil
 }
 
+const getDeletedEntryForUpdate = `-- name: GetDeletedEntryForUpdate :one
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NOT NULL
+FOR NO KEY UPDATE
+`
+
+type GetDeletedEntryForUpdateParams struct {
+	Owner string `json:"owner"`
+	ID    int32  `json:"id"`
+}
+
+func (q *Queries) GetDeletedEntryForUpdate(ctx context.Context, arg GetDeletedEntryForUpdateParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedEntryForUpdate, arg.Owner, arg.ID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getDeletedHouseholdEntryForUpdate = `-- name: GetDeletedHouseholdEntryForUpdate :one
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NOT NULL
+FOR NO KEY UPDATE
+`
+
+type GetDeletedHouseholdEntryForUpdateParams struct {
+	HouseholdID sql.NullInt32 `json:"household_id"`
+	ID          int32         `json:"id"`
+}
+
+func (q *Queries) GetDeletedHouseholdEntryForUpdate(ctx context.Context, arg GetDeletedHouseholdEntryForUpdateParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedHouseholdEntryForUpdate, arg.HouseholdID, arg.ID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const getEntries = `-- name: GetEntries :many
-SELECT id, owner, name, due_date, amount, category FROM entries
-WHERE owner = $1
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL
 `
 
 func (q *Queries) GetEntries(ctx context.Context, owner string) ([]Entry, error) {
@@ -118,6 +244,10 @@ func (q *Queries) GetEntries(ctx context.Context, owner string) ([]Entry, error)
 			&i.DueDate,
 			&i.Amount,
 			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -132,9 +262,61 @@ func (q *Queries) GetEntries(ctx context.Context, owner string) ([]Entry, error)
 	return items, nil
 }
 
+const getEntriesOfHouseholds = `-- name: GetEntriesOfHouseholds :many
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = ANY($1::int[]) AND deleted_at IS NULL
+ORDER BY household_id, id
+`
+
+func (q *Queries) GetEntriesOfHouseholds(ctx context.Context, householdIds []int32) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, getEntriesOfHouseholds, pq.Array(householdIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.DueDate,
+			&i.Amount,
+			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getEntriesTotal = `-- name: GetEntriesTotal :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NULL
+`
+
+func (q *Queries) GetEntriesTotal(ctx context.Context, owner string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getEntriesTotal, owner)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, owner, name, due_date, amount, category FROM entries
-WHERE owner = $1 AND id = $2
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL
 `
 
 type GetEntryParams struct {
@@ -152,13 +334,17 @@ func (q *Queries) GetEntry(ctx context.Context, arg GetEntryParams) (Entry, erro
 		&i.DueDate,
 		&i.Amount,
 		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
 
 const getEntryForUpdate = `-- name: GetEntryForUpdate :one
-SELECT id, owner, name, due_date, amount, category FROM entries
-WHERE owner = $1 AND id = $2
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL
 FOR NO KEY UPDATE
 `
 
@@ -177,6 +363,265 @@ func (q *Queries) GetEntryForUpdate(ctx context.Context, arg GetEntryForUpdatePa
 		&i.DueDate,
 		&i.Amount,
 		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getHouseholdEntries = `-- name: GetHouseholdEntries :many
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = $1 AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) GetHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, getHouseholdEntries, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.DueDate,
+			&i.Amount,
+			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHouseholdEntry = `-- name: GetHouseholdEntry :one
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+`
+
+type GetHouseholdEntryParams struct {
+	HouseholdID sql.NullInt32 `json:"household_id"`
+	ID          int32         `json:"id"`
+}
+
+func (q *Queries) GetHouseholdEntry(ctx context.Context, arg GetHouseholdEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, getHouseholdEntry, arg.HouseholdID, arg.ID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getHouseholdEntryForUpdate = `-- name: GetHouseholdEntryForUpdate :one
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+FOR NO KEY UPDATE
+`
+
+type GetHouseholdEntryForUpdateParams struct {
+	HouseholdID sql.NullInt32 `json:"household_id"`
+	ID          int32         `json:"id"`
+}
+
+func (q *Queries) GetHouseholdEntryForUpdate(ctx context.Context, arg GetHouseholdEntryForUpdateParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, getHouseholdEntryForUpdate, arg.HouseholdID, arg.ID)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getHouseholdTotal = `-- name: GetHouseholdTotal :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS total FROM entries
+WHERE household_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetHouseholdTotal(ctx context.Context, householdID sql.NullInt32) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getHouseholdTotal, householdID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const listDeletedEntries = `-- name: ListDeletedEntries :many
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE owner = $1 AND household_id IS NULL AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListDeletedEntries(ctx context.Context, owner string) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedEntries, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.DueDate,
+			&i.Amount,
+			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedHouseholdEntries = `-- name: ListDeletedHouseholdEntries :many
+SELECT id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version FROM entries
+WHERE household_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListDeletedHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedHouseholdEntries, householdID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.DueDate,
+			&i.Amount,
+			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeEntries = `-- name: PurgeEntries :many
+DELETE FROM entries
+WHERE deleted_at < $1
+  OR owner IN (SELECT username FROM users WHERE deleted_at < $1)
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
+`
+
+func (q *Queries) PurgeEntries(ctx context.Context, deletedBefore sql.NullTime) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, purgeEntries, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Name,
+			&i.DueDate,
+			&i.Amount,
+			&i.Category,
+			&i.HouseholdID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreEntry = `-- name: RestoreEntry :one
+UPDATE entries
+SET deleted_at = NULL, version = version + 1
+WHERE id = $1
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
+`
+
+func (q *Queries) RestoreEntry(ctx context.Context, id int32) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, restoreEntry, id)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
@@ -199,9 +644,9 @@ func (q *Queries) UpdateEntriesOwner(ctx context.Context, arg UpdateEntriesOwner
 
 const updateEntry = `-- name: UpdateEntry :one
 UPDATE entries
-SET name = $3, due_date = $4, amount = $5, category = $6
-WHERE owner = $1 AND id = $2
-RETURNING id, owner, name, due_date, amount, category
+SET name = $3, due_date = $4, amount = $5, category = $6, version = version + 1
+WHERE owner = $1 AND id = $2 AND household_id IS NULL AND deleted_at IS NULL
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
 `
 
 type UpdateEntryParams struct {
@@ -230,6 +675,51 @@ func (q *Queries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry
 		&i.DueDate,
 		&i.Amount,
 		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateHouseholdEntry = `-- name: UpdateHouseholdEntry :one
+UPDATE entries
+SET name = $3, due_date = $4, amount = $5, category = $6, version = version + 1
+WHERE household_id = $1 AND id = $2 AND deleted_at IS NULL
+RETURNING id, owner, name, due_date, amount, category, household_id, created_by, deleted_at, version
+`
+
+type UpdateHouseholdEntryParams struct {
+	HouseholdID sql.NullInt32  `json:"household_id"`
+	ID          int32          `json:"id"`
+	Name        string         `json:"name"`
+	DueDate     time.Time      `json:"due_date"`
+	Amount      int64          `json:"amount"`
+	Category    sql.NullString `json:"category"`
+}
+
+func (q *Queries) UpdateHouseholdEntry(ctx context.Context, arg UpdateHouseholdEntryParams) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, updateHouseholdEntry,
+		arg.HouseholdID,
+		arg.ID,
+		arg.Name,
+		arg.DueDate,
+		arg.Amount,
+		arg.Category,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Name,
+		&i.DueDate,
+		&i.Amount,
+		&i.Category,
+		&i.HouseholdID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.Version,
 	)
 	return i, err
 }
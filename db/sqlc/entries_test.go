@@ -60,9 +60,12 @@ func TestUpdateEntry(t *testing.T) {
 	entry := createRandomEntry(t, user)
 
 	arg := UpdateEntryParams{
-		Owner:  user.Username,
-		ID:     entry.ID,
-		Amount: 10,
+		Owner:    user.Username,
+		ID:       entry.ID,
+		Name:     entry.Name,
+		DueDate:  entry.DueDate,
+		Amount:   10,
+		Category: entry.Category,
 	}
 
 	fmt.Println(entry.Name)
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+)
+
+// MigrationVersion is the state of the migrations applied by golang-migrate
+type MigrationVersion struct {
+	Version int64
+	Dirty   bool
+}
+
+// Checks that the database is reachable
+func (store *SQLStore) Ping(ctx context.Context) error {
+	return store.db.PingContext(ctx)
+}
+
+// Returns the last migration applied to the database
+func (store *SQLStore) GetMigrationVersion(ctx context.Context) (MigrationVersion, error) {
+	var version MigrationVersion
+	err := store.db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version.Version, &version.Dirty)
+	return version, err
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMigrationVersion(t *testing.T) {
+	store := newTestStore()
+
+	require.NoError(t, store.Ping(context.Background()))
+
+	latest, err := migration.LatestVersion(testDriver)
+	require.NoError(t, err)
+
+	version, err := store.GetMigrationVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(latest), version.Version)
+	require.False(t, version.Dirty)
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHousehold(t *testing.T) Household {
+	name := util.RandomString(8)
+
+	household, err := testQueries.CreateHousehold(context.Background(), name)
+	require.NoError(t, err)
+	require.NotEmpty(t, household)
+
+	require.Equal(t, name, household.Name)
+	require.NotZero(t, household.ID)
+	require.NotZero(t, household.CreatedAt)
+
+	return household
+}
+
+func addRandomHouseholdMember(t *testing.T, household Household, role string) HouseholdMember {
+	user := createRandomUser(t)
+
+	arg := AddHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+		Role:        role,
+	}
+
+	member, err := testQueries.AddHouseholdMember(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, member)
+
+	require.Equal(t, arg.HouseholdID, member.HouseholdID)
+	require.Equal(t, arg.Username, member.Username)
+	require.Equal(t, arg.Role, member.Role)
+
+	return member
+}
+
+// Puts the users in a new household
+func createHouseholdOf(t *testing.T, usernames ...string) Household {
+	household := createRandomHousehold(t)
+
+	for _, username := range usernames {
+		_, err := testQueries.AddHouseholdMember(context.Background(), AddHouseholdMemberParams{
+			HouseholdID: household.ID,
+			Username:    username,
+			Role:        util.EditorRole,
+		})
+		require.NoError(t, err)
+	}
+
+	return household
+}
+
+func TestCreateHousehold(t *testing.T) {
+	createRandomHousehold(t)
+}
+
+func TestListHouseholds(t *testing.T) {
+	household := createRandomHousehold(t)
+	member := addRandomHouseholdMember(t, household, util.OwnerRole)
+
+	households, err := testQueries.ListHouseholds(context.Background(), member.Username)
+	require.NoError(t, err)
+	require.Len(t, households, 1)
+	require.Equal(t, household.ID, households[0].ID)
+}
+
+func TestUpdateHouseholdMemberRole(t *testing.T) {
+	household := createRandomHousehold(t)
+	member := addRandomHouseholdMember(t, household, util.EditorRole)
+
+	arg := UpdateHouseholdMemberRoleParams{
+		HouseholdID: household.ID,
+		Username:    member.Username,
+		Role:        util.ViewerRole,
+	}
+
+	updatedMember, err := testQueries.UpdateHouseholdMemberRole(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.ViewerRole, updatedMember.Role)
+}
+
+func TestAddHouseholdMemberInvalidRole(t *testing.T) {
+	household := createRandomHousehold(t)
+	user := createRandomUser(t)
+
+	arg := AddHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    user.Username,
+		Role:        "admin",
+	}
+
+	member, err := testQueries.AddHouseholdMember(context.Background(), arg)
+	require.Error(t, err)
+	require.Empty(t, member)
+}
+
+func TestDeleteHouseholdMember(t *testing.T) {
+	household := createRandomHousehold(t)
+	member := addRandomHouseholdMember(t, household, util.ViewerRole)
+
+	err := testQueries.DeleteHouseholdMember(context.Background(), DeleteHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    member.Username,
+	})
+	require.NoError(t, err)
+
+	deletedMember, err := testQueries.GetHouseholdMember(context.Background(), GetHouseholdMemberParams{
+		HouseholdID: household.ID,
+		Username:    member.Username,
+	})
+	require.Error(t, err)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.Empty(t, deletedMember)
+}
+
+func TestHouseholdEntries(t *testing.T) {
+	household := createRandomHousehold(t)
+	member := addRandomHouseholdMember(t, household, util.EditorRole)
+	householdID := sql.NullInt32{Int32: household.ID, Valid: true}
+
+	date, err := GetMadeUpDate("2022-12-11")
+	require.NoError(t, err)
+
+	var total int64
+	for i := 0; i < 3; i++ {
+		entry, err := testQueries.CreateHouseholdEntry(context.Background(), CreateHouseholdEntryParams{
+			Owner:       member.Username,
+			Name:        util.RandomString(8),
+			DueDate:     date,
+			Amount:      util.RandomMoney(),
+			HouseholdID: householdID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, householdID, entry.HouseholdID)
+		require.Equal(t, member.Username, entry.CreatedBy.String)
+		total += entry.Amount
+	}
+
+	entries, err := testQueries.GetHouseholdEntries(context.Background(), householdID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	gotTotal, err := testQueries.GetHouseholdTotal(context.Background(), householdID)
+	require.NoError(t, err)
+	require.Equal(t, total, gotTotal)
+
+	// household entries are not part of the member's personal entries
+	personalEntries, err := testQueries.GetEntries(context.Background(), member.Username)
+	require.NoError(t, err)
+	require.Empty(t, personalEntries)
+}
+
+func TestListMembersOfHouseholds(t *testing.T) {
+	household1 := createRandomHousehold(t)
+	household2 := createRandomHousehold(t)
+	owner1 := addRandomHouseholdMember(t, household1, util.OwnerRole)
+	viewer1 := addRandomHouseholdMember(t, household1, util.ViewerRole)
+	owner2 := addRandomHouseholdMember(t, household2, util.OwnerRole)
+
+	members, err := testQueries.ListMembersOfHouseholds(context.Background(), []int32{household1.ID, household2.ID})
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	require.Equal(t, owner1.Username, members[0].Username)
+	require.Equal(t, viewer1.Username, members[1].Username)
+	require.Equal(t, owner2.Username, members[2].Username)
+}
+
+func TestGetEntriesOfHouseholds(t *testing.T) {
+	date, err := GetMadeUpDate("2022-12-11")
+	require.NoError(t, err)
+
+	var ids []int32
+	for i := 0; i < 2; i++ {
+		household := createRandomHousehold(t)
+		member := addRandomHouseholdMember(t, household, util.EditorRole)
+		ids = append(ids, household.ID)
+
+		_, err := testQueries.CreateHouseholdEntry(context.Background(), CreateHouseholdEntryParams{
+			Owner:       member.Username,
+			Name:        util.RandomString(8),
+			DueDate:     date,
+			Amount:      util.RandomMoney(),
+			HouseholdID: sql.NullInt32{Int32: household.ID, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := testQueries.GetEntriesOfHouseholds(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for i, entry := range entries {
+		require.Equal(t, ids[i], entry.HouseholdID.Int32)
+	}
+}
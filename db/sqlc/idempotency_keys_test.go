@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.Zero(t, key.StatusCode)
+	require.WithinDuration(t, time.Now(), key.CreatedAt, time.Minute)
+
+	// the key is already taken
+	_, err = testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	err = testQueries.SaveIdempotencyResponse(context.Background(), SaveIdempotencyResponseParams{
+		Username:     user.Username,
+		Key:          arg.Key,
+		StatusCode:   200,
+		ResponseBody: []byte(`{"ok":true}`),
+	})
+	require.NoError(t, err)
+
+	getArg := GetIdempotencyKeyParams{
+		Username: user.Username,
+		Key:      arg.Key,
+	}
+	key, err = testQueries.GetIdempotencyKey(context.Background(), getArg)
+	require.NoError(t, err)
+	require.Equal(t, int32(200), key.StatusCode)
+	require.Equal(t, []byte(`{"ok":true}`), key.ResponseBody)
+
+	_, err = testQueries.DeleteExpiredIdempotencyKeys(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), getArg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
@@ -6,12 +6,15 @@ import (
 	"os"
 	"testing"
 
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
 	_ "github.com/lib/pq"
+	"github.com/rs/zerolog"
 )
 
 var testQueries *Queries
 var testDB *sql.DB
+var testDriver string
 
 func TestMain(m *testing.M) {
 	config, err := util.LoadConfig("../../")
@@ -19,12 +22,30 @@ func TestMain(m *testing.M) {
 		log.Fatal("cannot load config:", err)
 	}
 
-	testDB, err = sql.Open(config.DBDriver, config.DBSource)
+	testDriver = config.DBDriver
+	if testDriver == SQLiteDriver {
+		// the SQLite database is usually a new file, created with the schema here
+		migrator, err := migration.New(config.DBDriver, config.DBSource)
+		if err != nil {
+			log.Fatal("cannot open the migrations:", err)
+		}
+		if err := migrator.Up(-1); err != nil {
+			log.Fatal("cannot migrate db:", err)
+		}
+		migrator.Close()
+	}
+
+	testDB, err = Open(config.DBDriver, config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to db:", err)
 	}
 
-	testQueries = New(testDB)
+	testQueries = newTestStore().(*SQLStore).Queries
 
 	os.Exit(m.Run())
 }
+
+// Store of the database the tests run on
+func newTestStore() Store {
+	return NewStoreForDriver(testDriver, testDB, zerolog.Nop())
+}
@@ -0,0 +1,269 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Migration of the Postgres schema that MemStore follows
+const memSchemaVersion = 11
+
+// MemStore is a Store that keeps its tables in memory, for tests and demos that
+// shouldn't need a database. It follows the Postgres schema: unique keys, foreign
+// keys and checks fail with the errors lib/pq returns, missing rows are
+// sql.ErrNoRows, and transactions run one at a time and are rolled back on error.
+type MemStore struct {
+	*memQueries
+	txStore
+	db *memDB
+}
+
+// Creates a new empty store in memory
+func NewMemStore() Store {
+	store := &MemStore{
+		db: &memDB{
+			memTables: newMemTables(),
+			sequences: make(map[string]int64),
+		},
+	}
+	store.memQueries = &memQueries{db: store.db}
+	store.txStore = txStore{execTx: store.runTx}
+	return store
+}
+
+// Runs fn with the store locked, restoring the tables as they were if it fails
+func (store *MemStore) runTx(ctx context.Context, name string, fn func(q txQuerier) error) error {
+	store.db.mu.Lock()
+	defer store.db.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := store.db.memTables.clone()
+	q := &memQueries{db: store.db, tx: true, savepoints: make(map[string]memTables)}
+	err := fn(q)
+	if err != nil {
+		store.db.memTables = snapshot
+	}
+	return err
+}
+
+// The store is always reachable
+func (store *MemStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// The tables are created at the latest migration
+func (store *MemStore) GetMigrationVersion(ctx context.Context) (MigrationVersion, error) {
+	return MigrationVersion{Version: memSchemaVersion}, nil
+}
+
+// memDB holds the tables of a MemStore. Like Postgres sequences, the sequences
+// giving the IDs are not rolled back with the transactions.
+type memDB struct {
+	mu sync.Mutex
+	memTables
+	sequences map[string]int64
+}
+
+// Returns the next ID of the serial column of table
+func (db *memDB) nextID(table string) int64 {
+	db.sequences[table]++
+	return db.sequences[table]
+}
+
+type householdMemberKey struct {
+	householdID int32
+	username    string
+}
+
+type expenseShareKey struct {
+	expenseID int32
+	username  string
+}
+
+type balanceKey struct {
+	userA string
+	userB string
+}
+
+type idempotencyKeyKey struct {
+	username string
+	key      string
+}
+
+// memTables are the rows of each table by primary key
+type memTables struct {
+	users                map[string]User
+	entries              map[int32]Entry
+	households           map[int32]Household
+	householdMembers     map[householdMemberKey]HouseholdMember
+	householdInvitations map[int32]HouseholdInvitation
+	sharedExpenses       map[int32]SharedExpense
+	expenseShares        map[expenseShareKey]ExpenseShare
+	balances             map[balanceKey]Balance
+	settlements          map[int32]Settlement
+	auditEvents          map[int64]AuditEvent
+	idempotencyKeys      map[idempotencyKeyKey]IdempotencyKey
+	webhooks             map[int32]Webhook
+	webhookDeliveries    map[int64]WebhookDelivery
+	rateLimitBuckets     map[string]RateLimitBucket
+}
+
+func newMemTables() memTables {
+	return memTables{
+		users:                make(map[string]User),
+		entries:              make(map[int32]Entry),
+		households:           make(map[int32]Household),
+		householdMembers:     make(map[householdMemberKey]HouseholdMember),
+		householdInvitations: make(map[int32]HouseholdInvitation),
+		sharedExpenses:       make(map[int32]SharedExpense),
+		expenseShares:        make(map[expenseShareKey]ExpenseShare),
+		balances:             make(map[balanceKey]Balance),
+		settlements:          make(map[int32]Settlement),
+		auditEvents:          make(map[int64]AuditEvent),
+		idempotencyKeys:      make(map[idempotencyKeyKey]IdempotencyKey),
+		webhooks:             make(map[int32]Webhook),
+		webhookDeliveries:    make(map[int64]WebhookDelivery),
+		rateLimitBuckets:     make(map[string]RateLimitBucket),
+	}
+}
+
+// Copies the tables. Rows are copied by value, their slices are shared since
+// the queries replace them instead of changing them.
+func (tables memTables) clone() memTables {
+	return memTables{
+		users:                cloneMap(tables.users),
+		entries:              cloneMap(tables.entries),
+		households:           cloneMap(tables.households),
+		householdMembers:     cloneMap(tables.householdMembers),
+		householdInvitations: cloneMap(tables.householdInvitations),
+		sharedExpenses:       cloneMap(tables.sharedExpenses),
+		expenseShares:        cloneMap(tables.expenseShares),
+		balances:             cloneMap(tables.balances),
+		settlements:          cloneMap(tables.settlements),
+		auditEvents:          cloneMap(tables.auditEvents),
+		idempotencyKeys:      cloneMap(tables.idempotencyKeys),
+		webhooks:             cloneMap(tables.webhooks),
+		webhookDeliveries:    cloneMap(tables.webhookDeliveries),
+		rateLimitBuckets:     cloneMap(tables.rateLimitBuckets),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	cloned := make(map[K]V, len(m))
+	for key, value := range m {
+		cloned[key] = value
+	}
+	return cloned
+}
+
+// Returns the rows of table matching match, sorted by less
+func selectRows[K comparable, V any](table map[K]V, match func(row V) bool, less func(a V, b V) bool) []V {
+	rows := []V{}
+	for _, row := range table {
+		if match(row) {
+			rows = append(rows, row)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return less(rows[i], rows[j])
+	})
+	return rows
+}
+
+// Returns the rows from offset, at most limit of them
+func paginate[V any](rows []V, limit int32, offset int32) []V {
+	if int(offset) >= len(rows) {
+		return []V{}
+	}
+	rows = rows[offset:]
+	if int(limit) < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// memQueries runs the queries on the tables of db. Outside of a transaction
+// each query locks db itself.
+type memQueries struct {
+	db         *memDB
+	tx         bool
+	savepoints map[string]memTables
+}
+
+// Locks the tables unless a transaction already holds them, call the returned
+// function to unlock them
+func (q *memQueries) lock() func() {
+	if q.tx {
+		return func() {}
+	}
+	q.db.mu.Lock()
+	return q.db.mu.Unlock
+}
+
+func (q *memQueries) savepoint(ctx context.Context, name string) error {
+	q.savepoints[name] = q.db.memTables.clone()
+	return nil
+}
+
+func (q *memQueries) rollbackToSavepoint(ctx context.Context, name string) error {
+	tables, ok := q.savepoints[name]
+	if !ok {
+		return fmt.Errorf("savepoint %q does not exist", name)
+	}
+	q.db.memTables = tables.clone()
+	return nil
+}
+
+func (q *memQueries) releaseSavepoint(ctx context.Context, name string) error {
+	if _, ok := q.savepoints[name]; !ok {
+		return fmt.Errorf("savepoint %q does not exist", name)
+	}
+	delete(q.savepoints, name)
+	return nil
+}
+
+// Times are stored with the microsecond precision of Postgres
+func memTime(t time.Time) time.Time {
+	return t.Round(time.Microsecond)
+}
+
+func memNow() time.Time {
+	return memTime(time.Now())
+}
+
+// Errors of the violated constraints, as lib/pq returns them
+
+func uniqueViolation(table string, constraint string) error {
+	return &pq.Error{
+		Code:       "23505",
+		Message:    fmt.Sprintf("duplicate key value violates unique constraint %q", constraint),
+		Table:      table,
+		Constraint: constraint,
+	}
+}
+
+func foreignKeyViolation(table string, constraint string) error {
+	return &pq.Error{
+		Code:       "23503",
+		Message:    fmt.Sprintf("insert or update on table %q violates foreign key constraint %q", table, constraint),
+		Table:      table,
+		Constraint: constraint,
+	}
+}
+
+func checkViolation(table string, constraint string) error {
+	return &pq.Error{
+		Code:       "23514",
+		Message:    fmt.Sprintf("new row for relation %q violates check constraint %q", table, constraint),
+		Table:      table,
+		Constraint: constraint,
+	}
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+)
+
+func (q *memQueries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error) {
+	defer q.lock()()
+
+	event := AuditEvent{
+		ID:         q.db.nextID("audit_events"),
+		Actor:      arg.Actor,
+		Action:     arg.Action,
+		EntityType: arg.EntityType,
+		EntityID:   arg.EntityID,
+		Before:     cloneBytes(arg.Before),
+		After:      cloneBytes(arg.After),
+		ClientIp:   arg.ClientIp,
+		RequestID:  arg.RequestID,
+		CreatedAt:  memNow(),
+	}
+	q.db.auditEvents[event.ID] = event
+	return event, nil
+}
+
+func (q *memQueries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	defer q.lock()()
+
+	events := selectRows(q.db.auditEvents, func(event AuditEvent) bool {
+		return event.Actor == arg.Actor
+	}, func(a AuditEvent, b AuditEvent) bool {
+		return a.ID > b.ID
+	})
+	return paginate(events, arg.Limit, arg.Offset), nil
+}
+
+// Copies the bytes of a parameter, the caller may reuse them
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
@@ -0,0 +1,381 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+)
+
+func (q *memQueries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	defer q.lock()()
+
+	return q.db.insertEntry(Entry{
+		Owner:     arg.Owner,
+		Name:      arg.Name,
+		DueDate:   memTime(arg.DueDate),
+		Amount:    arg.Amount,
+		Category:  arg.Category,
+		CreatedBy: arg.CreatedBy,
+	})
+}
+
+func (q *memQueries) GetEntries(ctx context.Context, owner string) ([]Entry, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.entries, func(entry Entry) bool {
+		return isPersonalEntryOf(entry, owner) && !entry.DeletedAt.Valid
+	}, entriesByID), nil
+}
+
+func (q *memQueries) GetEntry(ctx context.Context, arg GetEntryParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.personalEntry(arg.Owner, arg.ID, false)
+}
+
+func (q *memQueries) GetEntryForUpdate(ctx context.Context, arg GetEntryForUpdateParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.personalEntry(arg.Owner, arg.ID, false)
+}
+
+func (q *memQueries) GetCategories(ctx context.Context, owner string) ([]sql.NullString, error) {
+	defer q.lock()()
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, entry := range q.db.entries {
+		if !isPersonalEntryOf(entry, owner) || entry.DeletedAt.Valid || !entry.Category.Valid || entry.Category.String == "" {
+			continue
+		}
+		if !seen[entry.Category.String] {
+			seen[entry.Category.String] = true
+			names = append(names, entry.Category.String)
+		}
+	}
+	sort.Strings(names)
+
+	categories := []sql.NullString{}
+	for _, name := range names {
+		categories = append(categories, sql.NullString{String: name, Valid: true})
+	}
+	return categories, nil
+}
+
+func (q *memQueries) UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error) {
+	defer q.lock()()
+
+	entry, err := q.db.personalEntry(arg.Owner, arg.ID, false)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Name = arg.Name
+	entry.DueDate = memTime(arg.DueDate)
+	entry.Amount = arg.Amount
+	entry.Category = arg.Category
+	return q.db.updateEntry(entry)
+}
+
+func (q *memQueries) DeleteEntry(ctx context.Context, id int32) error {
+	defer q.lock()()
+
+	entry, ok := q.db.entries[id]
+	if !ok || entry.DeletedAt.Valid {
+		return nil
+	}
+
+	entry.DeletedAt = sql.NullTime{Time: memNow(), Valid: true}
+	entry.Version++
+	q.db.entries[id] = entry
+	return nil
+}
+
+func (q *memQueries) DeleteEntries(ctx context.Context, owner string) error {
+	defer q.lock()()
+
+	for id, entry := range q.db.entries {
+		if entry.Owner == owner {
+			q.db.deleteEntry(id)
+		}
+	}
+	return nil
+}
+
+func (q *memQueries) UpdateEntriesOwner(ctx context.Context, arg UpdateEntriesOwnerParams) error {
+	defer q.lock()()
+
+	owned := selectRows(q.db.entries, func(entry Entry) bool {
+		return entry.Owner == arg.Owner
+	}, entriesByID)
+	if len(owned) == 0 {
+		return nil
+	}
+
+	err := q.db.checkUser(arg.Owner_2, "entries", "entries_owner_fkey")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range owned {
+		entry.Owner = arg.Owner_2
+		q.db.entries[entry.ID] = entry
+	}
+	return nil
+}
+
+func (q *memQueries) CreateHouseholdEntry(ctx context.Context, arg CreateHouseholdEntryParams) (Entry, error) {
+	defer q.lock()()
+
+	return q.db.insertEntry(Entry{
+		Owner:       arg.Owner,
+		Name:        arg.Name,
+		DueDate:     memTime(arg.DueDate),
+		Amount:      arg.Amount,
+		Category:    arg.Category,
+		HouseholdID: arg.HouseholdID,
+		CreatedBy:   sql.NullString{String: arg.Owner, Valid: true},
+	})
+}
+
+func (q *memQueries) GetHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.entries, func(entry Entry) bool {
+		return isEntryOfHousehold(entry, householdID) && !entry.DeletedAt.Valid
+	}, entriesByID), nil
+}
+
+func (q *memQueries) GetEntriesOfHouseholds(ctx context.Context, householdIds []int32) ([]Entry, error) {
+	defer q.lock()()
+
+	wanted := int32Set(householdIds)
+	return selectRows(q.db.entries, func(entry Entry) bool {
+		return entry.HouseholdID.Valid && wanted[entry.HouseholdID.Int32] && !entry.DeletedAt.Valid
+	}, func(a Entry, b Entry) bool {
+		if a.HouseholdID.Int32 != b.HouseholdID.Int32 {
+			return a.HouseholdID.Int32 < b.HouseholdID.Int32
+		}
+		return a.ID < b.ID
+	}), nil
+}
+
+func (q *memQueries) GetHouseholdEntry(ctx context.Context, arg GetHouseholdEntryParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.householdEntry(arg.HouseholdID, arg.ID, false)
+}
+
+func (q *memQueries) GetHouseholdEntryForUpdate(ctx context.Context, arg GetHouseholdEntryForUpdateParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.householdEntry(arg.HouseholdID, arg.ID, false)
+}
+
+func (q *memQueries) UpdateHouseholdEntry(ctx context.Context, arg UpdateHouseholdEntryParams) (Entry, error) {
+	defer q.lock()()
+
+	entry, err := q.db.householdEntry(arg.HouseholdID, arg.ID, false)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Name = arg.Name
+	entry.DueDate = memTime(arg.DueDate)
+	entry.Amount = arg.Amount
+	entry.Category = arg.Category
+	return q.db.updateEntry(entry)
+}
+
+func (q *memQueries) DeleteHouseholdEntry(ctx context.Context, arg DeleteHouseholdEntryParams) error {
+	defer q.lock()()
+
+	entry, err := q.db.householdEntry(arg.HouseholdID, arg.ID, false)
+	if err != nil {
+		return nil
+	}
+
+	entry.DeletedAt = sql.NullTime{Time: memNow(), Valid: true}
+	entry.Version++
+	q.db.entries[entry.ID] = entry
+	return nil
+}
+
+func (q *memQueries) GetEntriesTotal(ctx context.Context, owner string) (int64, error) {
+	defer q.lock()()
+	return q.db.entriesTotal(owner), nil
+}
+
+func (q *memQueries) GetHouseholdTotal(ctx context.Context, householdID sql.NullInt32) (int64, error) {
+	defer q.lock()()
+
+	var total int64
+	for _, entry := range q.db.entries {
+		if isEntryOfHousehold(entry, householdID) && !entry.DeletedAt.Valid {
+			total += entry.Amount
+		}
+	}
+	return total, nil
+}
+
+func (q *memQueries) ListDeletedEntries(ctx context.Context, owner string) ([]Entry, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.entries, func(entry Entry) bool {
+		return isPersonalEntryOf(entry, owner) && entry.DeletedAt.Valid
+	}, func(a Entry, b Entry) bool {
+		return a.DeletedAt.Time.After(b.DeletedAt.Time)
+	}), nil
+}
+
+func (q *memQueries) GetDeletedEntryForUpdate(ctx context.Context, arg GetDeletedEntryForUpdateParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.personalEntry(arg.Owner, arg.ID, true)
+}
+
+func (q *memQueries) ListDeletedHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.entries, func(entry Entry) bool {
+		return isEntryOfHousehold(entry, householdID) && entry.DeletedAt.Valid
+	}, func(a Entry, b Entry) bool {
+		return a.DeletedAt.Time.After(b.DeletedAt.Time)
+	}), nil
+}
+
+func (q *memQueries) GetDeletedHouseholdEntryForUpdate(ctx context.Context, arg GetDeletedHouseholdEntryForUpdateParams) (Entry, error) {
+	defer q.lock()()
+	return q.db.householdEntry(arg.HouseholdID, arg.ID, true)
+}
+
+func (q *memQueries) RestoreEntry(ctx context.Context, id int32) (Entry, error) {
+	defer q.lock()()
+
+	entry, ok := q.db.entries[id]
+	if !ok {
+		return Entry{}, sql.ErrNoRows
+	}
+
+	entry.DeletedAt = sql.NullTime{}
+	return q.db.updateEntry(entry)
+}
+
+func (q *memQueries) PurgeEntries(ctx context.Context, deletedBefore sql.NullTime) ([]Entry, error) {
+	defer q.lock()()
+
+	deletedEarlier := func(deletedAt sql.NullTime) bool {
+		return deletedAt.Valid && deletedBefore.Valid && deletedAt.Time.Before(deletedBefore.Time)
+	}
+
+	purged := selectRows(q.db.entries, func(entry Entry) bool {
+		return deletedEarlier(entry.DeletedAt) || deletedEarlier(q.db.users[entry.Owner].DeletedAt)
+	}, entriesByID)
+	for _, entry := range purged {
+		q.db.deleteEntry(entry.ID)
+	}
+	return purged, nil
+}
+
+func entriesByID(a Entry, b Entry) bool {
+	return a.ID < b.ID
+}
+
+func isPersonalEntryOf(entry Entry, owner string) bool {
+	return entry.Owner == owner && !entry.HouseholdID.Valid
+}
+
+// NULL is never equal to a household
+func isEntryOfHousehold(entry Entry, householdID sql.NullInt32) bool {
+	return householdID.Valid && entry.HouseholdID.Valid && entry.HouseholdID.Int32 == householdID.Int32
+}
+
+func int32Set(values []int32) map[int32]bool {
+	set := make(map[int32]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// Returns an entry of the owner outside of households, in the trash when deleted is set
+func (db *memDB) personalEntry(owner string, id int32, deleted bool) (Entry, error) {
+	entry, ok := db.entries[id]
+	if !ok || !isPersonalEntryOf(entry, owner) || entry.DeletedAt.Valid != deleted {
+		return Entry{}, sql.ErrNoRows
+	}
+	return entry, nil
+}
+
+// Returns an entry of the household, in the trash when deleted is set
+func (db *memDB) householdEntry(householdID sql.NullInt32, id int32, deleted bool) (Entry, error) {
+	entry, ok := db.entries[id]
+	if !ok || !isEntryOfHousehold(entry, householdID) || entry.DeletedAt.Valid != deleted {
+		return Entry{}, sql.ErrNoRows
+	}
+	return entry, nil
+}
+
+// Sums the amounts of the owner's entries outside of households and the trash
+func (db *memDB) entriesTotal(owner string) int64 {
+	var total int64
+	for _, entry := range db.entries {
+		if isPersonalEntryOf(entry, owner) && !entry.DeletedAt.Valid {
+			total += entry.Amount
+		}
+	}
+	return total
+}
+
+// Checks the constraints of an entry that is not in the trash. Names are only
+// unique among those, the trash can hold several entries with the same name.
+func (db *memDB) checkEntry(entry Entry) error {
+	if !entry.DeletedAt.Valid {
+		for _, other := range db.entries {
+			if other.ID != entry.ID && other.Name == entry.Name && !other.DeletedAt.Valid {
+				return uniqueViolation("entries", "entries_name_key")
+			}
+		}
+	}
+
+	err := db.checkUser(entry.Owner, "entries", "entries_owner_fkey")
+	if err != nil {
+		return err
+	}
+
+	if entry.HouseholdID.Valid {
+		if _, ok := db.households[entry.HouseholdID.Int32]; !ok {
+			return foreignKeyViolation("entries", "entries_household_id_fkey")
+		}
+	}
+	return nil
+}
+
+func (db *memDB) insertEntry(entry Entry) (Entry, error) {
+	entry.ID = int32(db.nextID("entries"))
+	err := db.checkEntry(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Version = 1
+	db.entries[entry.ID] = entry
+	return entry, nil
+}
+
+// Saves a changed entry at its next version
+func (db *memDB) updateEntry(entry Entry) (Entry, error) {
+	err := db.checkEntry(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry.Version++
+	db.entries[entry.ID] = entry
+	return entry, nil
+}
+
+// Deletes the entry and, on cascade, its split
+func (db *memDB) deleteEntry(id int32) {
+	for expenseID, expense := range db.sharedExpenses {
+		if expense.EntryID == id {
+			db.deleteSharedExpense(expenseID)
+		}
+	}
+	delete(db.entries, id)
+}
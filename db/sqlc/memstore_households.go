@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+func (q *memQueries) CreateHousehold(ctx context.Context, name string) (Household, error) {
+	defer q.lock()()
+
+	household := Household{
+		ID:        int32(q.db.nextID("households")),
+		Name:      name,
+		CreatedAt: memNow(),
+	}
+	q.db.households[household.ID] = household
+	return household, nil
+}
+
+func (q *memQueries) GetHousehold(ctx context.Context, id int32) (Household, error) {
+	defer q.lock()()
+
+	household, ok := q.db.households[id]
+	if !ok {
+		return Household{}, sql.ErrNoRows
+	}
+	return household, nil
+}
+
+func (q *memQueries) ListHouseholds(ctx context.Context, username string) ([]Household, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.households, func(household Household) bool {
+		_, ok := q.db.householdMembers[householdMemberKey{household.ID, username}]
+		return ok
+	}, func(a Household, b Household) bool {
+		return a.ID < b.ID
+	}), nil
+}
+
+func (q *memQueries) DeleteHousehold(ctx context.Context, id int32) error {
+	defer q.lock()()
+
+	if _, ok := q.db.households[id]; !ok {
+		return nil
+	}
+
+	for key, member := range q.db.householdMembers {
+		if member.HouseholdID == id {
+			delete(q.db.householdMembers, key)
+		}
+	}
+	for invitationID, invitation := range q.db.householdInvitations {
+		if invitation.HouseholdID == id {
+			delete(q.db.householdInvitations, invitationID)
+		}
+	}
+	for entryID, entry := range q.db.entries {
+		if entry.HouseholdID.Valid && entry.HouseholdID.Int32 == id {
+			q.db.deleteEntry(entryID)
+		}
+	}
+	delete(q.db.households, id)
+	return nil
+}
+
+func (q *memQueries) AddHouseholdMember(ctx context.Context, arg AddHouseholdMemberParams) (HouseholdMember, error) {
+	defer q.lock()()
+
+	if !util.IsSupportedRole(arg.Role) {
+		return HouseholdMember{}, checkViolation("household_members", "household_members_role_check")
+	}
+
+	key := householdMemberKey{arg.HouseholdID, arg.Username}
+	if _, ok := q.db.householdMembers[key]; ok {
+		return HouseholdMember{}, uniqueViolation("household_members", "household_members_pkey")
+	}
+	if _, ok := q.db.households[arg.HouseholdID]; !ok {
+		return HouseholdMember{}, foreignKeyViolation("household_members", "household_members_household_id_fkey")
+	}
+	err := q.db.checkUser(arg.Username, "household_members", "household_members_username_fkey")
+	if err != nil {
+		return HouseholdMember{}, err
+	}
+
+	member := HouseholdMember{
+		HouseholdID: arg.HouseholdID,
+		Username:    arg.Username,
+		Role:        arg.Role,
+		CreatedAt:   memNow(),
+	}
+	q.db.householdMembers[key] = member
+	return member, nil
+}
+
+func (q *memQueries) GetHouseholdMember(ctx context.Context, arg GetHouseholdMemberParams) (HouseholdMember, error) {
+	defer q.lock()()
+
+	member, ok := q.db.householdMembers[householdMemberKey{arg.HouseholdID, arg.Username}]
+	if !ok {
+		return HouseholdMember{}, sql.ErrNoRows
+	}
+	return member, nil
+}
+
+func (q *memQueries) GetHouseholdMemberForUpdate(ctx context.Context, arg GetHouseholdMemberForUpdateParams) (HouseholdMember, error) {
+	return q.GetHouseholdMember(ctx, GetHouseholdMemberParams(arg))
+}
+
+func (q *memQueries) ListHouseholdMembers(ctx context.Context, householdID int32) ([]HouseholdMember, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.householdMembers, func(member HouseholdMember) bool {
+		return member.HouseholdID == householdID
+	}, membersByCreation), nil
+}
+
+func (q *memQueries) ListMembersOfHouseholds(ctx context.Context, householdIds []int32) ([]HouseholdMember, error) {
+	defer q.lock()()
+
+	wanted := int32Set(householdIds)
+	return selectRows(q.db.householdMembers, func(member HouseholdMember) bool {
+		return wanted[member.HouseholdID]
+	}, func(a HouseholdMember, b HouseholdMember) bool {
+		if a.HouseholdID != b.HouseholdID {
+			return a.HouseholdID < b.HouseholdID
+		}
+		return membersByCreation(a, b)
+	}), nil
+}
+
+func (q *memQueries) UpdateHouseholdMemberRole(ctx context.Context, arg UpdateHouseholdMemberRoleParams) (HouseholdMember, error) {
+	defer q.lock()()
+
+	key := householdMemberKey{arg.HouseholdID, arg.Username}
+	member, ok := q.db.householdMembers[key]
+	if !ok {
+		return HouseholdMember{}, sql.ErrNoRows
+	}
+	if !util.IsSupportedRole(arg.Role) {
+		return HouseholdMember{}, checkViolation("household_members", "household_members_role_check")
+	}
+
+	member.Role = arg.Role
+	q.db.householdMembers[key] = member
+	return member, nil
+}
+
+func (q *memQueries) DeleteHouseholdMember(ctx context.Context, arg DeleteHouseholdMemberParams) error {
+	defer q.lock()()
+
+	delete(q.db.householdMembers, householdMemberKey{arg.HouseholdID, arg.Username})
+	return nil
+}
+
+func (q *memQueries) CreateHouseholdInvitation(ctx context.Context, arg CreateHouseholdInvitationParams) (HouseholdInvitation, error) {
+	defer q.lock()()
+
+	invitation := HouseholdInvitation{
+		ID:          int32(q.db.nextID("household_invitations")),
+		HouseholdID: arg.HouseholdID,
+		Email:       arg.Email,
+		Role:        arg.Role,
+		InvitedBy:   arg.InvitedBy,
+		CreatedAt:   memNow(),
+	}
+	if !util.IsSupportedRole(arg.Role) {
+		return HouseholdInvitation{}, checkViolation("household_invitations", "household_invitations_role_check")
+	}
+	if _, ok := q.db.households[arg.HouseholdID]; !ok {
+		return HouseholdInvitation{}, foreignKeyViolation("household_invitations", "household_invitations_household_id_fkey")
+	}
+
+	q.db.householdInvitations[invitation.ID] = invitation
+	return invitation, nil
+}
+
+func (q *memQueries) GetHouseholdInvitationForUpdate(ctx context.Context, id int32) (HouseholdInvitation, error) {
+	defer q.lock()()
+
+	invitation, ok := q.db.householdInvitations[id]
+	if !ok {
+		return HouseholdInvitation{}, sql.ErrNoRows
+	}
+	return invitation, nil
+}
+
+func (q *memQueries) ListHouseholdInvitations(ctx context.Context, email string) ([]HouseholdInvitation, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.householdInvitations, func(invitation HouseholdInvitation) bool {
+		return invitation.Email == email && !invitation.Accepted
+	}, func(a HouseholdInvitation, b HouseholdInvitation) bool {
+		return a.ID < b.ID
+	}), nil
+}
+
+func (q *memQueries) AcceptHouseholdInvitation(ctx context.Context, id int32) (HouseholdInvitation, error) {
+	defer q.lock()()
+
+	invitation, ok := q.db.householdInvitations[id]
+	if !ok {
+		return HouseholdInvitation{}, sql.ErrNoRows
+	}
+
+	invitation.Accepted = true
+	q.db.householdInvitations[id] = invitation
+	return invitation, nil
+}
+
+// Members that joined at the same time are sorted by username, Postgres
+// returns them in any order
+func membersByCreation(a HouseholdMember, b HouseholdMember) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.Username < b.Username
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+func (q *memQueries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error) {
+	defer q.lock()()
+
+	key := idempotencyKeyKey{arg.Username, arg.Key}
+	if _, ok := q.db.idempotencyKeys[key]; ok {
+		// ON CONFLICT DO NOTHING returns no row
+		return IdempotencyKey{}, sql.ErrNoRows
+	}
+	err := q.db.checkUser(arg.Username, "idempotency_keys", "idempotency_keys_username_fkey")
+	if err != nil {
+		return IdempotencyKey{}, err
+	}
+
+	idempotencyKey := IdempotencyKey{
+		Username:    arg.Username,
+		Key:         arg.Key,
+		RequestHash: arg.RequestHash,
+		CreatedAt:   memNow(),
+	}
+	q.db.idempotencyKeys[key] = idempotencyKey
+	return idempotencyKey, nil
+}
+
+func (q *memQueries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error) {
+	defer q.lock()()
+
+	idempotencyKey, ok := q.db.idempotencyKeys[idempotencyKeyKey{arg.Username, arg.Key}]
+	if !ok {
+		return IdempotencyKey{}, sql.ErrNoRows
+	}
+	return idempotencyKey, nil
+}
+
+func (q *memQueries) SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error {
+	defer q.lock()()
+
+	key := idempotencyKeyKey{arg.Username, arg.Key}
+	idempotencyKey, ok := q.db.idempotencyKeys[key]
+	if !ok {
+		return nil
+	}
+
+	idempotencyKey.StatusCode = arg.StatusCode
+	idempotencyKey.ResponseBody = cloneBytes(arg.ResponseBody)
+	idempotencyKey.ContentType = arg.ContentType
+	q.db.idempotencyKeys[key] = idempotencyKey
+	return nil
+}
+
+func (q *memQueries) DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error {
+	defer q.lock()()
+
+	delete(q.db.idempotencyKeys, idempotencyKeyKey{arg.Username, arg.Key})
+	return nil
+}
+
+func (q *memQueries) DeleteExpiredIdempotencyKeys(ctx context.Context, createdAt time.Time) (int64, error) {
+	defer q.lock()()
+
+	var deleted int64
+	for key, idempotencyKey := range q.db.idempotencyKeys {
+		if idempotencyKey.CreatedAt.Before(createdAt) {
+			delete(q.db.idempotencyKeys, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
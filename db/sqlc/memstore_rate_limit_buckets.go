@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+func (q *memQueries) CreateRateLimitBucket(ctx context.Context, arg CreateRateLimitBucketParams) error {
+	defer q.lock()()
+
+	if _, ok := q.db.rateLimitBuckets[arg.Key]; ok {
+		return nil
+	}
+
+	q.db.rateLimitBuckets[arg.Key] = RateLimitBucket{
+		Key:       arg.Key,
+		Tokens:    arg.Tokens,
+		UpdatedAt: memTime(arg.UpdatedAt),
+	}
+	return nil
+}
+
+func (q *memQueries) GetRateLimitBucketForUpdate(ctx context.Context, key string) (RateLimitBucket, error) {
+	defer q.lock()()
+
+	bucket, ok := q.db.rateLimitBuckets[key]
+	if !ok {
+		return RateLimitBucket{}, sql.ErrNoRows
+	}
+	return bucket, nil
+}
+
+func (q *memQueries) UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error {
+	defer q.lock()()
+
+	if _, ok := q.db.rateLimitBuckets[arg.Key]; !ok {
+		return nil
+	}
+
+	q.db.rateLimitBuckets[arg.Key] = RateLimitBucket{
+		Key:       arg.Key,
+		Tokens:    arg.Tokens,
+		UpdatedAt: memTime(arg.UpdatedAt),
+	}
+	return nil
+}
+
+func (q *memQueries) DeleteIdleRateLimitBuckets(ctx context.Context, updatedAt time.Time) (int64, error) {
+	defer q.lock()()
+
+	var deleted int64
+	for key, bucket := range q.db.rateLimitBuckets {
+		if bucket.UpdatedAt.Before(updatedAt) {
+			delete(q.db.rateLimitBuckets, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+func (q *memQueries) CreateSharedExpense(ctx context.Context, arg CreateSharedExpenseParams) (SharedExpense, error) {
+	defer q.lock()()
+
+	expense := SharedExpense{
+		ID:        int32(q.db.nextID("shared_expenses")),
+		EntryID:   arg.EntryID,
+		PaidBy:    arg.PaidBy,
+		SplitType: arg.SplitType,
+		CreatedAt: memNow(),
+	}
+	if !util.IsSupportedSplitType(arg.SplitType) {
+		return SharedExpense{}, checkViolation("shared_expenses", "shared_expenses_split_type_check")
+	}
+	if _, err := q.db.sharedExpenseByEntry(arg.EntryID); err == nil {
+		return SharedExpense{}, uniqueViolation("shared_expenses", "shared_expenses_entry_id_key")
+	}
+	if _, ok := q.db.entries[arg.EntryID]; !ok {
+		return SharedExpense{}, foreignKeyViolation("shared_expenses", "shared_expenses_entry_id_fkey")
+	}
+	err := q.db.checkUser(arg.PaidBy, "shared_expenses", "shared_expenses_paid_by_fkey")
+	if err != nil {
+		return SharedExpense{}, err
+	}
+
+	q.db.sharedExpenses[expense.ID] = expense
+	return expense, nil
+}
+
+func (q *memQueries) GetSharedExpenseByEntry(ctx context.Context, entryID int32) (SharedExpense, error) {
+	defer q.lock()()
+	return q.db.sharedExpenseByEntry(entryID)
+}
+
+func (q *memQueries) CreateExpenseShare(ctx context.Context, arg CreateExpenseShareParams) (ExpenseShare, error) {
+	defer q.lock()()
+
+	key := expenseShareKey{arg.ExpenseID, arg.Username}
+	if _, ok := q.db.expenseShares[key]; ok {
+		return ExpenseShare{}, uniqueViolation("expense_shares", "expense_shares_pkey")
+	}
+	if _, ok := q.db.sharedExpenses[arg.ExpenseID]; !ok {
+		return ExpenseShare{}, foreignKeyViolation("expense_shares", "expense_shares_expense_id_fkey")
+	}
+	err := q.db.checkUser(arg.Username, "expense_shares", "expense_shares_username_fkey")
+	if err != nil {
+		return ExpenseShare{}, err
+	}
+
+	share := ExpenseShare{
+		ExpenseID: arg.ExpenseID,
+		Username:  arg.Username,
+		Amount:    arg.Amount,
+	}
+	q.db.expenseShares[key] = share
+	return share, nil
+}
+
+func (q *memQueries) ListExpenseShares(ctx context.Context, expenseID int32) ([]ExpenseShare, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.expenseShares, func(share ExpenseShare) bool {
+		return share.ExpenseID == expenseID
+	}, func(a ExpenseShare, b ExpenseShare) bool {
+		return a.Username < b.Username
+	}), nil
+}
+
+func (q *memQueries) UpdateExpenseShare(ctx context.Context, arg UpdateExpenseShareParams) (ExpenseShare, error) {
+	defer q.lock()()
+
+	key := expenseShareKey{arg.ExpenseID, arg.Username}
+	share, ok := q.db.expenseShares[key]
+	if !ok {
+		return ExpenseShare{}, sql.ErrNoRows
+	}
+
+	share.Amount = arg.Amount
+	q.db.expenseShares[key] = share
+	return share, nil
+}
+
+func (q *memQueries) AddToBalance(ctx context.Context, arg AddToBalanceParams) (Balance, error) {
+	defer q.lock()()
+
+	if arg.UserA >= arg.UserB {
+		return Balance{}, checkViolation("balances", "balances_ordered_check")
+	}
+
+	key := balanceKey{arg.UserA, arg.UserB}
+	balance, ok := q.db.balances[key]
+	if ok {
+		balance.Amount += arg.Amount
+		balance.UpdatedAt = memNow()
+		q.db.balances[key] = balance
+		return balance, nil
+	}
+
+	err := q.db.checkUser(arg.UserA, "balances", "balances_user_a_fkey")
+	if err != nil {
+		return Balance{}, err
+	}
+	err = q.db.checkUser(arg.UserB, "balances", "balances_user_b_fkey")
+	if err != nil {
+		return Balance{}, err
+	}
+
+	balance = Balance{
+		UserA:     arg.UserA,
+		UserB:     arg.UserB,
+		Amount:    arg.Amount,
+		UpdatedAt: memNow(),
+	}
+	q.db.balances[key] = balance
+	return balance, nil
+}
+
+func (q *memQueries) GetBalanceForUpdate(ctx context.Context, arg GetBalanceForUpdateParams) (Balance, error) {
+	defer q.lock()()
+
+	balance, ok := q.db.balances[balanceKey{arg.UserA, arg.UserB}]
+	if !ok {
+		return Balance{}, sql.ErrNoRows
+	}
+	return balance, nil
+}
+
+func (q *memQueries) ListBalances(ctx context.Context, username string) ([]Balance, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.balances, func(balance Balance) bool {
+		return (balance.UserA == username || balance.UserB == username) && balance.Amount != 0
+	}, balancesByUsers), nil
+}
+
+func (q *memQueries) ListBalancesAmong(ctx context.Context, usernames []string) ([]Balance, error) {
+	defer q.lock()()
+
+	among := stringSet(usernames)
+	return selectRows(q.db.balances, func(balance Balance) bool {
+		return among[balance.UserA] && among[balance.UserB] && balance.Amount != 0
+	}, balancesByUsers), nil
+}
+
+func (q *memQueries) CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error) {
+	defer q.lock()()
+
+	settlement := Settlement{
+		ID:           int32(q.db.nextID("settlements")),
+		FromUsername: arg.FromUsername,
+		ToUsername:   arg.ToUsername,
+		Amount:       arg.Amount,
+		CreatedAt:    memNow(),
+	}
+	if arg.Amount <= 0 {
+		return Settlement{}, checkViolation("settlements", "settlements_amount_check")
+	}
+	err := q.db.checkUser(arg.FromUsername, "settlements", "settlements_from_username_fkey")
+	if err != nil {
+		return Settlement{}, err
+	}
+	err = q.db.checkUser(arg.ToUsername, "settlements", "settlements_to_username_fkey")
+	if err != nil {
+		return Settlement{}, err
+	}
+
+	q.db.settlements[settlement.ID] = settlement
+	return settlement, nil
+}
+
+func (q *memQueries) ListSettlements(ctx context.Context, username string) ([]Settlement, error) {
+	defer q.lock()()
+
+	return selectRows(q.db.settlements, func(settlement Settlement) bool {
+		return settlement.FromUsername == username || settlement.ToUsername == username
+	}, func(a Settlement, b Settlement) bool {
+		return a.ID > b.ID
+	}), nil
+}
+
+func balancesByUsers(a Balance, b Balance) bool {
+	if a.UserA != b.UserA {
+		return a.UserA < b.UserA
+	}
+	return a.UserB < b.UserB
+}
+
+func (db *memDB) sharedExpenseByEntry(entryID int32) (SharedExpense, error) {
+	for _, expense := range db.sharedExpenses {
+		if expense.EntryID == entryID {
+			return expense, nil
+		}
+	}
+	return SharedExpense{}, sql.ErrNoRows
+}
+
+// Deletes the shared expense and, on cascade, its shares
+func (db *memDB) deleteSharedExpense(id int32) {
+	for key, share := range db.expenseShares {
+		if share.ExpenseID == id {
+			delete(db.expenseShares, key)
+		}
+	}
+	delete(db.sharedExpenses, id)
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemStoreConformance(t *testing.T) {
+	testStoreConformance(t, NewMemStore())
+}
+
+func TestMemStoreMigrationVersion(t *testing.T) {
+	latest, err := migration.LatestVersion(PostgresDriver)
+	require.NoError(t, err)
+
+	version, err := NewMemStore().GetMigrationVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(latest), version.Version)
+	require.False(t, version.Dirty)
+}
@@ -0,0 +1,355 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+func (q *memQueries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	defer q.lock()()
+
+	if _, ok := q.db.users[arg.Username]; ok {
+		return User{}, uniqueViolation("users", "users_pkey")
+	}
+	if q.db.emailTaken(arg.Email, "") {
+		return User{}, uniqueViolation("users", "users_email_key")
+	}
+
+	user := User{
+		Username:       arg.Username,
+		HashedPassword: arg.HashedPassword,
+		FullName:       arg.FullName,
+		Email:          arg.Email,
+		TotalExpenses:  arg.TotalExpenses,
+		CreatedAt:      memNow(),
+	}
+	q.db.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memQueries) GetUser(ctx context.Context, username string) (User, error) {
+	defer q.lock()()
+	return q.db.activeUser(username)
+}
+
+func (q *memQueries) GetEmail(ctx context.Context, username string) (User, error) {
+	defer q.lock()()
+	return q.db.activeUser(username)
+}
+
+func (q *memQueries) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	defer q.lock()()
+	return q.db.activeUser(username)
+}
+
+func (q *memQueries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	defer q.lock()()
+
+	users := selectRows(q.db.users, func(user User) bool {
+		return !user.DeletedAt.Valid
+	}, usersByUsername)
+	return paginate(users, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) GetUsers(ctx context.Context, usernames []string) ([]User, error) {
+	defer q.lock()()
+
+	wanted := stringSet(usernames)
+	return selectRows(q.db.users, func(user User) bool {
+		return wanted[user.Username] && !user.DeletedAt.Valid
+	}, usersByUsername), nil
+}
+
+func (q *memQueries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	defer q.lock()()
+
+	user, err := q.db.activeUser(arg.Username)
+	if err != nil {
+		return User{}, err
+	}
+
+	user.TotalExpenses = arg.TotalExpenses
+	q.db.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memQueries) ResetPassword(ctx context.Context, arg ResetPasswordParams) error {
+	defer q.lock()()
+
+	user, err := q.db.activeUser(arg.Username)
+	if err != nil {
+		return nil
+	}
+
+	user.HashedPassword = arg.HashedPassword
+	q.db.users[user.Username] = user
+	return nil
+}
+
+func (q *memQueries) DeleteUser(ctx context.Context, username string) error {
+	defer q.lock()()
+
+	user, err := q.db.activeUser(username)
+	if err != nil {
+		return nil
+	}
+
+	user.DeletedAt = sql.NullTime{Time: memNow(), Valid: true}
+	q.db.users[user.Username] = user
+	return nil
+}
+
+func (q *memQueries) UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams) (User, error) {
+	defer q.lock()()
+
+	user, err := q.db.activeUser(arg.Username)
+	if err != nil {
+		return User{}, err
+	}
+
+	if arg.Username_2 != user.Username {
+		if _, ok := q.db.users[arg.Username_2]; ok {
+			return User{}, uniqueViolation("users", "users_pkey")
+		}
+	}
+	if q.db.emailTaken(arg.Email, user.Username) {
+		return User{}, uniqueViolation("users", "users_email_key")
+	}
+
+	if arg.Username_2 != user.Username {
+		err = q.db.renameUser(user.Username, arg.Username_2)
+		if err != nil {
+			return User{}, err
+		}
+	}
+
+	user.Username = arg.Username_2
+	user.FullName = arg.FullName
+	user.Email = arg.Email
+	q.db.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memQueries) GetDeletedUser(ctx context.Context, username string) (User, error) {
+	defer q.lock()()
+
+	user, ok := q.db.users[username]
+	if !ok || !user.DeletedAt.Valid {
+		return User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+func (q *memQueries) RestoreUser(ctx context.Context, username string) (User, error) {
+	defer q.lock()()
+
+	user, ok := q.db.users[username]
+	if !ok || !user.DeletedAt.Valid {
+		return User{}, sql.ErrNoRows
+	}
+
+	user.DeletedAt = sql.NullTime{}
+	q.db.users[user.Username] = user
+	return user, nil
+}
+
+func (q *memQueries) PurgeUsers(ctx context.Context, deletedBefore sql.NullTime) ([]User, error) {
+	defer q.lock()()
+
+	purged := selectRows(q.db.users, func(user User) bool {
+		return user.DeletedAt.Valid && deletedBefore.Valid && user.DeletedAt.Time.Before(deletedBefore.Time)
+	}, usersByUsername)
+	for _, user := range purged {
+		err := q.db.checkUserHasNoEntries(user.Username)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, user := range purged {
+		q.db.deleteUser(user.Username)
+	}
+	return purged, nil
+}
+
+func (q *memQueries) ListTotalExpensesDrift(ctx context.Context) ([]ListTotalExpensesDriftRow, error) {
+	defer q.lock()()
+
+	users := selectRows(q.db.users, func(user User) bool {
+		return !user.DeletedAt.Valid
+	}, usersByUsername)
+
+	drifts := []ListTotalExpensesDriftRow{}
+	for _, user := range users {
+		total := q.db.entriesTotal(user.Username)
+		if total != user.TotalExpenses {
+			drifts = append(drifts, ListTotalExpensesDriftRow{
+				Username:      user.Username,
+				TotalExpenses: user.TotalExpenses,
+				EntriesTotal:  total,
+			})
+		}
+	}
+	return drifts, nil
+}
+
+func usersByUsername(a User, b User) bool {
+	return a.Username < b.Username
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// Returns the user unless it is missing or in the trash
+func (db *memDB) activeUser(username string) (User, error) {
+	user, ok := db.users[username]
+	if !ok || user.DeletedAt.Valid {
+		return User{}, sql.ErrNoRows
+	}
+	return user, nil
+}
+
+// Tells whether a user other than except has the email
+func (db *memDB) emailTaken(email string, except string) bool {
+	for _, user := range db.users {
+		if user.Email == email && user.Username != except {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks that the user exists, for the foreign key constraint of table
+func (db *memDB) checkUser(username string, table string, constraint string) error {
+	if _, ok := db.users[username]; !ok {
+		return foreignKeyViolation(table, constraint)
+	}
+	return nil
+}
+
+// Checks that no entry references the user, their foreign key has no action
+func (db *memDB) checkUserHasNoEntries(username string) error {
+	for _, entry := range db.entries {
+		if entry.Owner == username {
+			return foreignKeyViolation("entries", "entries_owner_fkey")
+		}
+	}
+	return nil
+}
+
+// Moves the user to a new username, updating the rows that reference it on
+// cascade. The user row itself is moved by the caller.
+func (db *memDB) renameUser(from string, to string) error {
+	err := db.checkUserHasNoEntries(from)
+	if err != nil {
+		return err
+	}
+
+	rename := func(username string) string {
+		if username == from {
+			return to
+		}
+		return username
+	}
+
+	// the statement fails as a whole, so every check goes before the changes
+	for _, balance := range db.balances {
+		if (balance.UserA == from || balance.UserB == from) && rename(balance.UserA) >= rename(balance.UserB) {
+			return checkViolation("balances", "balances_ordered_check")
+		}
+	}
+
+	for key, member := range db.householdMembers {
+		if member.Username == from {
+			delete(db.householdMembers, key)
+			member.Username = to
+			db.householdMembers[householdMemberKey{member.HouseholdID, to}] = member
+		}
+	}
+	for id, expense := range db.sharedExpenses {
+		expense.PaidBy = rename(expense.PaidBy)
+		db.sharedExpenses[id] = expense
+	}
+	for key, share := range db.expenseShares {
+		if share.Username == from {
+			delete(db.expenseShares, key)
+			share.Username = to
+			db.expenseShares[expenseShareKey{share.ExpenseID, to}] = share
+		}
+	}
+	for key, balance := range db.balances {
+		if balance.UserA != from && balance.UserB != from {
+			continue
+		}
+		delete(db.balances, key)
+		balance.UserA = rename(balance.UserA)
+		balance.UserB = rename(balance.UserB)
+		db.balances[balanceKey{balance.UserA, balance.UserB}] = balance
+	}
+	for id, settlement := range db.settlements {
+		settlement.FromUsername = rename(settlement.FromUsername)
+		settlement.ToUsername = rename(settlement.ToUsername)
+		db.settlements[id] = settlement
+	}
+	for key, idempotencyKey := range db.idempotencyKeys {
+		if idempotencyKey.Username == from {
+			delete(db.idempotencyKeys, key)
+			idempotencyKey.Username = to
+			db.idempotencyKeys[idempotencyKeyKey{to, idempotencyKey.Key}] = idempotencyKey
+		}
+	}
+	for id, webhook := range db.webhooks {
+		webhook.Owner = rename(webhook.Owner)
+		db.webhooks[id] = webhook
+	}
+
+	delete(db.users, from)
+	return nil
+}
+
+// Deletes the user and, on cascade, the rows that reference it. The caller
+// checks that no entry references it.
+func (db *memDB) deleteUser(username string) {
+	for key, member := range db.householdMembers {
+		if member.Username == username {
+			delete(db.householdMembers, key)
+		}
+	}
+	for id, expense := range db.sharedExpenses {
+		if expense.PaidBy == username {
+			db.deleteSharedExpense(id)
+		}
+	}
+	for key, share := range db.expenseShares {
+		if share.Username == username {
+			delete(db.expenseShares, key)
+		}
+	}
+	for key, balance := range db.balances {
+		if balance.UserA == username || balance.UserB == username {
+			delete(db.balances, key)
+		}
+	}
+	for id, settlement := range db.settlements {
+		if settlement.FromUsername == username || settlement.ToUsername == username {
+			delete(db.settlements, id)
+		}
+	}
+	for key, idempotencyKey := range db.idempotencyKeys {
+		if idempotencyKey.Username == username {
+			delete(db.idempotencyKeys, key)
+		}
+	}
+	for id, webhook := range db.webhooks {
+		if webhook.Owner == username {
+			db.deleteWebhook(id)
+		}
+	}
+
+	delete(db.users, username)
+}
@@ -0,0 +1,284 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+func (q *memQueries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	defer q.lock()()
+
+	webhook := Webhook{
+		ID:         int32(q.db.nextID("webhooks")),
+		Owner:      arg.Owner,
+		Url:        arg.Url,
+		Secret:     arg.Secret,
+		EventTypes: append([]string{}, arg.EventTypes...),
+		Budget:     arg.Budget,
+		CreatedAt:  memNow(),
+	}
+	err := q.db.checkUser(arg.Owner, "webhooks", "webhooks_owner_fkey")
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	q.db.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (q *memQueries) GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error) {
+	defer q.lock()()
+
+	webhook, ok := q.db.webhooks[arg.ID]
+	if !ok || webhook.Owner != arg.Owner {
+		return Webhook{}, sql.ErrNoRows
+	}
+	return webhook, nil
+}
+
+func (q *memQueries) GetWebhookForDelivery(ctx context.Context, id int32) (Webhook, error) {
+	defer q.lock()()
+
+	webhook, ok := q.db.webhooks[id]
+	if !ok {
+		return Webhook{}, sql.ErrNoRows
+	}
+	return webhook, nil
+}
+
+func (q *memQueries) ListWebhooks(ctx context.Context, owner string) ([]Webhook, error) {
+	defer q.lock()()
+	return q.db.webhooksOf(owner, ""), nil
+}
+
+func (q *memQueries) DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error) {
+	defer q.lock()()
+
+	webhook, ok := q.db.webhooks[arg.ID]
+	if !ok || webhook.Owner != arg.Owner {
+		return 0, nil
+	}
+
+	q.db.deleteWebhook(webhook.ID)
+	return 1, nil
+}
+
+func (q *memQueries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	defer q.lock()()
+
+	id := q.db.nextID("webhook_deliveries")
+	if q.db.deliveryExists(arg.WebhookID, arg.EventType, arg.DedupeKey) {
+		return WebhookDelivery{}, uniqueViolation("webhook_deliveries", "webhook_deliveries_webhook_id_event_type_dedupe_key_idx")
+	}
+	if _, ok := q.db.webhooks[arg.WebhookID]; !ok {
+		return WebhookDelivery{}, foreignKeyViolation("webhook_deliveries", "webhook_deliveries_webhook_id_fkey")
+	}
+
+	return q.db.insertDelivery(id, arg.WebhookID, arg.EventType, arg.DedupeKey, arg.Payload), nil
+}
+
+func (q *memQueries) EnqueueWebhookEvent(ctx context.Context, arg EnqueueWebhookEventParams) (int64, error) {
+	defer q.lock()()
+
+	var queued int64
+	for _, webhook := range q.db.webhooksOf(arg.Owner, arg.EventType) {
+		if q.db.enqueueDelivery(webhook.ID, arg.EventType, arg.DedupeKey, arg.Payload) {
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+func (q *memQueries) EnqueueBudgetExceeded(ctx context.Context, arg EnqueueBudgetExceededParams) (int64, error) {
+	defer q.lock()()
+
+	var total int64
+	for _, entry := range q.db.entries {
+		if isPersonalEntryOf(entry, arg.Owner) && !entry.DeletedAt.Valid &&
+			!entry.DueDate.Before(arg.MonthStart) && entry.DueDate.Before(arg.MonthEnd) {
+			total += entry.Amount
+		}
+	}
+
+	var queued int64
+	for _, webhook := range q.db.webhooksOf(arg.Owner, util.BudgetExceededEvent) {
+		if webhook.Budget <= 0 || total <= webhook.Budget {
+			continue
+		}
+
+		payload, err := json.Marshal(map[string]interface{}{
+			"month":  arg.Month,
+			"total":  total,
+			"budget": webhook.Budget,
+		})
+		if err != nil {
+			return queued, err
+		}
+		if q.db.enqueueDelivery(webhook.ID, util.BudgetExceededEvent, arg.Month, payload) {
+			queued++
+		}
+	}
+	return queued, nil
+}
+
+func (q *memQueries) EnqueueBillDue(ctx context.Context, arg EnqueueBillDueParams) (int64, error) {
+	defer q.lock()()
+
+	due := selectRows(q.db.entries, func(entry Entry) bool {
+		return !entry.HouseholdID.Valid && !entry.DeletedAt.Valid &&
+			!entry.DueDate.Before(arg.DueAfter) && entry.DueDate.Before(arg.DueBefore)
+	}, entriesByID)
+
+	var queued int64
+	for _, entry := range due {
+		for _, webhook := range q.db.webhooksOf(entry.Owner, util.BillDueEvent) {
+			payload, err := json.Marshal(map[string]webhookEntry{"entry": newWebhookEntry(entry)})
+			if err != nil {
+				return queued, err
+			}
+
+			dedupeKey := fmt.Sprintf("%d:%s", entry.ID, entry.DueDate.Format("2006-01-02"))
+			if q.db.enqueueDelivery(webhook.ID, util.BillDueEvent, dedupeKey, payload) {
+				queued++
+			}
+		}
+	}
+	return queued, nil
+}
+
+func (q *memQueries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	defer q.lock()()
+
+	deliveries := selectRows(q.db.webhookDeliveries, func(delivery WebhookDelivery) bool {
+		return delivery.WebhookID == arg.WebhookID
+	}, func(a WebhookDelivery, b WebhookDelivery) bool {
+		return a.ID > b.ID
+	})
+	return paginate(deliveries, arg.Limit, arg.Offset), nil
+}
+
+func (q *memQueries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	defer q.lock()()
+
+	now := memNow()
+	claimed := selectRows(q.db.webhookDeliveries, func(delivery WebhookDelivery) bool {
+		return delivery.Status == "pending" && !delivery.NextAttemptAt.After(now)
+	}, func(a WebhookDelivery, b WebhookDelivery) bool {
+		if !a.NextAttemptAt.Equal(b.NextAttemptAt) {
+			return a.NextAttemptAt.Before(b.NextAttemptAt)
+		}
+		return a.ID < b.ID
+	})
+	claimed = paginate(claimed, arg.MaxDeliveries, 0)
+
+	for i, delivery := range claimed {
+		delivery.Attempts++
+		delivery.NextAttemptAt = memTime(arg.LeaseUntil)
+		q.db.webhookDeliveries[delivery.ID] = delivery
+		claimed[i] = delivery
+	}
+	return claimed, nil
+}
+
+func (q *memQueries) CompleteWebhookDelivery(ctx context.Context, arg CompleteWebhookDeliveryParams) error {
+	defer q.lock()()
+
+	delivery, ok := q.db.webhookDeliveries[arg.ID]
+	if !ok {
+		return nil
+	}
+
+	delivery.Status = "succeeded"
+	delivery.LastStatusCode = arg.LastStatusCode
+	delivery.LastError = ""
+	delivery.DeliveredAt = sql.NullTime{Time: memNow(), Valid: true}
+	q.db.webhookDeliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (q *memQueries) FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error {
+	defer q.lock()()
+
+	delivery, ok := q.db.webhookDeliveries[arg.ID]
+	if !ok {
+		return nil
+	}
+
+	delivery.Status = arg.Status
+	delivery.LastStatusCode = arg.LastStatusCode
+	delivery.LastError = arg.LastError
+	delivery.NextAttemptAt = memTime(arg.NextAttemptAt)
+	q.db.webhookDeliveries[delivery.ID] = delivery
+	return nil
+}
+
+// Returns the webhooks of the owner by ID, only those subscribed to eventType unless it is empty
+func (db *memDB) webhooksOf(owner string, eventType string) []Webhook {
+	return selectRows(db.webhooks, func(webhook Webhook) bool {
+		if webhook.Owner != owner {
+			return false
+		}
+		if eventType == "" {
+			return true
+		}
+		for _, subscribed := range webhook.EventTypes {
+			if subscribed == eventType {
+				return true
+			}
+		}
+		return false
+	}, func(a Webhook, b Webhook) bool {
+		return a.ID < b.ID
+	})
+}
+
+// Deletes the webhook and, on cascade, its deliveries
+func (db *memDB) deleteWebhook(id int32) {
+	for deliveryID, delivery := range db.webhookDeliveries {
+		if delivery.WebhookID == id {
+			delete(db.webhookDeliveries, deliveryID)
+		}
+	}
+	delete(db.webhooks, id)
+}
+
+func (db *memDB) deliveryExists(webhookID int32, eventType string, dedupeKey string) bool {
+	for _, delivery := range db.webhookDeliveries {
+		if delivery.WebhookID == webhookID && delivery.EventType == eventType && delivery.DedupeKey == dedupeKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Queues a delivery unless the event was already queued for the webhook, like
+// ON CONFLICT DO NOTHING. Tells whether it was queued.
+func (db *memDB) enqueueDelivery(webhookID int32, eventType string, dedupeKey string, payload json.RawMessage) bool {
+	id := db.nextID("webhook_deliveries")
+	if db.deliveryExists(webhookID, eventType, dedupeKey) {
+		return false
+	}
+
+	db.insertDelivery(id, webhookID, eventType, dedupeKey, payload)
+	return true
+}
+
+func (db *memDB) insertDelivery(id int64, webhookID int32, eventType string, dedupeKey string, payload json.RawMessage) WebhookDelivery {
+	now := memNow()
+	delivery := WebhookDelivery{
+		ID:            id,
+		WebhookID:     webhookID,
+		EventType:     eventType,
+		DedupeKey:     dedupeKey,
+		Payload:       cloneBytes(payload),
+		Status:        "pending",
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	db.webhookDeliveries[id] = delivery
+	return delivery
+}
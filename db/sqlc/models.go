@@ -6,17 +6,115 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// username that made the change, kept after the user is deleted
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	ClientIp   string          `json:"client_ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type Balance struct {
+	UserA string `json:"user_a"`
+	UserB string `json:"user_b"`
+	// positive when user_b owes user_a
+	Amount    int64     `json:"amount"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type Entry struct {
 	ID      int32     `json:"id"`
 	Owner   string    `json:"owner"`
 	Name    string    `json:"name"`
 	DueDate time.Time `json:"due_date"`
 	// must be positive
-	Amount   int64          `json:"amount"`
-	Category sql.NullString `json:"category"`
+	Amount      int64          `json:"amount"`
+	Category    sql.NullString `json:"category"`
+	HouseholdID sql.NullInt32  `json:"household_id"`
+	// member who created the entry
+	CreatedBy sql.NullString `json:"created_by"`
+	// set while the entry is in the trash
+	DeletedAt sql.NullTime `json:"deleted_at"`
+	// incremented on every change, used for optimistic concurrency
+	Version int32 `json:"version"`
+}
+
+type ExpenseShare struct {
+	ExpenseID int32  `json:"expense_id"`
+	Username  string `json:"username"`
+	// part of the entry amount owed by the user
+	Amount int64 `json:"amount"`
+}
+
+type Household struct {
+	ID        int32     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type HouseholdInvitation struct {
+	ID          int32     `json:"id"`
+	HouseholdID int32     `json:"household_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	InvitedBy   string    `json:"invited_by"`
+	Accepted    bool      `json:"accepted"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type HouseholdMember struct {
+	HouseholdID int32  `json:"household_id"`
+	Username    string `json:"username"`
+	// owner, editor or viewer
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username string `json:"username"`
+	Key      string `json:"key"`
+	// sha256 of the method, path and body of the first request
+	RequestHash string `json:"request_hash"`
+	// 0 while the first request is still running
+	StatusCode   int32     `json:"status_code"`
+	ResponseBody []byte    `json:"response_body"`
+	CreatedAt    time.Time `json:"created_at"`
+	// Content-Type of the stored response
+	ContentType string `json:"content_type"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Settlement struct {
+	ID           int32  `json:"id"`
+	FromUsername string `json:"from_username"`
+	ToUsername   string `json:"to_username"`
+	// must be positive
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SharedExpense struct {
+	ID      int32  `json:"id"`
+	EntryID int32  `json:"entry_id"`
+	PaidBy  string `json:"paid_by"`
+	// equal, exact or percentage
+	SplitType string    `json:"split_type"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -27,4 +125,35 @@ type User struct {
 	TotalExpenses     int64     `json:"total_expenses"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// set while the account is in the trash
+	DeletedAt sql.NullTime `json:"deleted_at"`
+}
+
+type Webhook struct {
+	ID    int32  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// key of the HMAC-SHA256 signature of the payloads
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	// monthly expenses above which budget.exceeded is sent, 0 when unused
+	Budget    int64     `json:"budget"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID        int64  `json:"id"`
+	WebhookID int32  `json:"webhook_id"`
+	EventType string `json:"event_type"`
+	// identifies the occurrence of the event so it is only delivered once
+	DedupeKey string          `json:"dedupe_key"`
+	Payload   json.RawMessage `json:"payload"`
+	// pending, succeeded or failed
+	Status         string       `json:"status"`
+	Attempts       int32        `json:"attempts"`
+	LastStatusCode int32        `json:"last_status_code"`
+	LastError      string       `json:"last_error"`
+	NextAttemptAt  time.Time    `json:"next_attempt_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	DeliveredAt    sql.NullTime `json:"delivered_at"`
 }
@@ -7,25 +7,96 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AcceptHouseholdInvitation(ctx context.Context, id int32) (HouseholdInvitation, error)
+	AddHouseholdMember(ctx context.Context, arg AddHouseholdMemberParams) (HouseholdMember, error)
+	AddToBalance(ctx context.Context, arg AddToBalanceParams) (Balance, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CompleteWebhookDelivery(ctx context.Context, arg CompleteWebhookDeliveryParams) error
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateExpenseShare(ctx context.Context, arg CreateExpenseShareParams) (ExpenseShare, error)
+	CreateHousehold(ctx context.Context, name string) (Household, error)
+	CreateHouseholdEntry(ctx context.Context, arg CreateHouseholdEntryParams) (Entry, error)
+	CreateHouseholdInvitation(ctx context.Context, arg CreateHouseholdInvitationParams) (HouseholdInvitation, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateRateLimitBucket(ctx context.Context, arg CreateRateLimitBucketParams) error
+	CreateSettlement(ctx context.Context, arg CreateSettlementParams) (Settlement, error)
+	CreateSharedExpense(ctx context.Context, arg CreateSharedExpenseParams) (SharedExpense, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
 	DeleteEntries(ctx context.Context, owner string) error
 	DeleteEntry(ctx context.Context, id int32) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, createdAt time.Time) (int64, error)
+	DeleteHousehold(ctx context.Context, id int32) error
+	DeleteHouseholdEntry(ctx context.Context, arg DeleteHouseholdEntryParams) error
+	DeleteHouseholdMember(ctx context.Context, arg DeleteHouseholdMemberParams) error
+	DeleteIdempotencyKey(ctx context.Context, arg DeleteIdempotencyKeyParams) error
+	DeleteIdleRateLimitBuckets(ctx context.Context, updatedAt time.Time) (int64, error)
 	DeleteUser(ctx context.Context, username string) error
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	EnqueueBillDue(ctx context.Context, arg EnqueueBillDueParams) (int64, error)
+	EnqueueBudgetExceeded(ctx context.Context, arg EnqueueBudgetExceededParams) (int64, error)
+	EnqueueWebhookEvent(ctx context.Context, arg EnqueueWebhookEventParams) (int64, error)
+	FailWebhookDelivery(ctx context.Context, arg FailWebhookDeliveryParams) error
+	GetBalanceForUpdate(ctx context.Context, arg GetBalanceForUpdateParams) (Balance, error)
 	GetCategories(ctx context.Context, owner string) ([]sql.NullString, error)
+	GetDeletedEntryForUpdate(ctx context.Context, arg GetDeletedEntryForUpdateParams) (Entry, error)
+	GetDeletedHouseholdEntryForUpdate(ctx context.Context, arg GetDeletedHouseholdEntryForUpdateParams) (Entry, error)
+	GetDeletedUser(ctx context.Context, username string) (User, error)
 	GetEmail(ctx context.Context, username string) (User, error)
 	GetEntries(ctx context.Context, owner string) ([]Entry, error)
+	GetEntriesOfHouseholds(ctx context.Context, householdIds []int32) ([]Entry, error)
+	GetEntriesTotal(ctx context.Context, owner string) (int64, error)
 	GetEntry(ctx context.Context, arg GetEntryParams) (Entry, error)
 	GetEntryForUpdate(ctx context.Context, arg GetEntryForUpdateParams) (Entry, error)
+	GetHousehold(ctx context.Context, id int32) (Household, error)
+	GetHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error)
+	GetHouseholdEntry(ctx context.Context, arg GetHouseholdEntryParams) (Entry, error)
+	GetHouseholdEntryForUpdate(ctx context.Context, arg GetHouseholdEntryForUpdateParams) (Entry, error)
+	GetHouseholdInvitationForUpdate(ctx context.Context, id int32) (HouseholdInvitation, error)
+	GetHouseholdMember(ctx context.Context, arg GetHouseholdMemberParams) (HouseholdMember, error)
+	GetHouseholdMemberForUpdate(ctx context.Context, arg GetHouseholdMemberForUpdateParams) (HouseholdMember, error)
+	GetHouseholdTotal(ctx context.Context, householdID sql.NullInt32) (int64, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetRateLimitBucketForUpdate(ctx context.Context, key string) (RateLimitBucket, error)
+	GetSharedExpenseByEntry(ctx context.Context, entryID int32) (SharedExpense, error)
 	GetUser(ctx context.Context, username string) (User, error)
 	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetUsers(ctx context.Context, usernames []string) ([]User, error)
+	GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error)
+	GetWebhookForDelivery(ctx context.Context, id int32) (Webhook, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListBalances(ctx context.Context, username string) ([]Balance, error)
+	ListBalancesAmong(ctx context.Context, usernames []string) ([]Balance, error)
+	ListDeletedEntries(ctx context.Context, owner string) ([]Entry, error)
+	ListDeletedHouseholdEntries(ctx context.Context, householdID sql.NullInt32) ([]Entry, error)
+	ListExpenseShares(ctx context.Context, expenseID int32) ([]ExpenseShare, error)
+	ListHouseholdInvitations(ctx context.Context, email string) ([]HouseholdInvitation, error)
+	ListHouseholdMembers(ctx context.Context, householdID int32) ([]HouseholdMember, error)
+	ListHouseholds(ctx context.Context, username string) ([]Household, error)
+	ListMembersOfHouseholds(ctx context.Context, householdIds []int32) ([]HouseholdMember, error)
+	ListSettlements(ctx context.Context, username string) ([]Settlement, error)
+	ListTotalExpensesDrift(ctx context.Context) ([]ListTotalExpensesDriftRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhooks(ctx context.Context, owner string) ([]Webhook, error)
+	PurgeEntries(ctx context.Context, deletedBefore sql.NullTime) ([]Entry, error)
+	PurgeUsers(ctx context.Context, deletedBefore sql.NullTime) ([]User, error)
 	ResetPassword(ctx context.Context, arg ResetPasswordParams) error
+	RestoreEntry(ctx context.Context, id int32) (Entry, error)
+	RestoreUser(ctx context.Context, username string) (User, error)
+	SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error
 	UpdateEntriesOwner(ctx context.Context, arg UpdateEntriesOwnerParams) error
 	UpdateEntry(ctx context.Context, arg UpdateEntryParams) (Entry, error)
+	UpdateExpenseShare(ctx context.Context, arg UpdateExpenseShareParams) (ExpenseShare, error)
+	UpdateHouseholdEntry(ctx context.Context, arg UpdateHouseholdEntryParams) (Entry, error)
+	UpdateHouseholdMemberRole(ctx context.Context, arg UpdateHouseholdMemberRoleParams) (HouseholdMember, error)
+	UpdateRateLimitBucket(ctx context.Context, arg UpdateRateLimitBucketParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams) (User, error)
 }
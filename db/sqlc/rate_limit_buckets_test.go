@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeRateLimitTokenTx(t *testing.T) {
+	store := newTestStore()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	arg := TakeRateLimitTokenTxParams{
+		Key:   util.RandomString(12),
+		Limit: util.RateLimit{Requests: 2, Period: time.Minute},
+		Now:   now,
+	}
+
+	for _, remaining := range []int{1, 0} {
+		result, err := store.TakeRateLimitTokenTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, remaining, result.Remaining)
+	}
+
+	result, err := store.TakeRateLimitTokenTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 30*time.Second, result.RetryAfter)
+
+	// tokens come back over time
+	arg.Now = now.Add(30 * time.Second)
+	result, err = store.TakeRateLimitTokenTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	deleted, err := testQueries.DeleteIdleRateLimitBuckets(context.Background(), arg.Now.Add(time.Second))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetRateLimitBucketForUpdate(context.Background(), arg.Key)
+	require.Error(t, err)
+}
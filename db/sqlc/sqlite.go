@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/sqlite"
+)
+
+// sqliteDB runs the sqlc queries on SQLite by swapping each one for its SQLite
+// version, found by the name in its header. The other statements run as is.
+type sqliteDB struct {
+	db DBTX
+}
+
+func newSQLiteDB(db DBTX) DBTX {
+	return &sqliteDB{db: db}
+}
+
+func (s *sqliteDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func (s *sqliteDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return s.db.PrepareContext(ctx, sqliteQuery(query))
+}
+
+func (s *sqliteDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func (s *sqliteDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.db.QueryRowContext(ctx, sqliteQuery(query), sqliteArgs(args)...)
+}
+
+func sqliteQuery(query string) string {
+	if translated, ok := sqlite.Query(queryName(query)); ok {
+		return translated
+	}
+	return query
+}
+
+// Stores times in the layout the SQLite queries compare, and JSON as blobs
+func sqliteArgs(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case time.Time:
+			converted[i] = arg.UTC().Format(sqlite.TimeLayout)
+		case sql.NullTime:
+			if arg.Valid {
+				converted[i] = arg.Time.UTC().Format(sqlite.TimeLayout)
+			}
+		case json.RawMessage:
+			converted[i] = []byte(arg)
+		default:
+			converted[i] = arg
+		}
+	}
+	return converted
+}
@@ -3,53 +3,197 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
 	_ "github.com/golang/mock/mockgen/model"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var ErrInvitationNotValid = errors.New("invitation is not valid for this user")
+
+var ErrShareNotHouseholdMember = errors.New("expenses can only be shared with members of your households")
+
+var ErrSettlementExceedsDebt = errors.New("amount is more than what is owed")
+
+// VersionMismatchError is returned when an entry changed since the client read it
+type VersionMismatchError struct {
+	Current Entry
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("entry %d is at version %d", e.Current.ID, e.Current.Version)
+}
+
+// Fails with a VersionMismatchError unless the entry is at the expected version,
+// an expected version of 0 skips the check
+func checkVersion(entry Entry, expected int32) error {
+	if expected != 0 && entry.Version != expected {
+		return &VersionMismatchError{Current: entry}
+	}
+	return nil
+}
+
 // Store gives all functions to execute db queries and transactions
 type Store interface {
 	Querier
 	AddEntryTx(ctx context.Context, arg AddEntryTxParams) (AddEntryTxResult, error)
 	DeleteEntryTx(ctx context.Context, arg DeleteEntryTxParams) (DeleteEntryTxResult, error)
 	UpdateEntryTx(ctx context.Context, arg UpdateEntryTxParams) (UpdateEntryTxResult, error)
+	CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordParams) error
 	DeleteUserTx(ctx context.Context, username string) error
 	UpdateAccountTx(ctx context.Context, arg UpdateAccountTxParams) (UpdateAccountTxResult, error)
+	CreateHouseholdTx(ctx context.Context, arg CreateHouseholdTxParams) (CreateHouseholdTxResult, error)
+	AcceptHouseholdInvitationTx(ctx context.Context, arg AcceptHouseholdInvitationTxParams) (AcceptHouseholdInvitationTxResult, error)
+	CreateHouseholdInvitationTx(ctx context.Context, arg CreateHouseholdInvitationParams) (HouseholdInvitation, error)
+	UpdateHouseholdMemberRoleTx(ctx context.Context, arg UpdateHouseholdMemberRoleTxParams) (HouseholdMember, error)
+	DeleteHouseholdMemberTx(ctx context.Context, arg DeleteHouseholdMemberTxParams) error
+	CreateHouseholdEntryTx(ctx context.Context, arg CreateHouseholdEntryParams) (Entry, error)
+	UpdateHouseholdEntryTx(ctx context.Context, arg UpdateHouseholdEntryTxParams) (Entry, error)
+	DeleteHouseholdEntryTx(ctx context.Context, arg DeleteHouseholdEntryTxParams) error
+	RestoreHouseholdEntryTx(ctx context.Context, arg RestoreHouseholdEntryTxParams) (Entry, error)
+	SplitExpenseTx(ctx context.Context, arg SplitExpenseTxParams) (SplitExpenseTxResult, error)
+	SettleUpTx(ctx context.Context, arg SettleUpTxParams) (SettleUpTxResult, error)
+	BatchEntriesTx(ctx context.Context, arg BatchEntriesTxParams) (BatchEntriesTxResult, error)
+	RestoreEntryTx(ctx context.Context, arg RestoreEntryTxParams) (RestoreEntryTxResult, error)
+	RestoreUserTx(ctx context.Context, username string) (User, error)
+	PurgeTrashTx(ctx context.Context, deletedBefore time.Time) (PurgeTrashTxResult, error)
+	ReconcileTotalExpensesTx(ctx context.Context, username string) (ReconcileTotalExpensesTxResult, error)
+	TakeRateLimitTokenTx(ctx context.Context, arg TakeRateLimitTokenTxParams) (util.RateLimitResult, error)
+	CreateWebhookTx(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	DeleteWebhookTx(ctx context.Context, arg DeleteWebhookParams) error
+	Ping(ctx context.Context) error
+	GetMigrationVersion(ctx context.Context) (MigrationVersion, error)
+}
+
+// txQuerier runs the queries of a transaction, part of which can be rolled
+// back to a savepoint
+type txQuerier interface {
+	Querier
+	savepoint(ctx context.Context, name string) error
+	rollbackToSavepoint(ctx context.Context, name string) error
+	releaseSavepoint(ctx context.Context, name string) error
+}
+
+// txStore implements the transactions of Store with the queries alone, so
+// every store shares them. execTx runs fn within a transaction called name
+// and rolls it back if fn fails.
+type txStore struct {
+	execTx func(ctx context.Context, name string, fn func(q txQuerier) error) error
 }
 
 // SQLStore provides all functions to execute SQL queries and transactions
 type SQLStore struct {
 	*Queries
-	db *sql.DB
+	txStore
+	db     *sql.DB
+	logger zerolog.Logger
+	sqlite bool
+}
+
+// Creates a new store on a Postgres database
+func NewStore(db *sql.DB, logger zerolog.Logger) Store {
+	store := &SQLStore{
+		db:     db,
+		logger: logger,
+	}
+	store.Queries = New(store.conn(db, nil))
+	store.txStore = txStore{execTx: store.runTx}
+	return store
+}
+
+// Creates a new store on a SQLite database opened by Open
+func NewSQLiteStore(db *sql.DB, logger zerolog.Logger) Store {
+	store := &SQLStore{
+		db:     db,
+		logger: logger,
+		sqlite: true,
+	}
+	store.Queries = New(store.conn(db, nil))
+	store.txStore = txStore{execTx: store.runTx}
+	return store
+}
+
+// Returns the DBTX the queries run on, traced and, on SQLite, translated.
+// Within a transaction tx is its span.
+func (store *SQLStore) conn(db DBTX, tx trace.Span) DBTX {
+	if store.sqlite {
+		return newSQLiteDB(newTracedDB(db, tx, semconv.DBSystemSqlite))
+	}
+	return newTracedDB(db, tx, semconv.DBSystemPostgreSQL)
 }
 
-// Creates a new store
-func NewStore(db *sql.DB) Store {
-	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+// Returns the logger of the request running ctx, so its lines carry the
+// request ID, or the store's logger outside of requests
+func (store *SQLStore) log(ctx context.Context) *zerolog.Logger {
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		return logger
 	}
+	return &store.logger
 }
 
-// executes a function within a db transaction
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+// executes a function within a db transaction traced as a span called name,
+// the queries of fn get child spans of it
+func (store *SQLStore) runTx(ctx context.Context, name string, fn func(q txQuerier) error) error {
+	ctx, span := tracer.Start(ctx, "db."+name)
+	defer span.End()
+
 	tx, err := store.db.BeginTx(ctx, nil)
 	if err != nil {
+		endSpan(span, err)
 		return err
 	}
 
-	q := New(tx)
+	start := time.Now()
+	q := New(store.conn(tx, span))
 	err = fn(q)
-	fmt.Print(err)
 	if err != nil {
+		metrics.TxRollbacks.Inc()
+		metrics.TxDuration.WithLabelValues(metrics.TxRolledBack).Observe(time.Since(start).Seconds())
+		span.SetAttributes(attribute.Bool("db.rolled_back", true))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		if rbErr := tx.Rollback(); rbErr != nil {
+			store.log(ctx).Error().Err(rbErr).Msg("cannot roll back transaction")
 			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
 		}
+		store.log(ctx).Debug().Err(err).Msg("rolled back transaction")
+		return err
+	}
+
+	err = tx.Commit()
+	outcome := metrics.TxCommitted
+	if err != nil {
+		metrics.TxRollbacks.Inc()
+		outcome = metrics.TxRolledBack
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
+	metrics.TxDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (q *Queries) savepoint(ctx context.Context, name string) error {
+	_, err := q.db.ExecContext(ctx, "SAVEPOINT "+name)
+	return err
+}
+
+func (q *Queries) rollbackToSavepoint(ctx context.Context, name string) error {
+	_, err := q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+	return err
+}
 
-	return tx.Commit()
+func (q *Queries) releaseSavepoint(ctx context.Context, name string) error {
+	_, err := q.db.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
 }
 
 // Contains the input parameter of the add entry transaction
@@ -68,44 +212,72 @@ type AddEntryTxResult struct {
 }
 
 // Adds an entry and updates the total expense in the user
-func (store *SQLStore) AddEntryTx(ctx context.Context, arg AddEntryTxParams) (AddEntryTxResult, error) {
+func (store *txStore) AddEntryTx(ctx context.Context, arg AddEntryTxParams) (AddEntryTxResult, error) {
 	var result AddEntryTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
-		user, err := q.GetUserForUpdate(ctx, arg.Username)
-		if err != nil {
-			return err
-		}
+	err := store.execTx(ctx, "AddEntryTx", func(q txQuerier) error {
+		var err error
+		result, err = addEntry(ctx, q, arg)
+		return err
+	})
+	if err == nil {
+		metrics.EntriesCreated.Inc()
+	}
 
-		createEntryParams := CreateEntryParams{
-			Owner:    arg.Username,
-			Name:     arg.Name,
-			DueDate:  arg.DueDate,
-			Amount:   arg.Amount,
-			Category: sql.NullString{String: arg.Category, Valid: true},
-		}
-		result.Entry, err = q.CreateEntry(ctx, createEntryParams)
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		totalExpenses := user.TotalExpenses
-		entryAmount := result.Entry.Amount
-		totalExpenses = totalExpenses + entryAmount
+// Adds an entry using the queries of the running transaction
+func addEntry(ctx context.Context, q Querier, arg AddEntryTxParams) (AddEntryTxResult, error) {
+	var result AddEntryTxResult
 
-		updatedUserParams := UpdateUserParams{
-			Username:      arg.Username,
-			TotalExpenses: totalExpenses,
-		}
-		result.User, err = q.UpdateUser(ctx, updatedUserParams)
-		if err != nil {
-			return err
-		}
+	user, err := q.GetUserForUpdate(ctx, arg.Username)
+	if err != nil {
+		return result, err
+	}
 
-		return nil
-	})
+	createEntryParams := CreateEntryParams{
+		Owner:     arg.Username,
+		Name:      arg.Name,
+		DueDate:   arg.DueDate,
+		Amount:    arg.Amount,
+		Category:  sql.NullString{String: arg.Category, Valid: true},
+		CreatedBy: sql.NullString{String: arg.Username, Valid: true},
+	}
+	result.Entry, err = q.CreateEntry(ctx, createEntryParams)
+	if err != nil {
+		return result, err
+	}
 
-	return result, err
+	totalExpenses := user.TotalExpenses
+	entryAmount := result.Entry.Amount
+	totalExpenses = totalExpenses + entryAmount
+
+	updatedUserParams := UpdateUserParams{
+		Username:      arg.Username,
+		TotalExpenses: totalExpenses,
+	}
+	result.User, err = q.UpdateUser(ctx, updatedUserParams)
+	if err != nil {
+		return result, err
+	}
+
+	err = recordAuditEvent(ctx, q, arg.Username, AuditActionCreate, AuditEntityEntry, result.Entry.ID, nil, result.Entry)
+	if err != nil {
+		return result, err
+	}
+
+	err = queueEntryCreated(ctx, q, result.Entry)
+	if err != nil {
+		return result, err
+	}
+
+	err = queueBudgetExceeded(ctx, q, arg.Username, result.Entry.DueDate)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
 }
 
 // Contains the input parameter of the update entry transaction
@@ -116,6 +288,7 @@ type UpdateEntryTxParams struct {
 	DueDate  time.Time `json:"due_date"`
 	Amount   int64     `json:"amount"`
 	Category string    `json:"category"`
+	Version  int32     `json:"version"`
 }
 
 // Contains the result of the update entry transaction
@@ -125,76 +298,108 @@ type UpdateEntryTxResult struct {
 }
 
 // Updates the amount of an entry and updates the total expense in the user
-func (store *SQLStore) UpdateEntryTx(ctx context.Context, arg UpdateEntryTxParams) (UpdateEntryTxResult, error) {
+func (store *txStore) UpdateEntryTx(ctx context.Context, arg UpdateEntryTxParams) (UpdateEntryTxResult, error) {
 	var result UpdateEntryTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
-		user, err := q.GetUserForUpdate(ctx, arg.Username)
-		if err != nil {
-			return err
-		}
+	err := store.execTx(ctx, "UpdateEntryTx", func(q txQuerier) error {
+		var err error
+		result, err = editEntry(ctx, q, arg)
+		return err
+	})
 
-		getEntryParams := GetEntryForUpdateParams{
-			Owner: arg.Username,
-			ID:    arg.ID,
-		}
-		entry, err := q.GetEntryForUpdate(ctx, getEntryParams)
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		totalExpenses := user.TotalExpenses
-		entryAmount := entry.Amount
+// Updates an entry using the queries of the running transaction
+func editEntry(ctx context.Context, q Querier, arg UpdateEntryTxParams) (UpdateEntryTxResult, error) {
+	var result UpdateEntryTxResult
 
-		changeInAmount := arg.Amount - entryAmount
-		totalExpenses = totalExpenses + changeInAmount
+	user, err := q.GetUserForUpdate(ctx, arg.Username)
+	if err != nil {
+		return result, err
+	}
 
-		updatedUserParams := UpdateUserParams{
-			Username:      arg.Username,
-			TotalExpenses: totalExpenses,
+	getEntryParams := GetEntryForUpdateParams{
+		Owner: arg.Username,
+		ID:    arg.ID,
+	}
+	entry, err := q.GetEntryForUpdate(ctx, getEntryParams)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkVersion(entry, arg.Version)
+	if err != nil {
+		return result, err
+	}
+
+	totalExpenses := user.TotalExpenses
+	entryAmount := entry.Amount
+
+	changeInAmount := arg.Amount - entryAmount
+	totalExpenses = totalExpenses + changeInAmount
+
+	updatedUserParams := UpdateUserParams{
+		Username:      arg.Username,
+		TotalExpenses: totalExpenses,
+	}
+	result.User, err = q.UpdateUser(ctx, updatedUserParams)
+	if err != nil {
+		return result, err
+	}
+
+	var categoryValue sql.NullString
+	if arg.Category == "" {
+		categoryValue = sql.NullString{
+			String: "",
+			Valid:  false,
 		}
-		result.User, err = q.UpdateUser(ctx, updatedUserParams)
-		if err != nil {
-			return err
+	} else {
+		categoryValue = sql.NullString{
+			String: arg.Category,
+			Valid:  true,
 		}
+	}
 
-		var categoryValue sql.NullString
-		if arg.Category == "" {
-			categoryValue = sql.NullString{
-				String: "",
-				Valid:  false,
-			}
-		} else {
-			categoryValue = sql.NullString{
-				String: arg.Category,
-				Valid:  true,
-			}
-		}
+	updateEntryParams := UpdateEntryParams{
+		Owner:    arg.Username,
+		ID:       arg.ID,
+		Name:     arg.Name,
+		DueDate:  arg.DueDate,
+		Amount:   arg.Amount,
+		Category: categoryValue,
+	}
 
-		updateEntryParams := UpdateEntryParams{
-			Owner:    arg.Username,
-			ID:       arg.ID,
-			Name:     arg.Name,
-			DueDate:  arg.DueDate,
-			Amount:   arg.Amount,
-			Category: categoryValue,
-		}
+	result.Entry, err = q.UpdateEntry(ctx, updateEntryParams)
+	if err != nil {
+		return result, err
+	}
 
-		result.Entry, err = q.UpdateEntry(ctx, updateEntryParams)
+	if changeInAmount != 0 {
+		err = resplitEntry(ctx, q, arg.Username, entry.ID, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		return nil
-	})
+	err = recordAuditEvent(ctx, q, arg.Username, AuditActionUpdate, AuditEntityEntry, entry.ID, entry, result.Entry)
+	if err != nil {
+		return result, err
+	}
 
-	return result, err
+	err = queueBudgetExceeded(ctx, q, arg.Username, result.Entry.DueDate)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
 }
 
 // Contains the input parameter of the delete entry transaction
 type DeleteEntryTxParams struct {
 	Username string `json:"username"`
 	ID       int32  `json:"id"`
+	Version  int32  `json:"version"`
 }
 
 // Contains the result of the delete entry transaction
@@ -202,39 +407,259 @@ type DeleteEntryTxResult struct {
 	User User `json:"user"`
 }
 
-// Updates the amount of an entry and updates the total expense in the user
-func (store *SQLStore) DeleteEntryTx(ctx context.Context, arg DeleteEntryTxParams) (DeleteEntryTxResult, error) {
+// Moves an entry to the trash and updates the total expense in the user
+func (store *txStore) DeleteEntryTx(ctx context.Context, arg DeleteEntryTxParams) (DeleteEntryTxResult, error) {
 	var result DeleteEntryTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
-		user, err := q.GetUserForUpdate(ctx, arg.Username)
+	err := store.execTx(ctx, "DeleteEntryTx", func(q txQuerier) error {
+		var err error
+		result, err = trashEntry(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// Moves an entry to the trash using the queries of the running transaction
+func trashEntry(ctx context.Context, q Querier, arg DeleteEntryTxParams) (DeleteEntryTxResult, error) {
+	var result DeleteEntryTxResult
+
+	user, err := q.GetUserForUpdate(ctx, arg.Username)
+	if err != nil {
+		return result, err
+	}
+
+	getEntryParams := GetEntryForUpdateParams{
+		Owner: arg.Username,
+		ID:    arg.ID,
+	}
+	entry, err := q.GetEntryForUpdate(ctx, getEntryParams)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkVersion(entry, arg.Version)
+	if err != nil {
+		return result, err
+	}
+
+	totalExpenses := user.TotalExpenses
+	entryAmount := entry.Amount
+	totalExpenses = totalExpenses - entryAmount
+
+	err = reverseSplit(ctx, q, entry.ID)
+	if err != nil {
+		return result, err
+	}
+
+	err = q.DeleteEntry(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+
+	updatedUserParams := UpdateUserParams{
+		Username:      arg.Username,
+		TotalExpenses: totalExpenses,
+	}
+	result.User, err = q.UpdateUser(ctx, updatedUserParams)
+	if err != nil {
+		return result, err
+	}
+
+	err = recordAuditEvent(ctx, q, arg.Username, AuditActionDelete, AuditEntityEntry, entry.ID, entry, nil)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// Kinds of operation accepted by the batch entries transaction
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+)
+
+// Contains one operation of the batch entries transaction.
+// ID and Version are ignored when creating an entry and are the only fields used when deleting one.
+type EntryOperation struct {
+	Op       string    `json:"op"`
+	ID       int32     `json:"id"`
+	Name     string    `json:"name"`
+	DueDate  time.Time `json:"due_date"`
+	Amount   int64     `json:"amount"`
+	Category string    `json:"category"`
+	Version  int32     `json:"version"`
+}
+
+// Contains the outcome of one operation of the batch entries transaction
+type EntryOperationResult struct {
+	Op    string `json:"op"`
+	Entry *Entry `json:"entry,omitempty"`
+	Err   error  `json:"-"`
+}
+
+// Contains the input parameter of the batch entries transaction
+type BatchEntriesTxParams struct {
+	Username   string           `json:"username"`
+	Operations []EntryOperation `json:"operations"`
+	BestEffort bool             `json:"best_effort"`
+}
+
+// Contains the result of the batch entries transaction
+type BatchEntriesTxResult struct {
+	Results []EntryOperationResult `json:"results"`
+	User    User                   `json:"user"`
+}
+
+// BatchOperationError is returned when an operation fails a batch that is not best effort
+type BatchOperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchOperationError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchOperationError) Unwrap() error {
+	return e.Err
+}
+
+// Runs several entry operations in one transaction. Unless the batch is best effort
+// the first failing operation rolls back all of them, otherwise each operation runs in
+// its own savepoint and failures are reported in its result.
+func (store *txStore) BatchEntriesTx(ctx context.Context, arg BatchEntriesTxParams) (BatchEntriesTxResult, error) {
+	var result BatchEntriesTxResult
+
+	err := store.execTx(ctx, "BatchEntriesTx", func(q txQuerier) error {
+		var err error
+		result.Results = make([]EntryOperationResult, len(arg.Operations))
+
+		for i, op := range arg.Operations {
+			if arg.BestEffort {
+				err = q.savepoint(ctx, "batch_operation")
+				if err != nil {
+					return err
+				}
+			}
+
+			result.Results[i], err = runEntryOperation(ctx, q, arg.Username, op)
+			if err != nil && !arg.BestEffort {
+				return &BatchOperationError{Index: i, Err: err}
+			}
+
+			if arg.BestEffort {
+				if err != nil {
+					result.Results[i] = EntryOperationResult{Op: op.Op, Err: err}
+					err = q.rollbackToSavepoint(ctx, "batch_operation")
+				} else {
+					err = q.releaseSavepoint(ctx, "batch_operation")
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		result.User, err = q.GetUser(ctx, arg.Username)
+		return err
+	})
+	if err == nil {
+		for _, r := range result.Results {
+			if r.Op == BatchCreate && r.Err == nil {
+				metrics.EntriesCreated.Inc()
+			}
+		}
+	}
+
+	return result, err
+}
+
+func runEntryOperation(ctx context.Context, q Querier, username string, op EntryOperation) (EntryOperationResult, error) {
+	result := EntryOperationResult{Op: op.Op}
+
+	switch op.Op {
+	case BatchCreate:
+		added, err := addEntry(ctx, q, AddEntryTxParams{
+			Username: username,
+			Name:     op.Name,
+			DueDate:  op.DueDate,
+			Amount:   op.Amount,
+			Category: op.Category,
+		})
 		if err != nil {
-			return err
+			return result, err
+		}
+		result.Entry = &added.Entry
+	case BatchUpdate:
+		updated, err := editEntry(ctx, q, UpdateEntryTxParams{
+			Username: username,
+			ID:       op.ID,
+			Name:     op.Name,
+			DueDate:  op.DueDate,
+			Amount:   op.Amount,
+			Category: op.Category,
+			Version:  op.Version,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Entry = &updated.Entry
+	case BatchDelete:
+		_, err := trashEntry(ctx, q, DeleteEntryTxParams{
+			Username: username,
+			ID:       op.ID,
+			Version:  op.Version,
+		})
+		if err != nil {
+			return result, err
 		}
+	default:
+		return result, fmt.Errorf("unsupported operation %s", op.Op)
+	}
 
-		getEntryParams := GetEntryParams{
-			Owner: arg.Username,
-			ID:    arg.ID,
+	return result, nil
+}
+
+// Creates a user and records the signup in the audit log
+func (store *txStore) CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, "CreateUserTx", func(q txQuerier) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
 		}
-		entry, err := q.GetEntry(ctx, getEntryParams)
+
+		err = recordAuditEvent(ctx, q, user.Username, AuditActionCreate, AuditEntityUser, user.Username, nil, newAuditedUser(user))
 		if err != nil {
 			return err
 		}
 
-		totalExpenses := user.TotalExpenses
-		entryAmount := entry.Amount
-		totalExpenses = totalExpenses - entryAmount
+		return nil
+	})
+
+	return user, err
+}
 
-		err = q.DeleteEntry(ctx, arg.ID)
+// Replaces the password of a user and records the change, but not the hash,
+// in the audit log. Unknown and deleted users are not found.
+func (store *txStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordParams) error {
+	err := store.execTx(ctx, "ResetPasswordTx", func(q txQuerier) error {
+		_, err := q.GetUserForUpdate(ctx, arg.Username)
 		if err != nil {
 			return err
 		}
 
-		updatedUserParams := UpdateUserParams{
-			Username:      arg.Username,
-			TotalExpenses: totalExpenses,
+		err = q.ResetPassword(ctx, arg)
+		if err != nil {
+			return err
 		}
-		result.User, err = q.UpdateUser(ctx, updatedUserParams)
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionResetPassword, AuditEntityUser, arg.Username, nil, nil)
 		if err != nil {
 			return err
 		}
@@ -242,13 +667,13 @@ func (store *SQLStore) DeleteEntryTx(ctx context.Context, arg DeleteEntryTxParam
 		return nil
 	})
 
-	return result, err
+	return err
 }
 
-// Updates the amount of an user and updates the total expense in the user
-func (store *SQLStore) DeleteUserTx(ctx context.Context, username string) error {
-	err := store.execTx(ctx, func(q *Queries) error {
-		err := q.DeleteEntries(ctx, username)
+// Moves the user to the trash, the entries are kept until the account is purged
+func (store *txStore) DeleteUserTx(ctx context.Context, username string) error {
+	err := store.execTx(ctx, "DeleteUserTx", func(q txQuerier) error {
+		user, err := q.GetUserForUpdate(ctx, username)
 		if err != nil {
 			return err
 		}
@@ -258,6 +683,11 @@ func (store *SQLStore) DeleteUserTx(ctx context.Context, username string) error
 			return err
 		}
 
+		err = recordAuditEvent(ctx, q, username, AuditActionDelete, AuditEntityUser, username, newAuditedUser(user), nil)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -278,11 +708,14 @@ type UpdateAccountTxResult struct {
 }
 
 // Updates the amount of an entry and updates the total expense in the user
-func (store *SQLStore) UpdateAccountTx(ctx context.Context, arg UpdateAccountTxParams) (UpdateAccountTxResult, error) {
+func (store *txStore) UpdateAccountTx(ctx context.Context, arg UpdateAccountTxParams) (UpdateAccountTxResult, error) {
 	var result UpdateAccountTxResult
 
-	err := store.execTx(ctx, func(q *Queries) error {
-		var err error
+	err := store.execTx(ctx, "UpdateAccountTx", func(q txQuerier) error {
+		user, err := q.GetUserForUpdate(ctx, arg.OrigUsername)
+		if err != nil {
+			return err
+		}
 
 		updateEntryParams := UpdateEntriesOwnerParams{
 			Owner:   arg.OrigUsername,
@@ -304,8 +737,956 @@ func (store *SQLStore) UpdateAccountTx(ctx context.Context, arg UpdateAccountTxP
 			return err
 		}
 
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionUpdate, AuditEntityUser, arg.OrigUsername, newAuditedUser(user), newAuditedUser(result.User))
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
 	return result, err
 }
+
+// Contains the input parameter of the create household transaction
+type CreateHouseholdTxParams struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// Contains the result of the create household transaction
+type CreateHouseholdTxResult struct {
+	Household Household       `json:"household"`
+	Member    HouseholdMember `json:"member"`
+}
+
+// Creates a household and makes the creating user its owner
+func (store *txStore) CreateHouseholdTx(ctx context.Context, arg CreateHouseholdTxParams) (CreateHouseholdTxResult, error) {
+	var result CreateHouseholdTxResult
+
+	err := store.execTx(ctx, "CreateHouseholdTx", func(q txQuerier) error {
+		var err error
+
+		result.Household, err = q.CreateHousehold(ctx, arg.Name)
+		if err != nil {
+			return err
+		}
+
+		addMemberParams := AddHouseholdMemberParams{
+			HouseholdID: result.Household.ID,
+			Username:    arg.Username,
+			Role:        util.OwnerRole,
+		}
+		result.Member, err = q.AddHouseholdMember(ctx, addMemberParams)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionCreate, AuditEntityHousehold, result.Household.ID, nil, result.Household)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Contains the input parameter of the accept household invitation transaction
+type AcceptHouseholdInvitationTxParams struct {
+	InvitationID int32  `json:"invitation_id"`
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+}
+
+// Contains the result of the accept household invitation transaction
+type AcceptHouseholdInvitationTxResult struct {
+	Invitation HouseholdInvitation `json:"invitation"`
+	Member     HouseholdMember     `json:"member"`
+}
+
+// Accepts a pending invitation sent to the user's email and adds the user to the household
+func (store *txStore) AcceptHouseholdInvitationTx(ctx context.Context, arg AcceptHouseholdInvitationTxParams) (AcceptHouseholdInvitationTxResult, error) {
+	var result AcceptHouseholdInvitationTxResult
+
+	err := store.execTx(ctx, "AcceptHouseholdInvitationTx", func(q txQuerier) error {
+		invitation, err := q.GetHouseholdInvitationForUpdate(ctx, arg.InvitationID)
+		if err != nil {
+			return err
+		}
+
+		if invitation.Accepted || invitation.Email != arg.Email {
+			return ErrInvitationNotValid
+		}
+
+		result.Invitation, err = q.AcceptHouseholdInvitation(ctx, invitation.ID)
+		if err != nil {
+			return err
+		}
+
+		addMemberParams := AddHouseholdMemberParams{
+			HouseholdID: invitation.HouseholdID,
+			Username:    arg.Username,
+			Role:        invitation.Role,
+		}
+		result.Member, err = q.AddHouseholdMember(ctx, addMemberParams)
+		if err != nil {
+			return err
+		}
+
+		entityID := fmt.Sprintf("%d/%s", result.Member.HouseholdID, result.Member.Username)
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionCreate, AuditEntityHouseholdMember, entityID, nil, result.Member)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Invites an email to a household and records the invitation in the audit log
+// as the inviter's
+func (store *txStore) CreateHouseholdInvitationTx(ctx context.Context, arg CreateHouseholdInvitationParams) (HouseholdInvitation, error) {
+	var invitation HouseholdInvitation
+
+	err := store.execTx(ctx, "CreateHouseholdInvitationTx", func(q txQuerier) error {
+		var err error
+		invitation, err = q.CreateHouseholdInvitation(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.InvitedBy, AuditActionCreate, AuditEntityInvitation, invitation.ID, nil, invitation)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return invitation, err
+}
+
+// Contains the input parameter of the update household member role transaction
+type UpdateHouseholdMemberRoleTxParams struct {
+	// Username is the member making the change
+	Username    string `json:"username"`
+	HouseholdID int32  `json:"household_id"`
+	Member      string `json:"member"`
+	Role        string `json:"role"`
+}
+
+// Changes the role of a household member and records the change in the audit log
+func (store *txStore) UpdateHouseholdMemberRoleTx(ctx context.Context, arg UpdateHouseholdMemberRoleTxParams) (HouseholdMember, error) {
+	var member HouseholdMember
+
+	err := store.execTx(ctx, "UpdateHouseholdMemberRoleTx", func(q txQuerier) error {
+		before, err := q.GetHouseholdMemberForUpdate(ctx, GetHouseholdMemberForUpdateParams{
+			HouseholdID: arg.HouseholdID,
+			Username:    arg.Member,
+		})
+		if err != nil {
+			return err
+		}
+
+		member, err = q.UpdateHouseholdMemberRole(ctx, UpdateHouseholdMemberRoleParams{
+			HouseholdID: arg.HouseholdID,
+			Username:    arg.Member,
+			Role:        arg.Role,
+		})
+		if err != nil {
+			return err
+		}
+
+		entityID := fmt.Sprintf("%d/%s", member.HouseholdID, member.Username)
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionUpdate, AuditEntityHouseholdMember, entityID, before, member)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return member, err
+}
+
+// Contains the input parameter of the delete household member transaction
+type DeleteHouseholdMemberTxParams struct {
+	// Username is the member making the change
+	Username    string `json:"username"`
+	HouseholdID int32  `json:"household_id"`
+	Member      string `json:"member"`
+}
+
+// Removes a member from a household and records the removal in the audit log
+func (store *txStore) DeleteHouseholdMemberTx(ctx context.Context, arg DeleteHouseholdMemberTxParams) error {
+	err := store.execTx(ctx, "DeleteHouseholdMemberTx", func(q txQuerier) error {
+		member, err := q.GetHouseholdMemberForUpdate(ctx, GetHouseholdMemberForUpdateParams{
+			HouseholdID: arg.HouseholdID,
+			Username:    arg.Member,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteHouseholdMember(ctx, DeleteHouseholdMemberParams{
+			HouseholdID: arg.HouseholdID,
+			Username:    arg.Member,
+		})
+		if err != nil {
+			return err
+		}
+
+		entityID := fmt.Sprintf("%d/%s", member.HouseholdID, member.Username)
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionDelete, AuditEntityHouseholdMember, entityID, member, nil)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// Adds an entry to a household and records it in the audit log as its owner's
+func (store *txStore) CreateHouseholdEntryTx(ctx context.Context, arg CreateHouseholdEntryParams) (Entry, error) {
+	var entry Entry
+
+	err := store.execTx(ctx, "CreateHouseholdEntryTx", func(q txQuerier) error {
+		var err error
+		entry, err = q.CreateHouseholdEntry(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Owner, AuditActionCreate, AuditEntityEntry, entry.ID, nil, entry)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return entry, err
+}
+
+// Contains the input parameter of the update household entry transaction
+type UpdateHouseholdEntryTxParams struct {
+	// Username is the member making the change
+	Username    string         `json:"username"`
+	HouseholdID int32          `json:"household_id"`
+	ID          int32          `json:"id"`
+	Name        string         `json:"name"`
+	DueDate     time.Time      `json:"due_date"`
+	Amount      int64          `json:"amount"`
+	Category    sql.NullString `json:"category"`
+	Version     int32          `json:"version"`
+}
+
+// Updates an entry of a household and records the change in the audit log
+func (store *txStore) UpdateHouseholdEntryTx(ctx context.Context, arg UpdateHouseholdEntryTxParams) (Entry, error) {
+	var entry Entry
+
+	err := store.execTx(ctx, "UpdateHouseholdEntryTx", func(q txQuerier) error {
+		householdID := sql.NullInt32{Int32: arg.HouseholdID, Valid: true}
+		before, err := q.GetHouseholdEntryForUpdate(ctx, GetHouseholdEntryForUpdateParams{
+			HouseholdID: householdID,
+			ID:          arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = checkVersion(before, arg.Version)
+		if err != nil {
+			return err
+		}
+
+		entry, err = q.UpdateHouseholdEntry(ctx, UpdateHouseholdEntryParams{
+			HouseholdID: householdID,
+			ID:          arg.ID,
+			Name:        arg.Name,
+			DueDate:     arg.DueDate,
+			Amount:      arg.Amount,
+			Category:    arg.Category,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionUpdate, AuditEntityEntry, entry.ID, before, entry)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return entry, err
+}
+
+// Contains the input parameter of the delete household entry transaction
+type DeleteHouseholdEntryTxParams struct {
+	// Username is the member making the change
+	Username    string `json:"username"`
+	HouseholdID int32  `json:"household_id"`
+	ID          int32  `json:"id"`
+	Version     int32  `json:"version"`
+}
+
+// Moves an entry of a household to the trash and records it in the audit log.
+// Entries that aren't in the household are not found.
+func (store *txStore) DeleteHouseholdEntryTx(ctx context.Context, arg DeleteHouseholdEntryTxParams) error {
+	err := store.execTx(ctx, "DeleteHouseholdEntryTx", func(q txQuerier) error {
+		householdID := sql.NullInt32{Int32: arg.HouseholdID, Valid: true}
+		entry, err := q.GetHouseholdEntryForUpdate(ctx, GetHouseholdEntryForUpdateParams{
+			HouseholdID: householdID,
+			ID:          arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = checkVersion(entry, arg.Version)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteHouseholdEntry(ctx, DeleteHouseholdEntryParams{
+			HouseholdID: householdID,
+			ID:          arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionDelete, AuditEntityEntry, entry.ID, entry, nil)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// Contains the input parameter of the restore household entry transaction
+type RestoreHouseholdEntryTxParams struct {
+	// Username is the member making the change
+	Username    string `json:"username"`
+	HouseholdID int32  `json:"household_id"`
+	ID          int32  `json:"id"`
+}
+
+// Takes an entry of a household out of the trash and records it in the audit log.
+// Entries that aren't in the household's trash are not found.
+func (store *txStore) RestoreHouseholdEntryTx(ctx context.Context, arg RestoreHouseholdEntryTxParams) (Entry, error) {
+	var restored Entry
+
+	err := store.execTx(ctx, "RestoreHouseholdEntryTx", func(q txQuerier) error {
+		entry, err := q.GetDeletedHouseholdEntryForUpdate(ctx, GetDeletedHouseholdEntryForUpdateParams{
+			HouseholdID: sql.NullInt32{Int32: arg.HouseholdID, Valid: true},
+			ID:          arg.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		restored, err = q.RestoreEntry(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionRestore, AuditEntityEntry, entry.ID, entry, restored)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return restored, err
+}
+
+// Contains one participant of a split expense
+type ExpenseShareParams struct {
+	Username string `json:"username"`
+	Value    int64  `json:"value"`
+}
+
+// Contains the input parameter of the split expense transaction
+type SplitExpenseTxParams struct {
+	Username  string               `json:"username"`
+	EntryID   int32                `json:"entry_id"`
+	SplitType string               `json:"split_type"`
+	Shares    []ExpenseShareParams `json:"shares"`
+}
+
+// Contains the result of the split expense transaction
+type SplitExpenseTxResult struct {
+	Expense SharedExpense  `json:"expense"`
+	Shares  []ExpenseShare `json:"shares"`
+}
+
+// Splits an entry paid by the user among several users and updates the balances between them
+func (store *txStore) SplitExpenseTx(ctx context.Context, arg SplitExpenseTxParams) (SplitExpenseTxResult, error) {
+	var result SplitExpenseTxResult
+
+	err := store.execTx(ctx, "SplitExpenseTx", func(q txQuerier) error {
+		getEntryParams := GetEntryParams{
+			Owner: arg.Username,
+			ID:    arg.EntryID,
+		}
+		entry, err := q.GetEntry(ctx, getEntryParams)
+		if err != nil {
+			return err
+		}
+
+		err = checkHouseholdMates(ctx, q, arg.Username, arg.Shares)
+		if err != nil {
+			return err
+		}
+
+		values := make([]int64, len(arg.Shares))
+		for i, share := range arg.Shares {
+			values[i] = share.Value
+		}
+		amounts, err := util.SplitAmount(entry.Amount, arg.SplitType, values)
+		if err != nil {
+			return err
+		}
+
+		createExpenseParams := CreateSharedExpenseParams{
+			EntryID:   entry.ID,
+			PaidBy:    arg.Username,
+			SplitType: arg.SplitType,
+		}
+		result.Expense, err = q.CreateSharedExpense(ctx, createExpenseParams)
+		if err != nil {
+			return err
+		}
+
+		result.Shares = make([]ExpenseShare, len(arg.Shares))
+		for i, share := range arg.Shares {
+			createShareParams := CreateExpenseShareParams{
+				ExpenseID: result.Expense.ID,
+				Username:  share.Username,
+				Amount:    amounts[i],
+			}
+			result.Shares[i], err = q.CreateExpenseShare(ctx, createShareParams)
+			if err != nil {
+				return err
+			}
+
+			if share.Username == arg.Username {
+				continue
+			}
+
+			_, err = recordDebt(ctx, q, arg.Username, share.Username, amounts[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionCreate, AuditEntitySharedExpense, result.Expense.ID, nil, result)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Makes sure everyone the expense is split with shares a household with the user
+func checkHouseholdMates(ctx context.Context, q Querier, username string, shares []ExpenseShareParams) error {
+	households, err := q.ListHouseholds(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int32, len(households))
+	for i, household := range households {
+		ids[i] = household.ID
+	}
+	members, err := q.ListMembersOfHouseholds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	mates := map[string]bool{username: true}
+	for _, member := range members {
+		mates[member.Username] = true
+	}
+
+	for _, share := range shares {
+		if !mates[share.Username] {
+			return ErrShareNotHouseholdMember
+		}
+	}
+
+	return nil
+}
+
+// Contains the input parameter of the settle up transaction
+type SettleUpTxParams struct {
+	FromUsername string `json:"from_username"`
+	ToUsername   string `json:"to_username"`
+	Amount       int64  `json:"amount"`
+}
+
+// Contains the result of the settle up transaction
+type SettleUpTxResult struct {
+	Settlement Settlement `json:"settlement"`
+	Balance    Balance    `json:"balance"`
+}
+
+// Records a repayment from one user to another and updates the balance between them.
+// Users only repay members of their households, and no more than they owe them.
+func (store *txStore) SettleUpTx(ctx context.Context, arg SettleUpTxParams) (SettleUpTxResult, error) {
+	var result SettleUpTxResult
+
+	err := store.execTx(ctx, "SettleUpTx", func(q txQuerier) error {
+		err := checkHouseholdMates(ctx, q, arg.FromUsername, []ExpenseShareParams{{Username: arg.ToUsername}})
+		if err != nil {
+			return err
+		}
+
+		owed, err := debtBetween(ctx, q, arg.ToUsername, arg.FromUsername)
+		if err != nil {
+			return err
+		}
+		if arg.Amount > owed {
+			return ErrSettlementExceedsDebt
+		}
+
+		createSettlementParams := CreateSettlementParams{
+			FromUsername: arg.FromUsername,
+			ToUsername:   arg.ToUsername,
+			Amount:       arg.Amount,
+		}
+		result.Settlement, err = q.CreateSettlement(ctx, createSettlementParams)
+		if err != nil {
+			return err
+		}
+
+		result.Balance, err = recordDebt(ctx, q, arg.FromUsername, arg.ToUsername, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.FromUsername, AuditActionCreate, AuditEntitySettlement, result.Settlement.ID, nil, result.Settlement)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Contains the input parameter of the restore entry transaction
+type RestoreEntryTxParams struct {
+	Username string `json:"username"`
+	ID       int32  `json:"id"`
+}
+
+// Contains the result of the restore entry transaction
+type RestoreEntryTxResult struct {
+	Entry Entry `json:"entry"`
+	User  User  `json:"user"`
+}
+
+// Takes an entry out of the trash and adds its amount back to the total expense in the user
+func (store *txStore) RestoreEntryTx(ctx context.Context, arg RestoreEntryTxParams) (RestoreEntryTxResult, error) {
+	var result RestoreEntryTxResult
+
+	err := store.execTx(ctx, "RestoreEntryTx", func(q txQuerier) error {
+		user, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		getEntryParams := GetDeletedEntryForUpdateParams{
+			Owner: arg.Username,
+			ID:    arg.ID,
+		}
+		entry, err := q.GetDeletedEntryForUpdate(ctx, getEntryParams)
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.RestoreEntry(ctx, entry.ID)
+		if err != nil {
+			return err
+		}
+
+		updatedUserParams := UpdateUserParams{
+			Username:      arg.Username,
+			TotalExpenses: user.TotalExpenses + result.Entry.Amount,
+		}
+		result.User, err = q.UpdateUser(ctx, updatedUserParams)
+		if err != nil {
+			return err
+		}
+
+		err = reapplySplit(ctx, q, entry.ID)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Username, AuditActionRestore, AuditEntityEntry, entry.ID, entry, result.Entry)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Takes a deleted account out of the trash
+func (store *txStore) RestoreUserTx(ctx context.Context, username string) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, "RestoreUserTx", func(q txQuerier) error {
+		var err error
+
+		user, err = q.RestoreUser(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, username, AuditActionRestore, AuditEntityUser, username, nil, newAuditedUser(user))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return user, err
+}
+
+// Contains the result of the purge trash transaction
+type PurgeTrashTxResult struct {
+	Entries int64 `json:"entries"`
+	Users   int64 `json:"users"`
+}
+
+// Permanently deletes the entries and accounts that were moved to the trash
+// before deletedBefore, recording each of them in the audit log as purged by the system
+func (store *txStore) PurgeTrashTx(ctx context.Context, deletedBefore time.Time) (PurgeTrashTxResult, error) {
+	var result PurgeTrashTxResult
+
+	err := store.execTx(ctx, "PurgeTrashTx", func(q txQuerier) error {
+		before := sql.NullTime{Time: deletedBefore, Valid: true}
+
+		// entries go first, they reference their owner
+		entries, err := q.PurgeEntries(ctx, before)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			err = recordAuditEvent(ctx, q, AuditActorSystem, AuditActionPurge, AuditEntityEntry, entry.ID, entry, nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		users, err := q.PurgeUsers(ctx, before)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			err = recordAuditEvent(ctx, q, AuditActorSystem, AuditActionPurge, AuditEntityUser, user.Username, newAuditedUser(user), nil)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Entries = int64(len(entries))
+		result.Users = int64(len(users))
+		return nil
+	})
+
+	return result, err
+}
+
+// Contains the result of the reconcile total expenses transaction
+type ReconcileTotalExpensesTxResult struct {
+	Username string `json:"username"`
+	// TotalExpenses is the total stored before the transaction
+	TotalExpenses int64 `json:"total_expenses"`
+	EntriesTotal  int64 `json:"entries_total"`
+	Corrected     bool  `json:"corrected"`
+}
+
+// Sets the total expenses of a user to the sum of their entries if they differ,
+// recording the correction in the audit log. The user row is locked before summing, like the entry transactions do, so an
+// entry being added concurrently is counted exactly once.
+func (store *txStore) ReconcileTotalExpensesTx(ctx context.Context, username string) (ReconcileTotalExpensesTxResult, error) {
+	result := ReconcileTotalExpensesTxResult{Username: username}
+
+	err := store.execTx(ctx, "ReconcileTotalExpensesTx", func(q txQuerier) error {
+		user, err := q.GetUserForUpdate(ctx, username)
+		if err != nil {
+			return err
+		}
+		result.TotalExpenses = user.TotalExpenses
+
+		result.EntriesTotal, err = q.GetEntriesTotal(ctx, username)
+		if err != nil {
+			return err
+		}
+		if result.EntriesTotal == result.TotalExpenses {
+			return nil
+		}
+
+		corrected, err := q.UpdateUser(ctx, UpdateUserParams{
+			Username:      username,
+			TotalExpenses: result.EntriesTotal,
+		})
+		if err != nil {
+			return err
+		}
+		result.Corrected = true
+
+		err = recordAuditEvent(ctx, q, AuditActorSystem, AuditActionUpdate, AuditEntityUser, username, newAuditedUser(user), newAuditedUser(corrected))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Contains the input parameter of the take rate limit token transaction
+type TakeRateLimitTokenTxParams struct {
+	Key   string         `json:"key"`
+	Limit util.RateLimit `json:"limit"`
+	Now   time.Time      `json:"now"`
+}
+
+// Takes a token from the bucket of key, creating it full if it doesn't exist.
+// The bucket row stays locked until the transaction ends, so replicas sharing
+// the database see the same count.
+func (store *txStore) TakeRateLimitTokenTx(ctx context.Context, arg TakeRateLimitTokenTxParams) (util.RateLimitResult, error) {
+	var result util.RateLimitResult
+
+	err := store.execTx(ctx, "TakeRateLimitTokenTx", func(q txQuerier) error {
+		bucket := arg.Limit.NewBucket(arg.Now)
+		err := q.CreateRateLimitBucket(ctx, CreateRateLimitBucketParams{
+			Key:       arg.Key,
+			Tokens:    bucket.Tokens,
+			UpdatedAt: bucket.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		row, err := q.GetRateLimitBucketForUpdate(ctx, arg.Key)
+		if err != nil {
+			return err
+		}
+
+		bucket, result = arg.Limit.Take(util.TokenBucket{Tokens: row.Tokens, UpdatedAt: row.UpdatedAt}, arg.Now)
+		return q.UpdateRateLimitBucket(ctx, UpdateRateLimitBucketParams{
+			Key:       arg.Key,
+			Tokens:    bucket.Tokens,
+			UpdatedAt: bucket.UpdatedAt,
+		})
+	})
+
+	return result, err
+}
+
+// Records that debtor owes amount more to creditor.
+// Balances are stored once per pair with user_a < user_b.
+func recordDebt(ctx context.Context, q Querier, creditor string, debtor string, amount int64) (Balance, error) {
+	arg := AddToBalanceParams{
+		UserA:  creditor,
+		UserB:  debtor,
+		Amount: amount,
+	}
+	if debtor < creditor {
+		arg = AddToBalanceParams{
+			UserA:  debtor,
+			UserB:  creditor,
+			Amount: -amount,
+		}
+	}
+
+	return q.AddToBalance(ctx, arg)
+}
+
+// Returns how much debtor owes creditor, locking the balance between them
+func debtBetween(ctx context.Context, q Querier, creditor string, debtor string) (int64, error) {
+	arg := GetBalanceForUpdateParams{UserA: creditor, UserB: debtor}
+	sign := int64(1)
+	if debtor < creditor {
+		arg = GetBalanceForUpdateParams{UserA: debtor, UserB: creditor}
+		sign = -1
+	}
+
+	balance, err := q.GetBalanceForUpdate(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return sign * balance.Amount, nil
+}
+
+// Takes back the balances created when the entry was split, if it was
+func reverseSplit(ctx context.Context, q Querier, entryID int32) error {
+	return adjustSplitBalances(ctx, q, entryID, -1)
+}
+
+// Adds back the balances of a split entry restored from the trash
+func reapplySplit(ctx context.Context, q Querier, entryID int32) error {
+	return adjustSplitBalances(ctx, q, entryID, 1)
+}
+
+// Adds each share of the entry's split, multiplied by sign, to the balances
+func adjustSplitBalances(ctx context.Context, q Querier, entryID int32, sign int64) error {
+	expense, err := q.GetSharedExpenseByEntry(ctx, entryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	shares, err := q.ListExpenseShares(ctx, expense.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, share := range shares {
+		if share.Username == expense.PaidBy {
+			continue
+		}
+
+		_, err = recordDebt(ctx, q, expense.PaidBy, share.Username, sign*share.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Divides the new amount of a split entry among its shares, if it was split,
+// and moves the balances by the difference in each share
+func resplitEntry(ctx context.Context, q Querier, username string, entryID int32, amount int64) error {
+	expense, err := q.GetSharedExpenseByEntry(ctx, entryID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	shares, err := q.ListExpenseShares(ctx, expense.ID)
+	if err != nil {
+		return err
+	}
+
+	amounts := make([]int64, len(shares))
+	for i, share := range shares {
+		amounts[i] = share.Amount
+	}
+	amounts, err = util.ResplitAmount(amount, expense.SplitType, amounts)
+	if err != nil {
+		return err
+	}
+
+	resplit := make([]ExpenseShare, len(shares))
+	for i, share := range shares {
+		updateShareParams := UpdateExpenseShareParams{
+			ExpenseID: expense.ID,
+			Username:  share.Username,
+			Amount:    amounts[i],
+		}
+		resplit[i], err = q.UpdateExpenseShare(ctx, updateShareParams)
+		if err != nil {
+			return err
+		}
+
+		if share.Username == expense.PaidBy {
+			continue
+		}
+
+		_, err = recordDebt(ctx, q, expense.PaidBy, share.Username, amounts[i]-share.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	before := SplitExpenseTxResult{Expense: expense, Shares: shares}
+	after := SplitExpenseTxResult{Expense: expense, Shares: resplit}
+	return recordAuditEvent(ctx, q, username, AuditActionUpdate, AuditEntitySharedExpense, expense.ID, before, after)
+}
+
+// Registers a webhook and records it in the audit log, without its secret
+func (store *txStore) CreateWebhookTx(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	var webhook Webhook
+
+	err := store.execTx(ctx, "CreateWebhookTx", func(q txQuerier) error {
+		var err error
+		webhook, err = q.CreateWebhook(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Owner, AuditActionCreate, AuditEntityWebhook, webhook.ID, nil, newAuditedWebhook(webhook))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return webhook, err
+}
+
+// Deletes a webhook of the owner and records it in the audit log. Webhooks of
+// other users are not found.
+func (store *txStore) DeleteWebhookTx(ctx context.Context, arg DeleteWebhookParams) error {
+	err := store.execTx(ctx, "DeleteWebhookTx", func(q txQuerier) error {
+		webhook, err := q.GetWebhook(ctx, GetWebhookParams(arg))
+		if err != nil {
+			return err
+		}
+
+		rows, err := q.DeleteWebhook(ctx, arg)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		err = recordAuditEvent(ctx, q, arg.Owner, AuditActionDelete, AuditEntityWebhook, webhook.ID, newAuditedWebhook(webhook), nil)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
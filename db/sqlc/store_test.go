@@ -3,14 +3,16 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/LeandroEstevez/budgetAppAPI/util"
 	"github.com/stretchr/testify/require"
 )
 
 func TestAddEntryTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 
@@ -45,7 +47,7 @@ func TestAddEntryTx(t *testing.T) {
 }
 
 func TestUpdateEntryTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 	entry := createRandomEntry(t, user)
@@ -79,7 +81,7 @@ func TestUpdateEntryTx(t *testing.T) {
 }
 
 func TestDeleteEntryTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 	entry := createRandomEntry(t, user)
@@ -100,7 +102,7 @@ func TestDeleteEntryTx(t *testing.T) {
 }
 
 func TestDeleteUserTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 	n := 5
@@ -118,6 +120,10 @@ func TestDeleteUserTx(t *testing.T) {
 	require.EqualError(t, err, sql.ErrNoRows.Error())
 	require.Empty(t, deletedUser)
 
+	trashedUser, err := testQueries.GetDeletedUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, trashedUser.DeletedAt.Valid)
+
 	for i := 0; i < n; i++ {
 		getEntryParams := GetEntryParams {
 			Owner: user.Username,
@@ -131,7 +137,7 @@ func TestDeleteUserTx(t *testing.T) {
 }
 
 func TestConcurrentAddEntryTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 
@@ -185,7 +191,7 @@ func TestConcurrentAddEntryTx(t *testing.T) {
 }
 
 func TestConcurrentUpdateEntryTx(t *testing.T) {
-	store := NewStore(testDB)
+	store := newTestStore()
 
 	user := createRandomUser(t)
 	entry := createRandomEntry(t, user)
@@ -204,6 +210,8 @@ func TestConcurrentUpdateEntryTx(t *testing.T) {
 			result, err := store.UpdateEntryTx(context.Background(), UpdateEntryTxParams {
 				Username: user.Username,
 				ID: entry.ID,
+				Name: entry.Name,
+				DueDate: entry.DueDate,
 				Amount: amount,
 			})
 
@@ -232,4 +240,439 @@ func TestConcurrentUpdateEntryTx(t *testing.T) {
 		require.NotEmpty(t, result.Entry)
 		require.Equal(t, amount, result.Entry.Amount)
 	}
-}
\ No newline at end of file
+}
+func TestCreateHouseholdTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+
+	result, err := store.CreateHouseholdTx(context.Background(), CreateHouseholdTxParams{
+		Name:     util.RandomString(8),
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+
+	require.NotZero(t, result.Household.ID)
+	require.Equal(t, result.Household.ID, result.Member.HouseholdID)
+	require.Equal(t, user.Username, result.Member.Username)
+	require.Equal(t, util.OwnerRole, result.Member.Role)
+}
+
+func TestAcceptHouseholdInvitationTx(t *testing.T) {
+	store := newTestStore()
+
+	owner := createRandomUser(t)
+	invitee := createRandomUser(t)
+
+	household, err := store.CreateHouseholdTx(context.Background(), CreateHouseholdTxParams{
+		Name:     util.RandomString(8),
+		Username: owner.Username,
+	})
+	require.NoError(t, err)
+
+	invitation, err := store.CreateHouseholdInvitation(context.Background(), CreateHouseholdInvitationParams{
+		HouseholdID: household.Household.ID,
+		Email:       invitee.Email,
+		Role:        util.EditorRole,
+		InvitedBy:   owner.Username,
+	})
+	require.NoError(t, err)
+
+	// an invitation can only be accepted by the user it was sent to
+	_, err = store.AcceptHouseholdInvitationTx(context.Background(), AcceptHouseholdInvitationTxParams{
+		InvitationID: invitation.ID,
+		Username:     owner.Username,
+		Email:        owner.Email,
+	})
+	require.ErrorIs(t, err, ErrInvitationNotValid)
+
+	result, err := store.AcceptHouseholdInvitationTx(context.Background(), AcceptHouseholdInvitationTxParams{
+		InvitationID: invitation.ID,
+		Username:     invitee.Username,
+		Email:        invitee.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, result.Invitation.Accepted)
+	require.Equal(t, invitee.Username, result.Member.Username)
+	require.Equal(t, util.EditorRole, result.Member.Role)
+
+	// accepting twice is rejected
+	_, err = store.AcceptHouseholdInvitationTx(context.Background(), AcceptHouseholdInvitationTxParams{
+		InvitationID: invitation.ID,
+		Username:     invitee.Username,
+		Email:        invitee.Email,
+	})
+	require.ErrorIs(t, err, ErrInvitationNotValid)
+}
+
+func TestSplitExpenseTx(t *testing.T) {
+	store := newTestStore()
+
+	payer := createRandomUser(t)
+	friend := createRandomUser(t)
+	entry := createRandomEntry(t, payer)
+	createHouseholdOf(t, payer.Username, friend.Username)
+
+	result, err := store.SplitExpenseTx(context.Background(), SplitExpenseTxParams{
+		Username:  payer.Username,
+		EntryID:   entry.ID,
+		SplitType: util.EqualSplit,
+		Shares: []ExpenseShareParams{
+			{Username: payer.Username},
+			{Username: friend.Username},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry.ID, result.Expense.EntryID)
+	require.Len(t, result.Shares, 2)
+	require.Equal(t, entry.Amount, result.Shares[0].Amount+result.Shares[1].Amount)
+
+	balances, err := store.ListBalances(context.Background(), friend.Username)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+
+	owed := balances[0].Amount
+	if balances[0].UserA == friend.Username {
+		owed = -owed
+	}
+	require.Equal(t, result.Shares[1].Amount, owed)
+
+	// deleting the entry takes the debt back
+	_, err = store.DeleteEntryTx(context.Background(), DeleteEntryTxParams{
+		Username: payer.Username,
+		ID:       entry.ID,
+	})
+	require.NoError(t, err)
+
+	balances, err = store.ListBalances(context.Background(), friend.Username)
+	require.NoError(t, err)
+	require.Empty(t, balances)
+}
+
+func TestSettleUpTx(t *testing.T) {
+	store := newTestStore()
+
+	payer := createRandomUser(t)
+	friend := createRandomUser(t)
+	createHouseholdOf(t, payer.Username, friend.Username)
+
+	date, err := GetMadeUpDate("2022-12-11")
+	require.NoError(t, err)
+
+	added, err := store.AddEntryTx(context.Background(), AddEntryTxParams{
+		Username: payer.Username,
+		Name:     util.RandomString(6),
+		DueDate:  date,
+		Amount:   100,
+	})
+	require.NoError(t, err)
+	entry := added.Entry
+
+	_, err = store.SplitExpenseTx(context.Background(), SplitExpenseTxParams{
+		Username:  payer.Username,
+		EntryID:   entry.ID,
+		SplitType: util.ExactSplit,
+		Shares: []ExpenseShareParams{
+			{Username: friend.Username, Value: entry.Amount},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := store.SettleUpTx(context.Background(), SettleUpTxParams{
+		FromUsername: friend.Username,
+		ToUsername:   payer.Username,
+		Amount:       entry.Amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry.Amount, result.Settlement.Amount)
+	require.Zero(t, result.Balance.Amount)
+}
+
+func TestAuditEventsTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+
+	metadata := AuditMetadata{
+		ClientIP:  "10.0.0.1",
+		RequestID: util.RandomString(12),
+	}
+	ctx := WithAuditMetadata(context.Background(), metadata)
+
+	_, err := store.UpdateEntryTx(ctx, UpdateEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+		Name:     entry.Name,
+		DueDate:  entry.DueDate,
+		Amount:   entry.Amount + 1,
+	})
+	require.NoError(t, err)
+
+	_, err = store.DeleteEntryTx(ctx, DeleteEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+	})
+	require.NoError(t, err)
+
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Actor:  user.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	// newest first
+	require.Equal(t, AuditActionDelete, events[0].Action)
+	require.Equal(t, AuditActionUpdate, events[1].Action)
+
+	for _, event := range events {
+		require.Equal(t, AuditEntityEntry, event.EntityType)
+		require.Equal(t, fmt.Sprint(entry.ID), event.EntityID)
+		require.Equal(t, metadata.ClientIP, event.ClientIp)
+		require.Equal(t, metadata.RequestID, event.RequestID)
+	}
+	require.JSONEq(t, "null", string(events[0].After))
+	require.NotEqual(t, "null", string(events[1].After))
+}
+
+func TestRestoreEntryTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+
+	deleteResult, err := store.DeleteEntryTx(context.Background(), DeleteEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+	})
+	require.NoError(t, err)
+
+	trash, err := testQueries.ListDeletedEntries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	require.Equal(t, entry.ID, trash[0].ID)
+	require.True(t, trash[0].DeletedAt.Valid)
+
+	result, err := store.RestoreEntryTx(context.Background(), RestoreEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, entry.ID, result.Entry.ID)
+	require.False(t, result.Entry.DeletedAt.Valid)
+	require.Equal(t, deleteResult.User.TotalExpenses+entry.Amount, result.User.TotalExpenses)
+
+	_, err = testQueries.GetEntry(context.Background(), GetEntryParams{
+		Owner: user.Username,
+		ID:    entry.ID,
+	})
+	require.NoError(t, err)
+
+	// an entry that is not in the trash can't be restored
+	_, err = store.RestoreEntryTx(context.Background(), RestoreEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestRestoreUserTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+
+	err := store.DeleteUserTx(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	restoredUser, err := store.RestoreUserTx(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, restoredUser.Username)
+	require.False(t, restoredUser.DeletedAt.Valid)
+
+	// the entries come back with the account
+	_, err = testQueries.GetEntry(context.Background(), GetEntryParams{
+		Owner: user.Username,
+		ID:    entry.ID,
+	})
+	require.NoError(t, err)
+}
+
+func TestPurgeTrashTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+	keptEntry := createRandomEntry(t, user)
+
+	_, err := store.DeleteEntryTx(context.Background(), DeleteEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+	})
+	require.NoError(t, err)
+
+	deletedUser := createRandomUser(t)
+	createRandomEntry(t, deletedUser)
+	err = store.DeleteUserTx(context.Background(), deletedUser.Username)
+	require.NoError(t, err)
+
+	// nothing is old enough yet
+	result, err := store.PurgeTrashTx(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+
+	trash, err := testQueries.ListDeletedEntries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+
+	result, err = store.PurgeTrashTx(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.Entries, int64(2))
+	require.GreaterOrEqual(t, result.Users, int64(1))
+
+	trash, err = testQueries.ListDeletedEntries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	_, err = testQueries.GetDeletedUser(context.Background(), deletedUser.Username)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	_, err = testQueries.GetEntry(context.Background(), GetEntryParams{
+		Owner: user.Username,
+		ID:    keptEntry.ID,
+	})
+	require.NoError(t, err)
+
+	// the purge is audited as the system's, newest first
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		Actor: AuditActorSystem,
+		Limit: int32(result.Entries + result.Users),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, int(result.Entries+result.Users))
+	require.Equal(t, AuditEntityUser, events[0].EntityType)
+
+	purged := map[string]bool{}
+	for _, event := range events {
+		require.Equal(t, AuditActionPurge, event.Action)
+		require.JSONEq(t, "null", string(event.After))
+		purged[event.EntityType+"/"+event.EntityID] = true
+	}
+	require.True(t, purged[AuditEntityEntry+"/"+fmt.Sprint(entry.ID)])
+	require.True(t, purged[AuditEntityUser+"/"+deletedUser.Username])
+}
+
+func TestReconcileTotalExpensesTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry1 := createRandomEntry(t, user)
+	entry2 := createRandomEntry(t, user)
+
+	result, err := store.ReconcileTotalExpensesTx(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, result.Corrected)
+	require.Equal(t, user.TotalExpenses, result.TotalExpenses)
+	require.Equal(t, entry1.Amount+entry2.Amount, result.EntriesTotal)
+
+	updatedUser, err := testQueries.GetUser(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, result.EntriesTotal, updatedUser.TotalExpenses)
+
+	// nothing left to correct
+	result, err = store.ReconcileTotalExpensesTx(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, result.Corrected)
+
+	_, err = store.ReconcileTotalExpensesTx(context.Background(), util.RandomString(8))
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestUpdateEntryTxVersionMismatch(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+	require.Equal(t, int32(1), entry.Version)
+
+	arg := UpdateEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+		Name:     entry.Name,
+		DueDate:  entry.DueDate,
+		Amount:   entry.Amount + 1,
+		Version:  entry.Version,
+	}
+	result, err := store.UpdateEntryTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, entry.Version+1, result.Entry.Version)
+
+	// a second update from the same stale version loses
+	_, err = store.UpdateEntryTx(context.Background(), arg)
+	var mismatch *VersionMismatchError
+	require.ErrorAs(t, err, &mismatch)
+	require.Equal(t, result.Entry.Version, mismatch.Current.Version)
+	require.Equal(t, result.Entry.Amount, mismatch.Current.Amount)
+
+	_, err = store.DeleteEntryTx(context.Background(), DeleteEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+		Version:  entry.Version,
+	})
+	require.ErrorAs(t, err, &mismatch)
+}
+
+func TestBatchEntriesTx(t *testing.T) {
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+
+	date, err := GetMadeUpDate("2022-12-11")
+	require.NoError(t, err)
+
+	create := EntryOperation{
+		Op:      BatchCreate,
+		Name:    util.RandomString(8),
+		DueDate: date,
+		Amount:  10,
+	}
+	missing := EntryOperation{
+		Op:      BatchDelete,
+		ID:      entry.ID + 100000,
+		Version: 1,
+	}
+
+	// all or nothing: the failing delete rolls back the create
+	_, err = store.BatchEntriesTx(context.Background(), BatchEntriesTxParams{
+		Username:   user.Username,
+		Operations: []EntryOperation{create, missing},
+	})
+	var opErr *BatchOperationError
+	require.ErrorAs(t, err, &opErr)
+	require.Equal(t, 1, opErr.Index)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	entries, err := testQueries.GetEntries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// best effort: the create is kept and the delete reports its error
+	result, err := store.BatchEntriesTx(context.Background(), BatchEntriesTxParams{
+		Username:   user.Username,
+		Operations: []EntryOperation{create, missing},
+		BestEffort: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	require.NoError(t, result.Results[0].Err)
+	require.Equal(t, create.Name, result.Results[0].Entry.Name)
+	require.ErrorIs(t, result.Results[1].Err, sql.ErrNoRows)
+	require.Equal(t, user.TotalExpenses+create.Amount, result.User.TotalExpenses)
+
+	entries, err = testQueries.GetEntries(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+}
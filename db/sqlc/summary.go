@@ -0,0 +1,70 @@
+package db
+
+import (
+	"sort"
+	"time"
+)
+
+// EntrySummary aggregates the amounts of a list of entries
+type EntrySummary struct {
+	Total      int64
+	Entries    int64
+	Categories []CategoryTotal
+	Months     []MonthTotal
+}
+
+// CategoryTotal is the sum of the entries of a category
+type CategoryTotal struct {
+	Category string
+	Total    int64
+	Entries  int64
+}
+
+// MonthTotal is the sum of the entries due in a month formatted as YYYY-MM
+type MonthTotal struct {
+	Month string
+	Total int64
+}
+
+// Sums up the entries due between from and to, both inclusive. A zero from or
+// to leaves that side of the range open. Categories and months are sorted.
+func SummarizeEntries(entries []Entry, from time.Time, to time.Time) EntrySummary {
+	var summary EntrySummary
+	categories := map[string]int{}
+	months := map[string]int{}
+	for _, entry := range entries {
+		dueDate := entry.DueDate.UTC()
+		if (!from.IsZero() && dueDate.Before(from)) || (!to.IsZero() && dueDate.After(to)) {
+			continue
+		}
+
+		summary.Total += entry.Amount
+		summary.Entries++
+
+		i, ok := categories[entry.Category.String]
+		if !ok {
+			i = len(summary.Categories)
+			categories[entry.Category.String] = i
+			summary.Categories = append(summary.Categories, CategoryTotal{Category: entry.Category.String})
+		}
+		summary.Categories[i].Total += entry.Amount
+		summary.Categories[i].Entries++
+
+		key := dueDate.Format("2006-01")
+		i, ok = months[key]
+		if !ok {
+			i = len(summary.Months)
+			months[key] = i
+			summary.Months = append(summary.Months, MonthTotal{Month: key})
+		}
+		summary.Months[i].Total += entry.Amount
+	}
+
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		return summary.Categories[i].Category < summary.Categories[j].Category
+	})
+	sort.Slice(summary.Months, func(i, j int) bool {
+		return summary.Months[i].Month < summary.Months[j].Month
+	})
+	return summary
+}
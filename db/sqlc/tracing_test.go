@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	testCases := []struct {
+		query string
+		name  string
+	}{
+		{query: getUser, name: "GetUser"},
+		{query: updateEntry, name: "UpdateEntry"},
+		{query: "savepoint batch_operation", name: "SAVEPOINT"},
+		{query: "", name: "query"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.name, queryName(tc.query))
+	}
+}
+
+func TestUpdateEntryTxSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	store := newTestStore()
+
+	user := createRandomUser(t)
+	entry := createRandomEntry(t, user)
+
+	_, err := store.UpdateEntryTx(context.Background(), UpdateEntryTxParams{
+		Username: user.Username,
+		ID:       entry.ID,
+		Name:     entry.Name,
+		DueDate:  entry.DueDate,
+		Amount:   entry.Amount + 1,
+	})
+	require.NoError(t, err)
+
+	var tx sdktrace.ReadOnlySpan
+	queries := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.Name() == "db.UpdateEntryTx" {
+			tx = span
+		} else {
+			queries[span.Name()] = span
+		}
+	}
+	require.NotNil(t, tx)
+
+	for _, name := range []string{"db.GetEntryForUpdate", "db.GetUserForUpdate", "db.UpdateEntry", "db.UpdateUser"} {
+		require.Contains(t, queries, name)
+		require.Equal(t, tx.SpanContext().SpanID(), queries[name].Parent().SpanID())
+	}
+}
@@ -7,6 +7,9 @@ package db
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const createUser = `-- name: CreateUser :one
@@ -15,7 +18,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4, $5
 )
-RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at
+RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at
 `
 
 type CreateUserParams struct {
@@ -43,13 +46,15 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE username = $1
+UPDATE users
+SET deleted_at = now()
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) DeleteUser(ctx context.Context, username string) error {
@@ -57,9 +62,30 @@ func (q *Queries) DeleteUser(ctx context.Context, username string) error {
 	return err
 }
 
+const getDeletedUser = `-- name: GetDeletedUser :one
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE username = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) GetDeletedUser(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedUser, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.TotalExpenses,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const getEmail = `-- name: GetEmail :one
-SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at FROM users
-WHERE username = $1
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetEmail(ctx context.Context, username string) (User, error) {
@@ -73,13 +99,14 @@ func (q *Queries) GetEmail(ctx context.Context, username string) (User, error) {
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at FROM users
-WHERE username = $1
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -93,13 +120,14 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserForUpdate = `-- name: GetUserForUpdate :one
-SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at FROM users
-WHERE username = $1
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE username = $1 AND deleted_at IS NULL
 FOR NO KEY UPDATE
 `
 
@@ -114,12 +142,91 @@ func (q *Queries) GetUserForUpdate(ctx context.Context, username string) (User,
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getUsers = `-- name: GetUsers :many
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE username = ANY($1::varchar[]) AND deleted_at IS NULL
+ORDER BY username
+`
+
+func (q *Queries) GetUsers(ctx context.Context, usernames []string) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers, pq.Array(usernames))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.TotalExpenses,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTotalExpensesDrift = `-- name: ListTotalExpensesDrift :many
+SELECT users.username, users.total_expenses, COALESCE(SUM(entries.amount), 0)::bigint AS entries_total
+FROM users
+LEFT JOIN entries ON entries.owner = users.username AND entries.household_id IS NULL AND entries.deleted_at IS NULL
+WHERE users.deleted_at IS NULL
+GROUP BY users.username
+HAVING users.total_expenses <> COALESCE(SUM(entries.amount), 0)
+ORDER BY users.username
+`
+
+type ListTotalExpensesDriftRow struct {
+	Username      string `json:"username"`
+	TotalExpenses int64  `json:"total_expenses"`
+	EntriesTotal  int64  `json:"entries_total"`
+}
+
+func (q *Queries) ListTotalExpensesDrift(ctx context.Context) ([]ListTotalExpensesDriftRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTotalExpensesDrift)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTotalExpensesDriftRow{}
+	for rows.Next() {
+		var i ListTotalExpensesDriftRow
+		if err := rows.Scan(&i.Username, &i.TotalExpenses, &i.EntriesTotal); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at FROM users
+WHERE deleted_at IS NULL
 ORDER BY username
 LIMIT $1
 OFFSET $2
@@ -147,6 +254,45 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.TotalExpenses,
 			&i.PasswordChangedAt,
 			&i.CreatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeUsers = `-- name: PurgeUsers :many
+DELETE FROM users
+WHERE deleted_at < $1
+RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at
+`
+
+func (q *Queries) PurgeUsers(ctx context.Context, deletedBefore sql.NullTime) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, purgeUsers, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Username,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			&i.TotalExpenses,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -164,7 +310,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 const resetPassword = `-- name: ResetPassword :exec
 UPDATE users
 SET hashed_password = $2
-WHERE username = $1
+WHERE username = $1 AND deleted_at IS NULL
 `
 
 type ResetPasswordParams struct {
@@ -177,11 +323,34 @@ func (q *Queries) ResetPassword(ctx context.Context, arg ResetPasswordParams) er
 	return err
 }
 
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE username = $1 AND deleted_at IS NOT NULL
+RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.TotalExpenses,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET total_expenses = $2
-WHERE username = $1
-RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at
 `
 
 type UpdateUserParams struct {
@@ -200,6 +369,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -207,8 +377,8 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 const updateUserInfo = `-- name: UpdateUserInfo :one
 UPDATE users
 SET username = $2, full_name = $3, email = $4
-WHERE username = $1
-RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at
+WHERE username = $1 AND deleted_at IS NULL
+RETURNING username, hashed_password, full_name, email, total_expenses, password_changed_at, created_at, deleted_at
 `
 
 type UpdateUserInfoParams struct {
@@ -234,6 +404,7 @@ func (q *Queries) UpdateUserInfo(ctx context.Context, arg UpdateUserInfoParams)
 		&i.TotalExpenses,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -104,3 +104,40 @@ func TestListUsers(t *testing.T) {
 	}
 }
 
+
+func TestGetUsers(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2 := createRandomUser(t)
+
+	users, err := testQueries.GetUsers(context.Background(), []string{user1.Username, user2.Username, util.RandomString(8)})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	usernames := []string{users[0].Username, users[1].Username}
+	require.ElementsMatch(t, []string{user1.Username, user2.Username}, usernames)
+}
+
+func TestListTotalExpensesDrift(t *testing.T) {
+	user := createRandomUser(t)
+	// CreateEntry doesn't touch the total, leaving it out of sync
+	entry := createRandomEntry(t, user)
+	inSync, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username:      createRandomUser(t).Username,
+		TotalExpenses: 0,
+	})
+	require.NoError(t, err)
+
+	drifts, err := testQueries.ListTotalExpensesDrift(context.Background())
+	require.NoError(t, err)
+
+	var found bool
+	for _, drift := range drifts {
+		require.NotEqual(t, inSync.Username, drift.Username)
+		if drift.Username == user.Username {
+			found = true
+			require.Equal(t, user.TotalExpenses, drift.TotalExpenses)
+			require.Equal(t, entry.Amount, drift.EntriesTotal)
+		}
+	}
+	require.True(t, found)
+}
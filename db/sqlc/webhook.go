@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+// webhookEntry is the entry sent in the payload of entry webhooks
+type webhookEntry struct {
+	ID       int32   `json:"id"`
+	Name     string  `json:"name"`
+	DueDate  string  `json:"due_date"`
+	Amount   int64   `json:"amount"`
+	Category *string `json:"category"`
+}
+
+func newWebhookEntry(entry Entry) webhookEntry {
+	webhookEntry := webhookEntry{
+		ID:      entry.ID,
+		Name:    entry.Name,
+		DueDate: entry.DueDate.Format("2006-01-02"),
+		Amount:  entry.Amount,
+	}
+	if entry.Category.Valid {
+		webhookEntry.Category = &entry.Category.String
+	}
+	return webhookEntry
+}
+
+// Queues an entry created event for the webhooks of the owner
+func queueEntryCreated(ctx context.Context, q Querier, entry Entry) error {
+	payload, err := json.Marshal(map[string]webhookEntry{"entry": newWebhookEntry(entry)})
+	if err != nil {
+		return err
+	}
+
+	_, err = q.EnqueueWebhookEvent(ctx, EnqueueWebhookEventParams{
+		EventType: util.EntryCreatedEvent,
+		DedupeKey: fmt.Sprintf("entry:%d", entry.ID),
+		Payload:   payload,
+		Owner:     entry.Owner,
+	})
+	return err
+}
+
+// Queues a budget exceeded event when the expenses of the owner in the month of dueDate
+// go over the budget of a webhook. Each webhook is notified at most once per month.
+func queueBudgetExceeded(ctx context.Context, q Querier, owner string, dueDate time.Time) error {
+	year, month, _ := dueDate.Date()
+	monthStart := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := q.EnqueueBudgetExceeded(ctx, EnqueueBudgetExceededParams{
+		Month:      monthStart.Format("2006-01"),
+		Owner:      owner,
+		MonthStart: monthStart,
+		MonthEnd:   monthStart.AddDate(0, 1, 0),
+	})
+	return err
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomWebhook(t *testing.T, user User, budget int64, eventTypes ...string) Webhook {
+	arg := CreateWebhookParams{
+		Owner:      user.Username,
+		Url:        "https://example.com/" + util.RandomString(6),
+		Secret:     util.RandomString(64),
+		EventTypes: eventTypes,
+		Budget:     budget,
+	}
+
+	webhook, err := testQueries.CreateWebhook(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Url, webhook.Url)
+	require.Equal(t, arg.EventTypes, webhook.EventTypes)
+	require.Equal(t, arg.Budget, webhook.Budget)
+	return webhook
+}
+
+func listDeliveries(t *testing.T, webhook Webhook) []WebhookDelivery {
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		WebhookID: webhook.ID,
+		Limit:     50,
+	})
+	require.NoError(t, err)
+	return deliveries
+}
+
+func TestAddEntryTxQueuesWebhooks(t *testing.T) {
+	store := newTestStore()
+	user := createRandomUser(t)
+	entryWebhook := createRandomWebhook(t, user, 0, util.EntryCreatedEvent)
+	budgetWebhook := createRandomWebhook(t, user, 100, util.BudgetExceededEvent)
+
+	date, err := GetMadeUpDate("2022-12-11")
+	require.NoError(t, err)
+
+	add := func(amount int64) Entry {
+		result, err := store.AddEntryTx(context.Background(), AddEntryTxParams{
+			Username: user.Username,
+			Name:     util.RandomString(6),
+			DueDate:  date,
+			Amount:   amount,
+			Category: util.RandomString(6),
+		})
+		require.NoError(t, err)
+		return result.Entry
+	}
+
+	entry := add(60)
+	deliveries := listDeliveries(t, entryWebhook)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, util.EntryCreatedEvent, deliveries[0].EventType)
+	require.Equal(t, "pending", deliveries[0].Status)
+
+	var payload struct {
+		Entry webhookEntry `json:"entry"`
+	}
+	require.NoError(t, json.Unmarshal(deliveries[0].Payload, &payload))
+	require.Equal(t, newWebhookEntry(entry), payload.Entry)
+	require.Empty(t, listDeliveries(t, budgetWebhook))
+
+	// the budget is only reported once per month
+	add(60)
+	add(60)
+	deliveries = listDeliveries(t, budgetWebhook)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "2022-12", deliveries[0].DedupeKey)
+	require.JSONEq(t, `{"month":"2022-12","total":120,"budget":100}`, string(deliveries[0].Payload))
+}
+
+func TestClaimWebhookDeliveries(t *testing.T) {
+	user := createRandomUser(t)
+	webhook := createRandomWebhook(t, user, 0, util.EntryCreatedEvent)
+
+	delivery, err := testQueries.CreateWebhookDelivery(context.Background(), CreateWebhookDeliveryParams{
+		WebhookID: webhook.ID,
+		EventType: util.WebhookTestEvent,
+		DedupeKey: util.RandomString(10),
+		Payload:   json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	arg := ClaimWebhookDeliveriesParams{
+		LeaseUntil:    time.Now().Add(time.Minute),
+		MaxDeliveries: 1000,
+	}
+	claimed, err := testQueries.ClaimWebhookDeliveries(context.Background(), arg)
+	require.NoError(t, err)
+
+	found := false
+	for _, c := range claimed {
+		if c.ID == delivery.ID {
+			found = true
+			require.Equal(t, int32(1), c.Attempts)
+		}
+	}
+	require.True(t, found)
+
+	// leased deliveries are not claimed again
+	claimed, err = testQueries.ClaimWebhookDeliveries(context.Background(), arg)
+	require.NoError(t, err)
+	for _, c := range claimed {
+		require.NotEqual(t, delivery.ID, c.ID)
+	}
+
+	err = testQueries.CompleteWebhookDelivery(context.Background(), CompleteWebhookDeliveryParams{
+		ID:             delivery.ID,
+		LastStatusCode: 200,
+	})
+	require.NoError(t, err)
+
+	deliveries := listDeliveries(t, webhook)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, "succeeded", deliveries[0].Status)
+	require.True(t, deliveries[0].DeliveredAt.Valid)
+}
@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS audit_events;
+DROP TABLE IF EXISTS settlements;
+DROP TABLE IF EXISTS balances;
+DROP TABLE IF EXISTS expense_shares;
+DROP TABLE IF EXISTS shared_expenses;
+DROP TABLE IF EXISTS household_invitations;
+DROP TABLE IF EXISTS household_members;
+DROP TABLE IF EXISTS entries;
+DROP TABLE IF EXISTS households;
+DROP TABLE IF EXISTS users;
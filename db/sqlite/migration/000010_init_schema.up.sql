@@ -0,0 +1,194 @@
+-- The SQLite schema starts at version 10 of the Postgres one, later migrations
+-- are added to both with the same version.
+--
+-- Times are stored as fixed width UTC text, so they compare in time order.
+-- Arrays are stored as Postgres array literals, as lib/pq encodes them, and JSON
+-- as blobs so it scans into json.RawMessage.
+-- Changes are not published to the event streams, there is no LISTEN/NOTIFY.
+
+CREATE TABLE "users" (
+  "username" varchar NOT NULL PRIMARY KEY,
+  "hashed_password" varchar NOT NULL,
+  "full_name" varchar NOT NULL,
+  "email" varchar UNIQUE NOT NULL,
+  "total_expenses" bigint NOT NULL DEFAULT 0,
+  "password_changed_at" timestamp NOT NULL DEFAULT '0001-01-01 00:00:00.000000000Z',
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  -- set while the account is in the trash
+  "deleted_at" timestamp
+);
+
+CREATE INDEX "users_deleted_at_idx" ON "users" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE TABLE "households" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "name" varchar NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE TABLE "entries" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "owner" varchar NOT NULL REFERENCES "users" ("username"),
+  "name" varchar NOT NULL,
+  "due_date" timestamp NOT NULL,
+  -- must be positive
+  "amount" bigint NOT NULL DEFAULT 0,
+  "category" varchar,
+  "household_id" integer REFERENCES "households" ("id") ON DELETE CASCADE,
+  -- member who created the entry
+  "created_by" varchar,
+  -- set while the entry is in the trash
+  "deleted_at" timestamp,
+  -- incremented on every change, used for optimistic concurrency
+  "version" integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX "entries_owner_idx" ON "entries" ("owner");
+
+CREATE INDEX "entries_household_id_idx" ON "entries" ("household_id");
+
+CREATE UNIQUE INDEX "entries_name_key" ON "entries" ("name") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX "entries_deleted_at_idx" ON "entries" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+
+CREATE TABLE "household_members" (
+  "household_id" integer NOT NULL REFERENCES "households" ("id") ON DELETE CASCADE,
+  "username" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  -- owner, editor or viewer
+  "role" varchar NOT NULL CHECK ("role" IN ('owner', 'editor', 'viewer')),
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  PRIMARY KEY ("household_id", "username")
+);
+
+CREATE INDEX "household_members_username_idx" ON "household_members" ("username");
+
+CREATE TABLE "household_invitations" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "household_id" integer NOT NULL REFERENCES "households" ("id") ON DELETE CASCADE,
+  "email" varchar NOT NULL,
+  "role" varchar NOT NULL CHECK ("role" IN ('owner', 'editor', 'viewer')),
+  "invited_by" varchar NOT NULL,
+  "accepted" boolean NOT NULL DEFAULT false,
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE INDEX "household_invitations_email_idx" ON "household_invitations" ("email");
+
+CREATE TABLE "shared_expenses" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "entry_id" integer UNIQUE NOT NULL REFERENCES "entries" ("id") ON DELETE CASCADE,
+  "paid_by" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  -- equal, exact or percentage
+  "split_type" varchar NOT NULL CHECK ("split_type" IN ('equal', 'exact', 'percentage')),
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE TABLE "expense_shares" (
+  "expense_id" integer NOT NULL REFERENCES "shared_expenses" ("id") ON DELETE CASCADE,
+  "username" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  -- part of the entry amount owed by the user
+  "amount" bigint NOT NULL,
+  PRIMARY KEY ("expense_id", "username")
+);
+
+CREATE INDEX "expense_shares_username_idx" ON "expense_shares" ("username");
+
+CREATE TABLE "balances" (
+  "user_a" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  "user_b" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  -- positive when user_b owes user_a
+  "amount" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  PRIMARY KEY ("user_a", "user_b"),
+  CONSTRAINT "balances_ordered_check" CHECK ("user_a" < "user_b")
+);
+
+CREATE INDEX "balances_user_b_idx" ON "balances" ("user_b");
+
+CREATE TABLE "settlements" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "from_username" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  "to_username" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  -- must be positive
+  "amount" bigint NOT NULL CHECK ("amount" > 0),
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE INDEX "settlements_from_username_idx" ON "settlements" ("from_username");
+
+CREATE INDEX "settlements_to_username_idx" ON "settlements" ("to_username");
+
+CREATE TABLE "audit_events" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  -- username that made the change, kept after the user is deleted
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "entity_type" varchar NOT NULL,
+  "entity_id" varchar NOT NULL,
+  "before" blob NOT NULL DEFAULT (CAST('null' AS BLOB)),
+  "after" blob NOT NULL DEFAULT (CAST('null' AS BLOB)),
+  "client_ip" varchar NOT NULL DEFAULT '',
+  "request_id" varchar NOT NULL DEFAULT '',
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE INDEX "audit_events_actor_id_idx" ON "audit_events" ("actor", "id");
+
+CREATE INDEX "audit_events_entity_type_entity_id_idx" ON "audit_events" ("entity_type", "entity_id");
+
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  "key" varchar NOT NULL,
+  -- sha256 of the method, path and body of the first request
+  "request_hash" varchar NOT NULL,
+  -- 0 while the first request is still running
+  "status_code" integer NOT NULL DEFAULT 0,
+  "response_body" blob,
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  PRIMARY KEY ("username", "key")
+);
+
+CREATE INDEX "idempotency_keys_created_at_idx" ON "idempotency_keys" ("created_at");
+
+CREATE TABLE "webhooks" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "owner" varchar NOT NULL REFERENCES "users" ("username") ON UPDATE CASCADE ON DELETE CASCADE,
+  "url" varchar NOT NULL,
+  -- key of the HMAC-SHA256 signature of the payloads
+  "secret" varchar NOT NULL,
+  "event_types" text NOT NULL,
+  -- monthly expenses above which budget.exceeded is sent, 0 when unused
+  "budget" bigint NOT NULL DEFAULT 0,
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'))
+);
+
+CREATE INDEX "webhooks_owner_idx" ON "webhooks" ("owner");
+
+CREATE TABLE "webhook_deliveries" (
+  "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "webhook_id" integer NOT NULL REFERENCES "webhooks" ("id") ON DELETE CASCADE,
+  "event_type" varchar NOT NULL,
+  -- identifies the occurrence of the event so it is only delivered once
+  "dedupe_key" varchar NOT NULL,
+  "payload" blob NOT NULL,
+  -- pending, succeeded or failed
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_status_code" integer NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  "created_at" timestamp NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')),
+  "delivered_at" timestamp
+);
+
+CREATE UNIQUE INDEX "webhook_deliveries_webhook_id_event_type_dedupe_key_idx" ON "webhook_deliveries" ("webhook_id", "event_type", "dedupe_key");
+
+CREATE INDEX "webhook_deliveries_next_attempt_at_idx" ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE TABLE "rate_limit_buckets" (
+  "key" varchar NOT NULL PRIMARY KEY,
+  "tokens" double precision NOT NULL,
+  "updated_at" timestamp NOT NULL
+);
+
+CREATE INDEX "rate_limit_buckets_updated_at_idx" ON "rate_limit_buckets" ("updated_at");
@@ -0,0 +1,2 @@
+-- Content-Type of the stored response
+ALTER TABLE "idempotency_keys" ADD COLUMN "content_type" varchar NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  actor, action, entity_type, entity_id, before, after, client_ip, request_id
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8
+)
+RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE actor = ?1
+ORDER BY id DESC
+LIMIT ?2
+OFFSET ?3;
@@ -0,0 +1,119 @@
+-- name: CreateEntry :one
+INSERT INTO entries (
+  owner, name, due_date, amount, category, created_by
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5, ?6
+)
+RETURNING *;
+
+-- name: GetEntries :many
+SELECT * FROM entries
+WHERE owner = ?1 AND household_id IS NULL AND deleted_at IS NULL;
+
+-- name: GetEntry :one
+SELECT * FROM entries
+WHERE owner = ?1 AND id = ?2 AND household_id IS NULL AND deleted_at IS NULL;
+
+-- name: GetEntryForUpdate :one
+SELECT * FROM entries
+WHERE owner = ?1 AND id = ?2 AND household_id IS NULL AND deleted_at IS NULL;
+
+-- name: GetCategories :many
+SELECT category FROM entries
+WHERE owner = ?1 AND household_id IS NULL AND deleted_at IS NULL AND category != '' AND category IS NOT NULL
+GROUP BY category;
+
+-- name: UpdateEntry :one
+UPDATE entries
+SET name = ?3, due_date = ?4, amount = ?5, category = ?6, version = version + 1
+WHERE owner = ?1 AND id = ?2 AND household_id IS NULL AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteEntry :exec
+UPDATE entries
+SET deleted_at = strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'), version = version + 1
+WHERE id = ?1 AND deleted_at IS NULL;
+
+-- name: DeleteEntries :exec
+DELETE FROM entries
+WHERE owner = ?1;
+
+-- name: UpdateEntriesOwner :exec
+UPDATE entries
+SET owner = ?2
+WHERE owner = ?1;
+
+-- name: CreateHouseholdEntry :one
+INSERT INTO entries (
+  owner, name, due_date, amount, category, household_id, created_by
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5, ?6, ?1
+)
+RETURNING *;
+
+-- name: GetHouseholdEntries :many
+SELECT * FROM entries
+WHERE household_id = ?1 AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetEntriesOfHouseholds :many
+SELECT * FROM entries
+WHERE household_id IN (SELECT CAST(value AS INTEGER) FROM json_each(array_to_json(?1))) AND deleted_at IS NULL
+ORDER BY household_id, id;
+
+-- name: GetHouseholdEntry :one
+SELECT * FROM entries
+WHERE household_id = ?1 AND id = ?2 AND deleted_at IS NULL;
+
+-- name: GetHouseholdEntryForUpdate :one
+SELECT * FROM entries
+WHERE household_id = ?1 AND id = ?2 AND deleted_at IS NULL;
+
+-- name: UpdateHouseholdEntry :one
+UPDATE entries
+SET name = ?3, due_date = ?4, amount = ?5, category = ?6, version = version + 1
+WHERE household_id = ?1 AND id = ?2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteHouseholdEntry :exec
+UPDATE entries
+SET deleted_at = strftime('%Y-%m-%d %H:%M:%f000000Z', 'now'), version = version + 1
+WHERE household_id = ?1 AND id = ?2 AND deleted_at IS NULL;
+
+-- name: GetEntriesTotal :one
+SELECT COALESCE(SUM(amount), 0) AS total FROM entries
+WHERE owner = ?1 AND household_id IS NULL AND deleted_at IS NULL;
+
+-- name: GetHouseholdTotal :one
+SELECT COALESCE(SUM(amount), 0) AS total FROM entries
+WHERE household_id = ?1 AND deleted_at IS NULL;
+
+-- name: ListDeletedEntries :many
+SELECT * FROM entries
+WHERE owner = ?1 AND household_id IS NULL AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetDeletedEntryForUpdate :one
+SELECT * FROM entries
+WHERE owner = ?1 AND id = ?2 AND household_id IS NULL AND deleted_at IS NOT NULL;
+
+-- name: ListDeletedHouseholdEntries :many
+SELECT * FROM entries
+WHERE household_id = ?1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: GetDeletedHouseholdEntryForUpdate :one
+SELECT * FROM entries
+WHERE household_id = ?1 AND id = ?2 AND deleted_at IS NOT NULL;
+
+-- name: RestoreEntry :one
+UPDATE entries
+SET deleted_at = NULL, version = version + 1
+WHERE id = ?1
+RETURNING *;
+
+-- name: PurgeEntries :many
+DELETE FROM entries
+WHERE deleted_at < ?1
+  OR owner IN (SELECT username FROM users WHERE deleted_at < ?1)
+RETURNING *;
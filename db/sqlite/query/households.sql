@@ -0,0 +1,80 @@
+-- name: CreateHousehold :one
+INSERT INTO households (
+  name
+) VALUES (
+  ?1
+)
+RETURNING *;
+
+-- name: GetHousehold :one
+SELECT * FROM households
+WHERE id = ?1;
+
+-- name: ListHouseholds :many
+SELECT households.* FROM households
+JOIN household_members ON household_members.household_id = households.id
+WHERE household_members.username = ?1
+ORDER BY households.id;
+
+-- name: DeleteHousehold :exec
+DELETE FROM households
+WHERE id = ?1;
+
+-- name: AddHouseholdMember :one
+INSERT INTO household_members (
+  household_id, username, role
+) VALUES (
+  ?1, ?2, ?3
+)
+RETURNING *;
+
+-- name: GetHouseholdMember :one
+SELECT * FROM household_members
+WHERE household_id = ?1 AND username = ?2;
+
+-- name: GetHouseholdMemberForUpdate :one
+SELECT * FROM household_members
+WHERE household_id = ?1 AND username = ?2;
+
+-- name: ListHouseholdMembers :many
+SELECT * FROM household_members
+WHERE household_id = ?1
+ORDER BY created_at;
+
+-- name: ListMembersOfHouseholds :many
+SELECT * FROM household_members
+WHERE household_id IN (SELECT CAST(value AS INTEGER) FROM json_each(array_to_json(?1)))
+ORDER BY household_id, created_at;
+
+-- name: UpdateHouseholdMemberRole :one
+UPDATE household_members
+SET role = ?3
+WHERE household_id = ?1 AND username = ?2
+RETURNING *;
+
+-- name: DeleteHouseholdMember :exec
+DELETE FROM household_members
+WHERE household_id = ?1 AND username = ?2;
+
+-- name: CreateHouseholdInvitation :one
+INSERT INTO household_invitations (
+  household_id, email, role, invited_by
+) VALUES (
+  ?1, ?2, ?3, ?4
+)
+RETURNING *;
+
+-- name: GetHouseholdInvitationForUpdate :one
+SELECT * FROM household_invitations
+WHERE id = ?1;
+
+-- name: ListHouseholdInvitations :many
+SELECT * FROM household_invitations
+WHERE email = ?1 AND accepted = false
+ORDER BY id;
+
+-- name: AcceptHouseholdInvitation :one
+UPDATE household_invitations
+SET accepted = true
+WHERE id = ?1
+RETURNING *;
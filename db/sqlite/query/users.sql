@@ -0,0 +1,77 @@
+-- name: CreateUser :one
+INSERT INTO users (
+  username, hashed_password, full_name, email, total_expenses
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5
+)
+RETURNING *;
+
+-- name: GetUser :one
+SELECT * FROM users
+WHERE username = ?1 AND deleted_at IS NULL;
+
+-- name: GetEmail :one
+SELECT * FROM users
+WHERE username = ?1 AND deleted_at IS NULL;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = ?1 AND deleted_at IS NULL;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+ORDER BY username
+LIMIT ?1
+OFFSET ?2;
+
+-- name: GetUsers :many
+SELECT * FROM users
+WHERE username IN (SELECT value FROM json_each(array_to_json(?1))) AND deleted_at IS NULL
+ORDER BY username;
+
+-- name: UpdateUser :one
+UPDATE users
+SET total_expenses = ?2
+WHERE username = ?1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ResetPassword :exec
+UPDATE users
+SET hashed_password = ?2
+WHERE username = ?1 AND deleted_at IS NULL;
+
+-- name: DeleteUser :exec
+UPDATE users
+SET deleted_at = strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')
+WHERE username = ?1 AND deleted_at IS NULL;
+
+-- name: UpdateUserInfo :one
+UPDATE users
+SET username = ?2, full_name = ?3, email = ?4
+WHERE username = ?1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: GetDeletedUser :one
+SELECT * FROM users
+WHERE username = ?1 AND deleted_at IS NOT NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE username = ?1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: PurgeUsers :many
+DELETE FROM users
+WHERE deleted_at < ?1
+RETURNING *;
+
+-- name: ListTotalExpensesDrift :many
+SELECT users.username, users.total_expenses, COALESCE(SUM(entries.amount), 0) AS entries_total
+FROM users
+LEFT JOIN entries ON entries.owner = users.username AND entries.household_id IS NULL AND entries.deleted_at IS NULL
+WHERE users.deleted_at IS NULL
+GROUP BY users.username
+HAVING users.total_expenses <> COALESCE(SUM(entries.amount), 0)
+ORDER BY users.username;
@@ -0,0 +1,95 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+  owner, url, secret, event_types, budget
+) VALUES (
+  ?1, ?2, ?3, ?4, ?5
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE owner = ?1 AND id = ?2;
+
+-- name: GetWebhookForDelivery :one
+SELECT * FROM webhooks
+WHERE id = ?1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+WHERE owner = ?1
+ORDER BY id;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE owner = ?1 AND id = ?2;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (
+  webhook_id, event_type, dedupe_key, payload
+) VALUES (
+  ?1, ?2, ?3, ?4
+)
+RETURNING *;
+
+-- name: EnqueueWebhookEvent :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT id, ?1, ?2, ?3 FROM webhooks
+WHERE owner = ?4 AND ?1 IN (SELECT value FROM json_each(array_to_json(event_types)))
+ON CONFLICT DO NOTHING;
+
+-- name: EnqueueBudgetExceeded :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT webhooks.id, 'budget.exceeded', ?1,
+  CAST(json_object('month', ?1, 'total', month_total.total, 'budget', webhooks.budget) AS BLOB)
+FROM webhooks, (
+  SELECT COALESCE(SUM(amount), 0) AS total FROM entries
+  WHERE owner = ?2 AND household_id IS NULL AND deleted_at IS NULL
+    AND due_date >= ?3 AND due_date < ?4
+) AS month_total
+WHERE webhooks.owner = ?2 AND 'budget.exceeded' IN (SELECT value FROM json_each(array_to_json(webhooks.event_types)))
+  AND webhooks.budget > 0 AND month_total.total > webhooks.budget
+ON CONFLICT DO NOTHING;
+
+-- name: EnqueueBillDue :execrows
+INSERT INTO webhook_deliveries (webhook_id, event_type, dedupe_key, payload)
+SELECT webhooks.id, 'bill.due', entries.id || ':' || strftime('%Y-%m-%d', entries.due_date),
+  CAST(json_object('entry', json_object(
+    'id', entries.id,
+    'name', entries.name,
+    'due_date', strftime('%Y-%m-%d', entries.due_date),
+    'amount', entries.amount,
+    'category', entries.category
+  )) AS BLOB)
+FROM entries
+JOIN webhooks ON webhooks.owner = entries.owner AND 'bill.due' IN (SELECT value FROM json_each(array_to_json(webhooks.event_types)))
+WHERE entries.household_id IS NULL AND entries.deleted_at IS NULL
+  AND entries.due_date >= ?1 AND entries.due_date < ?2
+ON CONFLICT DO NOTHING;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = ?1
+ORDER BY id DESC
+LIMIT ?2
+OFFSET ?3;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET attempts = attempts + 1, next_attempt_at = ?1
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')
+  ORDER BY next_attempt_at
+  LIMIT ?2
+)
+RETURNING *;
+
+-- name: CompleteWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = 'succeeded', last_status_code = ?2, last_error = '', delivered_at = strftime('%Y-%m-%d %H:%M:%f000000Z', 'now')
+WHERE id = ?1;
+
+-- name: FailWebhookDelivery :exec
+UPDATE webhook_deliveries
+SET status = ?2, last_status_code = ?3, last_error = ?4, next_attempt_at = ?5
+WHERE id = ?1;
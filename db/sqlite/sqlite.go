@@ -0,0 +1,198 @@
+// Package sqlite holds the SQLite version of the schema and of the sqlc
+// queries, so the API can run on a single file instead of a Postgres server
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// DriverName is the database/sql name of the driver, the DB_DRIVER that selects SQLite
+const DriverName = "sqlite"
+
+// TimeLayout is how times are stored. It has a fixed width and is always UTC,
+// so comparing the text compares the times.
+const TimeLayout = "2006-01-02 15:04:05.000000000Z"
+
+//go:embed migration/*.sql
+var migrations embed.FS
+
+//go:embed query/*.sql
+var queryFiles embed.FS
+
+// SQLite version of each query, by sqlc name
+var queries = mustParseQueries()
+
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("array_to_json", 1, arrayToJSON)
+}
+
+// Migrations returns the migrations of the SQLite schema, in the golang-migrate layout
+func Migrations() fs.FS {
+	files, err := fs.Sub(migrations, "migration")
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
+
+// Query returns the SQLite version of the sqlc query called name, with its
+// "-- name:" header
+func Query(name string) (string, bool) {
+	query, ok := queries[name]
+	return query, ok
+}
+
+// QueryNames returns the names of all the queries with a SQLite version
+func QueryNames() []string {
+	names := make([]string, 0, len(queries))
+	for name := range queries {
+		names = append(names, name)
+	}
+	return names
+}
+
+var queryHeader = regexp.MustCompile(`(?m)^-- name: (\w+) :\w+\s*$`)
+
+func mustParseQueries() map[string]string {
+	files, err := fs.Glob(queryFiles, "query/*.sql")
+	if err != nil {
+		panic(err)
+	}
+
+	parsed := make(map[string]string)
+	for _, file := range files {
+		content, err := queryFiles.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+
+		text := string(content)
+		headers := queryHeader.FindAllStringSubmatchIndex(text, -1)
+		for i, header := range headers {
+			end := len(text)
+			if i+1 < len(headers) {
+				end = headers[i+1][0]
+			}
+
+			name := text[header[2]:header[3]]
+			if _, ok := parsed[name]; ok {
+				panic(fmt.Sprintf("query %s is defined twice", name))
+			}
+			parsed[name] = strings.TrimSuffix(strings.TrimSpace(text[header[0]:end]), ";")
+		}
+	}
+	return parsed
+}
+
+// Pragmas applied to every connection unless the source sets them
+var defaultPragmas = []string{
+	"foreign_keys(1)",
+	"busy_timeout(10000)",
+	"journal_mode(WAL)",
+}
+
+// Open opens the database file of source, a modernc.org/sqlite DSN such as
+// file:budgetapp.db. Foreign keys are enforced, writers wait for each other
+// instead of failing, and transactions take the write lock when they begin so
+// they can't deadlock upgrading a read lock.
+func Open(source string) (*sql.DB, error) {
+	path, rawQuery, _ := strings.Cut(source, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SQLite source: %w", err)
+	}
+
+	for _, pragma := range defaultPragmas {
+		name, _, _ := strings.Cut(pragma, "(")
+		if !hasPragma(params, name) {
+			params.Add("_pragma", pragma)
+		}
+	}
+	if params.Get("_txlock") == "" {
+		params.Set("_txlock", "immediate")
+	}
+
+	return sql.Open(DriverName, path+"?"+params.Encode())
+}
+
+func hasPragma(params url.Values, name string) bool {
+	for _, pragma := range params["_pragma"] {
+		if strings.HasPrefix(strings.ToLower(pragma), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decodes a Postgres array literal, as lib/pq encodes the array arguments,
+// into a JSON array of strings that json_each can read
+func arrayToJSON(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	var array pq.StringArray
+	err := array.Scan(args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if array == nil {
+		array = pq.StringArray{}
+	}
+	encoded, err := json.Marshal(array)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+// Names of the constraint violations, as Postgres reports them
+const (
+	UniqueViolation     = "unique_violation"
+	ForeignKeyViolation = "foreign_key_violation"
+)
+
+// Postgres names of the unique constraints, by the columns SQLite reports
+var uniqueConstraints = map[string]string{
+	"users.username": "users_pkey",
+	"users.email":    "users_email_key",
+	"entries.name":   "entries_name_key",
+	"household_members.household_id, household_members.username":                                  "household_members_pkey",
+	"shared_expenses.entry_id":                                                                    "shared_expenses_entry_id_key",
+	"expense_shares.expense_id, expense_shares.username":                                          "expense_shares_pkey",
+	"balances.user_a, balances.user_b":                                                            "balances_pkey",
+	"idempotency_keys.username, idempotency_keys.key":                                             "idempotency_keys_pkey",
+	"rate_limit_buckets.key":                                                                      "rate_limit_buckets_pkey",
+	"webhook_deliveries.webhook_id, webhook_deliveries.event_type, webhook_deliveries.dedupe_key": "webhook_deliveries_webhook_id_event_type_dedupe_key_idx",
+}
+
+// ConstraintViolation returns the Postgres name of the violation err reports,
+// and of the constraint when it is a unique one, so callers can handle both
+// databases alike. ok is false for other errors.
+func ConstraintViolation(err error) (code string, constraint string, ok bool) {
+	var sqliteError *sqlite.Error
+	if !errors.As(err, &sqliteError) {
+		return "", "", false
+	}
+
+	switch sqliteError.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		// the message ends with the columns, "UNIQUE constraint failed: users.email"
+		_, columns, _ := strings.Cut(sqliteError.Error(), "UNIQUE constraint failed: ")
+		columns = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(columns), fmt.Sprintf("(%d)", sqliteError.Code())))
+		return UniqueViolation, uniqueConstraints[columns], true
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return ForeignKeyViolation, "", true
+	}
+	return "", "", false
+}
@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEveryQueryHasSQLiteVersion(t *testing.T) {
+	files, err := filepath.Glob("../query/*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	var names []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		for _, header := range queryHeader.FindAllStringSubmatch(string(content), -1) {
+			names = append(names, header[1])
+		}
+	}
+
+	require.ElementsMatch(t, names, QueryNames())
+}
+
+func TestOpen(t *testing.T) {
+	db, err := Open("file:" + filepath.Join(t.TempDir(), "budgetapp.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var foreignKeys int
+	require.NoError(t, db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	require.Equal(t, 1, foreignKeys)
+
+	var usernames []string
+	rows, err := db.Query(`SELECT value FROM json_each(array_to_json(?))`, `{alice,"bob smith"}`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var username string
+		require.NoError(t, rows.Scan(&username))
+		usernames = append(usernames, username)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{"alice", "bob smith"}, usernames)
+}
+
+func TestConstraintViolation(t *testing.T) {
+	db, err := Open("file:" + filepath.Join(t.TempDir(), "budgetapp.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+CREATE TABLE users (username varchar PRIMARY KEY, email varchar UNIQUE NOT NULL);
+CREATE TABLE entries (id INTEGER PRIMARY KEY, owner varchar NOT NULL REFERENCES users (username));
+INSERT INTO users VALUES ('alice', 'alice@email.com');`)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		query      string
+		code       string
+		constraint string
+		ok         bool
+	}{
+		{
+			name:       "PrimaryKey",
+			query:      `INSERT INTO users VALUES ('alice', 'other@email.com')`,
+			code:       UniqueViolation,
+			constraint: "users_pkey",
+			ok:         true,
+		},
+		{
+			name:       "Unique",
+			query:      `INSERT INTO users VALUES ('bob', 'alice@email.com')`,
+			code:       UniqueViolation,
+			constraint: "users_email_key",
+			ok:         true,
+		},
+		{
+			name:  "ForeignKey",
+			query: `INSERT INTO entries (owner) VALUES ('bob')`,
+			code:  ForeignKeyViolation,
+			ok:    true,
+		},
+		{
+			name:  "OtherError",
+			query: `INSERT INTO missing VALUES (1)`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := db.Exec(tc.query)
+			require.Error(t, err)
+
+			code, constraint, ok := ConstraintViolation(err)
+			require.Equal(t, tc.code, code)
+			require.Equal(t, tc.constraint, constraint)
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
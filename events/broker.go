@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// Events buffered for each subscriber
+const subscriberBuffer = 16
+
+// Broker fans the events out to the subscribers of their user
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+	closed      bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: map[string]map[chan Event]struct{}{},
+	}
+}
+
+// Returns the events of username until cancel is called
+func (broker *Broker) Subscribe(username string) (events <-chan Event, cancel func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	if broker.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if broker.subscribers[username] == nil {
+		broker.subscribers[username] = map[chan Event]struct{}{}
+	}
+	broker.subscribers[username][ch] = struct{}{}
+
+	var once sync.Once
+	cancel = func() {
+		once.Do(func() {
+			broker.mu.Lock()
+			defer broker.mu.Unlock()
+
+			// already closed by Close
+			if _, ok := broker.subscribers[username][ch]; !ok {
+				return
+			}
+
+			delete(broker.subscribers[username], ch)
+			if len(broker.subscribers[username]) == 0 {
+				delete(broker.subscribers, username)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, cancel
+}
+
+// Closes the channels of all the subscribers, so their streams end, and of
+// the ones subscribing later
+func (broker *Broker) Close() {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	broker.closed = true
+	for username, subscribers := range broker.subscribers {
+		for ch := range subscribers {
+			close(ch)
+		}
+		delete(broker.subscribers, username)
+	}
+}
+
+// Sends event to the subscribers of its user. Subscribers whose buffer is
+// full miss the event rather than blocking the others.
+func (broker *Broker) Publish(event Event) {
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+
+	for ch := range broker.subscribers[event.Username] {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Str("type", event.Type).Str("username", event.Username).Msg("dropped event for a slow subscriber")
+		}
+	}
+}
+
+// Publishes the notifications sent on Channel by the database until ctx is
+// done. Every replica listens, so clients get the changes made through any of
+// them. The listener reconnects on its own when the connection is lost.
+func (broker *Broker) ListenPostgres(ctx context.Context, dataSource string) error {
+	listener := pq.NewListener(dataSource, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Error().Err(err).Msg("event listener")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(Channel); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case n := <-listener.Notify:
+			// nil after a reconnection, notifications sent meanwhile are lost
+			if n == nil {
+				continue
+			}
+
+			event, err := parseNotification(n.Extra)
+			if err != nil {
+				log.Error().Err(err).Msg("cannot parse notification")
+				continue
+			}
+			broker.Publish(event)
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+
+	phone, cancelPhone := broker.Subscribe("user01")
+	web, cancelWeb := broker.Subscribe("user01")
+	other, cancelOther := broker.Subscribe("user02")
+	defer cancelWeb()
+	defer cancelOther()
+
+	event := Event{Type: TotalUpdated, Username: "user01", Data: Total{TotalExpenses: 10}}
+	broker.Publish(event)
+
+	require.Equal(t, event, <-phone)
+	require.Equal(t, event, <-web)
+	require.Empty(t, other)
+
+	// cancelled subscribers get no more events and their channel is closed
+	cancelPhone()
+	cancelPhone()
+	broker.Publish(event)
+
+	_, ok := <-phone
+	require.False(t, ok)
+	require.Equal(t, event, <-web)
+}
+
+func TestBrokerSlowSubscriber(t *testing.T) {
+	broker := NewBroker()
+
+	events, cancel := broker.Subscribe("user01")
+	defer cancel()
+
+	// publishing never blocks on a full buffer
+	for i := 0; i < subscriberBuffer+5; i++ {
+		broker.Publish(Event{Type: TotalUpdated, Username: "user01", Data: Total{TotalExpenses: int64(i)}})
+	}
+
+	require.Len(t, events, subscriberBuffer)
+	require.Equal(t, Total{TotalExpenses: 0}, (<-events).Data)
+}
+
+func TestBrokerRemovesUsersWithoutSubscribers(t *testing.T) {
+	broker := NewBroker()
+
+	_, cancel := broker.Subscribe("user01")
+	require.Len(t, broker.subscribers, 1)
+
+	cancel()
+	require.Empty(t, broker.subscribers)
+}
+
+func TestBrokerClose(t *testing.T) {
+	broker := NewBroker()
+
+	events, cancel := broker.Subscribe("user01")
+	broker.Close()
+
+	_, ok := <-events
+	require.False(t, ok)
+	require.Empty(t, broker.subscribers)
+
+	// cancelling after the broker closed the channel is safe
+	cancel()
+
+	late, cancelLate := broker.Subscribe("user01")
+	defer cancelLate()
+	_, ok = <-late
+	require.False(t, ok)
+}
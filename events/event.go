@@ -0,0 +1,107 @@
+package events
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+)
+
+// Channel the database publishes the changes on, see migration 000008
+const Channel = "budget_events"
+
+// Types of the events sent to clients
+const (
+	EntryCreated = "entry.created"
+	EntryUpdated = "entry.updated"
+	EntryDeleted = "entry.deleted"
+	TotalUpdated = "total.updated"
+)
+
+// Event is a change to the data of a user
+type Event struct {
+	Type     string
+	Username string
+	// db.Entry for entry events and Total for total events
+	Data interface{}
+}
+
+// Total is the data of a total.updated event
+type Total struct {
+	TotalExpenses int64 `json:"total_expenses"`
+}
+
+// notification is the payload sent by the triggers
+type notification struct {
+	Type          string    `json:"type"`
+	Username      string    `json:"username"`
+	Entry         *entryRow `json:"entry"`
+	TotalExpenses int64     `json:"total_expenses"`
+}
+
+// entryRow is an entries row as encoded by row_to_json
+type entryRow struct {
+	ID          int32      `json:"id"`
+	Owner       string     `json:"owner"`
+	Name        string     `json:"name"`
+	DueDate     time.Time  `json:"due_date"`
+	Amount      int64      `json:"amount"`
+	Category    *string    `json:"category"`
+	HouseholdID *int32     `json:"household_id"`
+	CreatedBy   *string    `json:"created_by"`
+	DeletedAt   *time.Time `json:"deleted_at"`
+	Version     int32      `json:"version"`
+}
+
+func (row entryRow) entry() db.Entry {
+	entry := db.Entry{
+		ID:      row.ID,
+		Owner:   row.Owner,
+		Name:    row.Name,
+		DueDate: row.DueDate.UTC(),
+		Amount:  row.Amount,
+		Version: row.Version,
+	}
+	if row.Category != nil {
+		entry.Category = sql.NullString{String: *row.Category, Valid: true}
+	}
+	if row.HouseholdID != nil {
+		entry.HouseholdID = sql.NullInt32{Int32: *row.HouseholdID, Valid: true}
+	}
+	if row.CreatedBy != nil {
+		entry.CreatedBy = sql.NullString{String: *row.CreatedBy, Valid: true}
+	}
+	if row.DeletedAt != nil {
+		entry.DeletedAt = sql.NullTime{Time: row.DeletedAt.UTC(), Valid: true}
+	}
+	return entry
+}
+
+// Decodes the payload of a notification on Channel
+func parseNotification(payload string) (Event, error) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return Event{}, err
+	}
+
+	event := Event{
+		Type:     n.Type,
+		Username: n.Username,
+	}
+
+	switch n.Type {
+	case EntryCreated, EntryUpdated, EntryDeleted:
+		if n.Entry == nil {
+			return Event{}, fmt.Errorf("%s notification without an entry", n.Type)
+		}
+		event.Data = n.Entry.entry()
+	case TotalUpdated:
+		event.Data = Total{TotalExpenses: n.TotalExpenses}
+	default:
+		return Event{}, fmt.Errorf("unknown notification type %q", n.Type)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotification(t *testing.T) {
+	dueDate := time.Date(2022, time.December, 11, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		payload string
+		event   Event
+		wantErr bool
+	}{
+		{
+			name:    "EntryCreated",
+			payload: `{"type": "entry.created", "username": "user01", "entry": {"id": 7, "owner": "user01", "name": "rent", "due_date": "2022-12-11T00:00:00+00:00", "amount": 900, "category": "home", "household_id": null, "created_by": null, "deleted_at": null, "version": 1}}`,
+			event: Event{
+				Type:     EntryCreated,
+				Username: "user01",
+				Data: db.Entry{
+					ID:       7,
+					Owner:    "user01",
+					Name:     "rent",
+					DueDate:  dueDate,
+					Amount:   900,
+					Category: sql.NullString{String: "home", Valid: true},
+					Version:  1,
+				},
+			},
+		},
+		{
+			name:    "EntryDeleted",
+			payload: `{"type": "entry.deleted", "username": "user01", "entry": {"id": 7, "owner": "user01", "name": "rent", "due_date": "2022-12-11T00:00:00+00:00", "amount": 900, "category": null, "household_id": 3, "created_by": "user02", "deleted_at": "2022-12-12T10:00:00+00:00", "version": 2}}`,
+			event: Event{
+				Type:     EntryDeleted,
+				Username: "user01",
+				Data: db.Entry{
+					ID:          7,
+					Owner:       "user01",
+					Name:        "rent",
+					DueDate:     dueDate,
+					Amount:      900,
+					HouseholdID: sql.NullInt32{Int32: 3, Valid: true},
+					CreatedBy:   sql.NullString{String: "user02", Valid: true},
+					DeletedAt:   sql.NullTime{Time: time.Date(2022, time.December, 12, 10, 0, 0, 0, time.UTC), Valid: true},
+					Version:     2,
+				},
+			},
+		},
+		{
+			name:    "TotalUpdated",
+			payload: `{"type": "total.updated", "username": "user01", "total_expenses": 1500}`,
+			event: Event{
+				Type:     TotalUpdated,
+				Username: "user01",
+				Data:     Total{TotalExpenses: 1500},
+			},
+		},
+		{
+			name:    "MissingEntry",
+			payload: `{"type": "entry.updated", "username": "user01"}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnknownType",
+			payload: `{"type": "user.created", "username": "user01"}`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidJSON",
+			payload: `{"type": `,
+			wantErr: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			event, err := parseNotification(tc.payload)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.event, event)
+		})
+	}
+}
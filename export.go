@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+// Audit events read per query while exporting
+const exportPageSize = 100
+
+// userExport is everything stored about a user, except the password hash
+type userExport struct {
+	ExportedAt     time.Time       `json:"exported_at"`
+	User           exportedUser    `json:"user"`
+	Entries        []db.Entry      `json:"entries"`
+	DeletedEntries []db.Entry      `json:"deleted_entries"`
+	Households     []db.Household  `json:"households"`
+	AuditEvents    []db.AuditEvent `json:"audit_events"`
+}
+
+type exportedUser struct {
+	Username          string    `json:"username"`
+	FullName          string    `json:"full_name"`
+	Email             string    `json:"email"`
+	TotalExpenses     int64     `json:"total_expenses"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// Writes the data of a user as JSON, to answer data access requests
+func runExportUserCommand(ctx context.Context, config util.Config, args []string) error {
+	flags := flag.NewFlagSet("export-user", flag.ContinueOnError)
+	username := flags.String("username", "", "user to export")
+	output := flags.String("output", "", "file to write, standard output when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	export, err := exportUser(ctx, store, *username)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+func exportUser(ctx context.Context, store db.Store, username string) (userExport, error) {
+	var export userExport
+
+	user, err := store.GetUser(ctx, username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return export, fmt.Errorf("user %s not found", username)
+	}
+	if err != nil {
+		return export, err
+	}
+
+	export.ExportedAt = time.Now().UTC()
+	export.User = exportedUser{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		TotalExpenses:     user.TotalExpenses,
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreatedAt:         user.CreatedAt,
+	}
+
+	export.Entries, err = store.GetEntries(ctx, username)
+	if err != nil {
+		return export, err
+	}
+
+	export.DeletedEntries, err = store.ListDeletedEntries(ctx, username)
+	if err != nil {
+		return export, err
+	}
+
+	export.Households, err = store.ListHouseholds(ctx, username)
+	if err != nil {
+		return export, err
+	}
+
+	export.AuditEvents = []db.AuditEvent{}
+	for offset := int32(0); ; offset += exportPageSize {
+		events, err := store.ListAuditEvents(ctx, db.ListAuditEventsParams{
+			Actor:  username,
+			Limit:  exportPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return export, err
+		}
+
+		export.AuditEvents = append(export.AuditEvents, events...)
+		if len(events) < exportPageSize {
+			break
+		}
+	}
+
+	return export, nil
+}
@@ -0,0 +1,99 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"regexp"
+	"strings"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	requestIDHeaderKey      = "x-request-id"
+	forwardedForHeaderKey   = "x-forwarded-for"
+)
+
+type authorizationPayloadKey struct{}
+
+type auditMetadataKey struct{}
+
+// Request IDs sent by clients are kept when they look like one
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// Methods that can be called without an access token
+var publicMethods = map[string]bool{
+	"/pb.UserService/CreateUser": true,
+	"/pb.UserService/LoginUser":  true,
+}
+
+// Verifies the bearer token of every call to a method that isn't public
+// and stores its payload in the context, the equivalent of authMiddleware
+func authInterceptor(tokenMaker token.Maker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		payload, err := authorizeUser(ctx, tokenMaker)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return handler(context.WithValue(ctx, authorizationPayloadKey{}, payload), req)
+	}
+}
+
+func authorizeUser(ctx context.Context, tokenMaker token.Maker) (*token.Payload, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(authorizationHeaderKey)
+	if len(values) == 0 {
+		return nil, errMissingAuthorization
+	}
+
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 || strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return nil, errMissingAuthorization
+	}
+
+	return tokenMaker.VerifyToken(fields[1])
+}
+
+// Returns the payload stored by authInterceptor
+func authPayload(ctx context.Context) *token.Payload {
+	return ctx.Value(authorizationPayloadKey{}).(*token.Payload)
+}
+
+// Attaches the client IP and request ID to the context for the audit log and
+// the other interceptors, like auditMiddleware. Request IDs sent by clients are
+// kept when they look like one, and the ID is echoed in the response header.
+func auditInterceptor(trustedProxies []*net.IPNet) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		metadataValues := db.AuditMetadata{ClientIP: clientIP(ctx, trustedProxies)}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get(requestIDHeaderKey); len(values) > 0 && validRequestID.MatchString(values[0]) {
+			metadataValues.RequestID = values[0]
+		} else {
+			metadataValues.RequestID = uuid.NewString()
+		}
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDHeaderKey, metadataValues.RequestID))
+
+		ctx = context.WithValue(ctx, auditMetadataKey{}, metadataValues)
+		return handler(db.WithAuditMetadata(ctx, metadataValues), req)
+	}
+}
+
+// Returns the client IP and request ID stored by auditInterceptor
+func auditMetadata(ctx context.Context) db.AuditMetadata {
+	metadataValues, _ := ctx.Value(auditMetadataKey{}).(db.AuditMetadata)
+	return metadataValues
+}
@@ -0,0 +1,27 @@
+package gapi
+
+import (
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+)
+
+func convertUser(user db.User) *pb.User {
+	return &pb.User{
+		Username:      user.Username,
+		FullName:      user.FullName,
+		Email:         user.Email,
+		TotalExpenses: user.TotalExpenses,
+	}
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:       entry.ID,
+		Owner:    entry.Owner,
+		Name:     entry.Name,
+		DueDate:  entry.DueDate.Format(dateLayout),
+		Amount:   entry.Amount,
+		Category: entry.Category.String,
+		Version:  entry.Version,
+	}
+}
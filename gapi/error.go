@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errMissingAuthorization = errors.New("authorization header is not provided")
+
+// Converts a store or token error to a status error with the same meaning as
+// the HTTP status the REST API returns for it. Internal details are not exposed.
+func toStatusError(err error) error {
+	var mismatch *db.VersionMismatchError
+	violation, _ := db.ConstraintViolation(err)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return status.Error(codes.NotFound, "the requested resource was not found")
+	case errors.Is(err, token.ErrExpiredToken), errors.Is(err, token.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.As(err, &mismatch):
+		return versionMismatchError(mismatch.Current)
+	case violation == db.UniqueViolation:
+		return status.Error(codes.AlreadyExists, "the resource already exists")
+	case violation == db.ForeignKeyViolation:
+		return status.Error(codes.FailedPrecondition, "the request refers to a resource that doesn't exist")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// Returns a FailedPrecondition error telling the client the version the entry is at
+func versionMismatchError(current db.Entry) error {
+	statusMismatch := status.Newf(codes.FailedPrecondition, "entry is at version %d", current.Version)
+
+	statusDetails, err := statusMismatch.WithDetails(&errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{
+			Type:        "VERSION",
+			Subject:     fmt.Sprintf("entries/%d", current.ID),
+			Description: strconv.Itoa(int(current.Version)),
+		}},
+	})
+	if err != nil {
+		return statusMismatch.Err()
+	}
+
+	return statusDetails.Err()
+}
+
+func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: err.Error(),
+	}
+}
+
+// Returns an InvalidArgument error carrying the violations as BadRequest details
+func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) error {
+	badRequest := &errdetails.BadRequest{FieldViolations: violations}
+	statusInvalid := status.New(codes.InvalidArgument, "invalid parameters")
+
+	statusDetails, err := statusInvalid.WithDetails(badRequest)
+	if err != nil {
+		return statusInvalid.Err()
+	}
+
+	return statusDetails.Err()
+}
@@ -0,0 +1,124 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Size of the in-process buffers between the gateway and the gRPC server
+const gatewayBufferSize = 1024 * 1024
+
+// GatewayListener carries the calls of the HTTP gateway to the gRPC server in
+// process. Only the gateway can connect through it, so the client address it
+// forwards is trusted on these connections and on no others.
+type GatewayListener struct {
+	*bufconn.Listener
+}
+
+func NewGatewayListener() *GatewayListener {
+	return &GatewayListener{Listener: bufconn.Listen(gatewayBufferSize)}
+}
+
+// Accept returns the next connection of the gateway, which its calls are told apart by
+func (listener *GatewayListener) Accept() (net.Conn, error) {
+	conn, err := listener.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return gatewayConn{Conn: conn}, nil
+}
+
+// DialContext connects the gateway to the gRPC server, whatever the address
+func (listener *GatewayListener) DialContext(ctx context.Context, _ string) (net.Conn, error) {
+	return listener.Listener.DialContext(ctx)
+}
+
+type gatewayConn struct {
+	net.Conn
+}
+
+func (gatewayConn) RemoteAddr() net.Addr { return gatewayAddr{} }
+
+type gatewayAddr struct{}
+
+func (gatewayAddr) Network() string { return "gateway" }
+func (gatewayAddr) String() string  { return "gateway" }
+
+// Parses the trusted proxies, given as IPs or CIDRs like the HTTP server's
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", proxy)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			proxy = fmt.Sprintf("%s/%d", proxy, bits)
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the IP of the caller. It's the peer address, unless the call came
+// through the gateway: then it's the address the gateway was called from, or
+// the one forwarded by the trusted proxies in front of it. Clients can't pick
+// the IP they are seen with by sending x-forwarded-for.
+func clientIP(ctx context.Context, trustedProxies []*net.IPNet) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if _, ok := p.Addr.(gatewayAddr); !ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return ""
+		}
+		return host
+	}
+
+	// the gateway appends the address it was called from, the values before it
+	// are only as good as the proxies that added them
+	md, _ := metadata.FromIncomingContext(ctx)
+	addresses := strings.Split(strings.Join(md.Get(forwardedForHeaderKey), ","), ",")
+	for i := len(addresses) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(addresses[i]))
+		if ip == nil {
+			return ""
+		}
+		if i == 0 || !isTrustedProxy(ip, trustedProxies) {
+			return ip.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	trustedProxies, err := parseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.1 ", ""})
+	require.NoError(t, err)
+
+	directPeer := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 1234}}
+	gatewayPeer := &peer.Peer{Addr: gatewayAddr{}}
+
+	testCases := []struct {
+		name         string
+		peer         *peer.Peer
+		forwardedFor []string
+		clientIP     string
+	}{
+		{
+			name:     "Direct",
+			peer:     directPeer,
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:         "DirectSpoofed",
+			peer:         directPeer,
+			forwardedFor: []string{"198.51.100.1"},
+			clientIP:     "203.0.113.7",
+		},
+		{
+			name:         "Gateway",
+			peer:         gatewayPeer,
+			forwardedFor: []string{"198.51.100.1"},
+			clientIP:     "198.51.100.1",
+		},
+		{
+			name:         "GatewaySpoofed",
+			peer:         gatewayPeer,
+			forwardedFor: []string{"198.51.100.2, 198.51.100.1"},
+			clientIP:     "198.51.100.1",
+		},
+		{
+			name:         "GatewayBehindTrustedProxies",
+			peer:         gatewayPeer,
+			forwardedFor: []string{"198.51.100.2, 198.51.100.1, 192.168.1.1", "10.0.0.1"},
+			clientIP:     "198.51.100.1",
+		},
+		{
+			name:         "GatewayOnlyTrustedProxies",
+			peer:         gatewayPeer,
+			forwardedFor: []string{"10.0.0.2, 10.0.0.1"},
+			clientIP:     "10.0.0.2",
+		},
+		{
+			name:         "GatewayInvalidAddress",
+			peer:         gatewayPeer,
+			forwardedFor: []string{"not an ip"},
+			clientIP:     "",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			md := metadata.MD{}
+			for _, value := range tc.forwardedFor {
+				md.Append(forwardedForHeaderKey, value)
+			}
+			ctx := metadata.NewIncomingContext(peer.NewContext(context.Background(), tc.peer), md)
+
+			require.Equal(t, tc.clientIP, clientIP(ctx, trustedProxies))
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	_, err := parseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	_, err = parseTrustedProxies([]string{"proxy.local"})
+	require.Error(t, err)
+
+	networks, err := parseTrustedProxies([]string{"::1"})
+	require.NoError(t, err)
+	require.True(t, isTrustedProxy(net.ParseIP("::1"), networks))
+	require.False(t, isTrustedProxy(net.ParseIP("::2"), networks))
+}
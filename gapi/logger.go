@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Gives every call a logger that adds its request ID and trace ID to every
+// line, and logs a line once the call is handled, like loggerMiddleware
+func loggerInterceptor(logger zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		metadataValues := auditMetadata(ctx)
+
+		logContext := logger.With().Str("request_id", metadataValues.RequestID)
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			logContext = logContext.Str("trace_id", span.TraceID().String())
+		}
+		callLogger := logContext.Logger()
+
+		rsp, err := handler(callLogger.WithContext(ctx), req)
+
+		code := status.Code(err)
+		event := callLogger.Info()
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			event = callLogger.Error().Err(err)
+		default:
+			event = callLogger.Warn().Err(err)
+		}
+
+		event.
+			Str("method", info.FullMethod).
+			Str("code", code.String()).
+			Dur("duration", time.Since(start)).
+			Str("client_ip", metadataValues.ClientIP).
+			Msg("handled call")
+		return rsp, err
+	}
+}
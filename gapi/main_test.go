@@ -0,0 +1,91 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+	}
+
+	server, err := NewServer(config, store, util.NewMemoryRateLimiter(), zerolog.Nop())
+	require.NoError(t, err)
+
+	return server
+}
+
+// Serves the test server in memory and returns a connection to it
+func newTestConn(t *testing.T, server *Server) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := server.GRPCServer()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// Serves the test server to a connection that goes through the gateway listener,
+// like the calls of the HTTP gateway
+func newGatewayTestConn(t *testing.T, server *Server) *grpc.ClientConn {
+	listener := NewGatewayListener()
+	grpcServer := server.GRPCServer()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("gateway",
+		grpc.WithContextDialer(listener.DialContext),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func newContextWithBearerToken(t *testing.T, tokenMaker token.Maker, username string, duration time.Duration) context.Context {
+	accessToken, err := tokenMaker.CreateToken(username, duration)
+	require.NoError(t, err)
+
+	bearerToken := fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(authorizationHeaderKey, bearerToken))
+}
+
+// Context of a call that went through authInterceptor
+func newAuthorizedContext(username string) context.Context {
+	payload := &token.Payload{Username: username}
+	return context.WithValue(context.Background(), authorizationPayloadKey{}, payload)
+}
+
+func randomUser() db.User {
+	return db.User{
+		Username:      util.RandomString(6),
+		FullName:      util.RandomFullName(),
+		Email:         util.RandomEmail(),
+		TotalExpenses: util.RandomMoney(),
+	}
+}
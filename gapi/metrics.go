@@ -0,0 +1,21 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Counts the calls and observes their latency by method and status code
+func metricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+
+	code := status.Code(err).String()
+	metrics.GRPCRequests.WithLabelValues(info.FullMethod, code).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+	return rsp, err
+}
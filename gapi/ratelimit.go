@@ -0,0 +1,79 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	rateLimitLimitHeaderKey     = "ratelimit-limit"
+	rateLimitRemainingHeaderKey = "ratelimit-remaining"
+	rateLimitResetHeaderKey     = "ratelimit-reset"
+	retryAfterHeaderKey         = "retry-after"
+)
+
+var errRateLimited = errors.New("too many requests, retry later")
+
+// Methods with the stricter quota of the routes that guess passwords. Every
+// other method has the default quota.
+var authRateLimitedMethods = map[string]bool{
+	"/pb.UserService/LoginUser": true,
+}
+
+// Rejects calls over their quota with ResourceExhausted, like the rateLimit
+// middleware. The quotas have the names of the HTTP ones and the buckets the
+// same keys, so a client has one quota whichever API it calls.
+func (server *Server) rateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	name, limit := "default", util.RateLimit{
+		Requests: server.config.RateLimitRequests,
+		Period:   server.config.RateLimitPeriod,
+	}
+	if authRateLimitedMethods[info.FullMethod] {
+		name, limit = "auth", util.RateLimit{
+			Requests: server.config.AuthRateLimitRequests,
+			Period:   server.config.AuthRateLimitPeriod,
+		}
+	}
+	if !limit.Enabled() {
+		return handler(ctx, req)
+	}
+
+	key := name + ":ip:" + auditMetadata(ctx).ClientIP
+	if payload, ok := ctx.Value(authorizationPayloadKey{}).(*token.Payload); ok {
+		key = name + ":user:" + payload.Username
+	}
+
+	result, err := server.rateLimiter.Take(ctx, key, limit)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	header := metadata.Pairs(
+		rateLimitLimitHeaderKey, strconv.Itoa(result.Limit),
+		rateLimitRemainingHeaderKey, strconv.Itoa(result.Remaining),
+		rateLimitResetHeaderKey, strconv.Itoa(seconds(result.Reset)),
+	)
+	if !result.Allowed {
+		header.Set(retryAfterHeaderKey, strconv.Itoa(seconds(result.RetryAfter)))
+		grpc.SetHeader(ctx, header)
+		return nil, status.Error(codes.ResourceExhausted, errRateLimited.Error())
+	}
+
+	grpc.SetHeader(ctx, header)
+	return handler(ctx, req)
+}
+
+// Rounds d up to whole seconds, as the rate limit headers expect
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,75 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := util.Config{
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		AuthRateLimitRequests: 1,
+		AuthRateLimitPeriod:   time.Minute,
+	}
+	limiter := util.NewMemoryRateLimiter()
+	server, err := NewServer(config, mockdb.NewMockStore(ctrl), limiter, zerolog.Nop())
+	require.NoError(t, err)
+	client := pb.NewUserServiceClient(newTestConn(t, server))
+
+	var header metadata.MD
+	_, err = client.LoginUser(context.Background(), &pb.LoginUserRequest{}, grpc.Header(&header))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"1"}, header.Get(rateLimitLimitHeaderKey))
+	require.Equal(t, []string{"0"}, header.Get(rateLimitRemainingHeaderKey))
+
+	_, err = client.LoginUser(context.Background(), &pb.LoginUserRequest{}, grpc.Header(&header))
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"60"}, header.Get(retryAfterHeaderKey))
+}
+
+func TestRateLimitSharedWithHTTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := util.Config{
+		TokenSymmetricKey:     util.RandomString(32),
+		AccessTokenDuration:   time.Minute,
+		AuthRateLimitRequests: 1,
+		AuthRateLimitPeriod:   time.Minute,
+	}
+	limiter := util.NewMemoryRateLimiter()
+	server, err := NewServer(config, mockdb.NewMockStore(ctrl), limiter, zerolog.Nop())
+	require.NoError(t, err)
+	client := pb.NewUserServiceClient(newGatewayTestConn(t, server))
+
+	// the HTTP server took the last token of the client's login quota
+	limit := util.RateLimit{Requests: config.AuthRateLimitRequests, Period: config.AuthRateLimitPeriod}
+	result, err := limiter.Take(context.Background(), "auth:ip:203.0.113.7", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), forwardedForHeaderKey, "203.0.113.7")
+	_, err = client.LoginUser(ctx, &pb.LoginUserRequest{})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// other clients have their own quota
+	ctx = metadata.AppendToOutgoingContext(context.Background(), forwardedForHeaderKey, "203.0.113.8")
+	_, err = client.LoginUser(ctx, &pb.LoginUserRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,22 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+)
+
+func (server *Server) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := server.store.GetCategories(ctx, authPayload(ctx).Username)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	rsp := &pb.ListCategoriesResponse{Categories: []string{}}
+	for _, category := range categories {
+		if category.Valid {
+			rsp.Categories = append(rsp.Categories, category.String)
+		}
+	}
+	return rsp, nil
+}
@@ -0,0 +1,145 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) CreateEntry(ctx context.Context, req *pb.CreateEntryRequest) (*pb.CreateEntryResponse, error) {
+	if violations := validateEntryFields(req.GetName(), req.GetDueDate(), req.GetAmount(), req.GetCategory()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	dueDate, _ := parseDate(req.GetDueDate())
+	arg := db.AddEntryTxParams{
+		Username: authPayload(ctx).Username,
+		Name:     req.GetName(),
+		DueDate:  dueDate,
+		Amount:   req.GetAmount(),
+		Category: req.GetCategory(),
+	}
+
+	result, err := server.store.AddEntryTx(ctx, arg)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	rsp := &pb.CreateEntryResponse{
+		Entry: convertEntry(result.Entry),
+		User:  convertUser(result.User),
+	}
+	return rsp, nil
+}
+
+func validateEntryFields(name string, dueDate string, amount int64, category string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateEntryName(name); err != nil {
+		violations = append(violations, fieldViolation("name", err))
+	}
+	if _, err := parseDate(dueDate); err != nil {
+		violations = append(violations, fieldViolation("due_date", err))
+	}
+	if err := validateAmount(amount); err != nil {
+		violations = append(violations, fieldViolation("amount", err))
+	}
+	if err := validateCategory(category); err != nil {
+		violations = append(violations, fieldViolation("category", err))
+	}
+	return violations
+}
+
+func (server *Server) GetEntry(ctx context.Context, req *pb.GetEntryRequest) (*pb.GetEntryResponse, error) {
+	if err := validateID(req.GetId()); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("id", err)})
+	}
+
+	entry, err := server.store.GetEntry(ctx, db.GetEntryParams{
+		Owner: authPayload(ctx).Username,
+		ID:    req.GetId(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.GetEntryResponse{Entry: convertEntry(entry)}, nil
+}
+
+func (server *Server) ListEntries(ctx context.Context, req *pb.ListEntriesRequest) (*pb.ListEntriesResponse, error) {
+	entries, err := server.store.GetEntries(ctx, authPayload(ctx).Username)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	rsp := &pb.ListEntriesResponse{Entries: make([]*pb.Entry, len(entries))}
+	for i, entry := range entries {
+		rsp.Entries[i] = convertEntry(entry)
+	}
+	return rsp, nil
+}
+
+func (server *Server) UpdateEntry(ctx context.Context, req *pb.UpdateEntryRequest) (*pb.UpdateEntryResponse, error) {
+	violations := validateEntryFields(req.GetName(), req.GetDueDate(), req.GetAmount(), req.GetCategory())
+	if err := validateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if err := validateVersion(req.GetVersion()); err != nil {
+		violations = append(violations, fieldViolation("version", err))
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	dueDate, _ := parseDate(req.GetDueDate())
+	arg := db.UpdateEntryTxParams{
+		Username: authPayload(ctx).Username,
+		ID:       req.GetId(),
+		Name:     req.GetName(),
+		DueDate:  dueDate,
+		Amount:   req.GetAmount(),
+		Category: req.GetCategory(),
+		Version:  req.GetVersion(),
+	}
+
+	result, err := server.store.UpdateEntryTx(ctx, arg)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	rsp := &pb.UpdateEntryResponse{
+		Entry: convertEntry(result.Entry),
+		User:  convertUser(result.User),
+	}
+	return rsp, nil
+}
+
+func (server *Server) DeleteEntry(ctx context.Context, req *pb.DeleteEntryRequest) (*pb.DeleteEntryResponse, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	if err := validateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if err := validateVersion(req.GetVersion()); err != nil {
+		violations = append(violations, fieldViolation("version", err))
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	if err := requireVersion(req.GetVersion()); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.DeleteEntryTx(ctx, db.DeleteEntryTxParams{
+		Username: authPayload(ctx).Username,
+		ID:       req.GetId(),
+		Version:  req.GetVersion(),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &pb.DeleteEntryResponse{User: convertUser(result.User)}, nil
+}
@@ -0,0 +1,219 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateEntry(t *testing.T) {
+	user := randomUser()
+	dueDate := time.Date(2022, time.December, 11, 0, 0, 0, 0, time.UTC)
+	entry := db.Entry{
+		ID:       7,
+		Owner:    user.Username,
+		Name:     "rent",
+		DueDate:  dueDate,
+		Amount:   900,
+		Category: sql.NullString{String: "home", Valid: true},
+		Version:  3,
+	}
+
+	req := &pb.UpdateEntryRequest{
+		Id:       entry.ID,
+		Name:     entry.Name,
+		DueDate:  "2022-12-11",
+		Amount:   entry.Amount,
+		Category: entry.Category.String,
+		Version:  2,
+	}
+
+	testCases := []struct {
+		name       string
+		req        *pb.UpdateEntryRequest
+		buildStubs func(store *mockdb.MockStore)
+		code       codes.Code
+	}{
+		{
+			name: "OK",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpdateEntryTxParams{
+					Username: user.Username,
+					ID:       entry.ID,
+					Name:     entry.Name,
+					DueDate:  dueDate,
+					Amount:   entry.Amount,
+					Category: entry.Category.String,
+					Version:  2,
+				}
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateEntryTxResult{Entry: entry, User: user}, nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name: "InvalidDate",
+			req: &pb.UpdateEntryRequest{
+				Id:      entry.ID,
+				Name:    entry.Name,
+				DueDate: "2022-14-11",
+				Amount:  entry.Amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "MissingVersion",
+			req: &pb.UpdateEntryRequest{
+				Id:      entry.ID,
+				Name:    entry.Name,
+				DueDate: "2022-12-11",
+				Amount:  entry.Amount,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "VersionMismatch",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateEntryTxResult{}, &db.VersionMismatchError{Current: entry})
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "NotFound",
+			req:  req,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateEntryTxResult{}, sql.ErrNoRows)
+			},
+			code: codes.NotFound,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			rsp, err := server.UpdateEntry(newAuthorizedContext(user.Username), tc.req)
+			require.Equal(t, tc.code, status.Code(err))
+			if tc.code == codes.OK {
+				require.Equal(t, "2022-12-11", rsp.GetEntry().GetDueDate())
+				require.Equal(t, "home", rsp.GetEntry().GetCategory())
+				require.Equal(t, int32(3), rsp.GetEntry().GetVersion())
+			}
+		})
+	}
+}
+
+func TestDeleteEntry(t *testing.T) {
+	user := randomUser()
+	current := db.Entry{ID: 7, Owner: user.Username, Version: 3}
+
+	testCases := []struct {
+		name       string
+		req        *pb.DeleteEntryRequest
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.DeleteEntryRequest{Id: current.ID, Version: 3},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.DeleteEntryTxParams{
+					Username: user.Username,
+					ID:       current.ID,
+					Version:  3,
+				}
+				store.EXPECT().
+					DeleteEntryTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.DeleteEntryTxResult{User: user}, nil)
+			},
+			check: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "MissingVersion",
+			req:  &pb.DeleteEntryRequest{Id: current.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteEntryTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name: "VersionMismatch",
+			req:  &pb.DeleteEntryRequest{Id: current.ID, Version: 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeleteEntryTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DeleteEntryTxResult{}, &db.VersionMismatchError{Current: current})
+			},
+			check: func(t *testing.T, err error) {
+				st := status.Convert(err)
+				require.Equal(t, codes.FailedPrecondition, st.Code())
+				require.Contains(t, st.Message(), "version 3")
+
+				require.Len(t, st.Details(), 1)
+				failure, ok := st.Details()[0].(*errdetails.PreconditionFailure)
+				require.True(t, ok)
+				require.Equal(t, "3", failure.GetViolations()[0].GetDescription())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			_, err := server.DeleteEntry(newAuthorizedContext(user.Username), tc.req)
+			tc.check(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) GetSummary(ctx context.Context, req *pb.GetSummaryRequest) (*pb.GetSummaryResponse, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	var from, to time.Time
+	var err error
+	if req.GetFrom() != "" {
+		if from, err = parseDate(req.GetFrom()); err != nil {
+			violations = append(violations, fieldViolation("from", err))
+		}
+	}
+	if req.GetTo() != "" {
+		if to, err = parseDate(req.GetTo()); err != nil {
+			violations = append(violations, fieldViolation("to", err))
+		}
+	}
+	if violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	entries, err := server.store.GetEntries(ctx, authPayload(ctx).Username)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	summary := db.SummarizeEntries(entries, from, to)
+	rsp := &pb.GetSummaryResponse{
+		Total:   summary.Total,
+		Entries: int32(summary.Entries),
+	}
+	for _, category := range summary.Categories {
+		rsp.Categories = append(rsp.Categories, &pb.CategoryTotal{
+			Category: category.Category,
+			Total:    category.Total,
+			Entries:  int32(category.Entries),
+		})
+	}
+	for _, month := range summary.Months {
+		rsp.Months = append(rsp.Months, &pb.MonthTotal{
+			Month: month.Month,
+			Total: month.Total,
+		})
+	}
+	return rsp, nil
+}
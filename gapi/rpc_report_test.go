@@ -0,0 +1,61 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomUser()
+	newEntry := func(amount int64, category string, month time.Month) db.Entry {
+		return db.Entry{
+			Owner:    user.Username,
+			Amount:   amount,
+			Category: sql.NullString{String: category, Valid: category != ""},
+			DueDate:  time.Date(2022, month, 15, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	entries := []db.Entry{
+		newEntry(100, "home", time.March),
+		newEntry(50, "food", time.January),
+		newEntry(30, "food", time.March),
+		newEntry(20, "", time.February),
+	}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetEntries(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(entries, nil)
+
+	server := newTestServer(t, store)
+	rsp, err := server.GetSummary(newAuthorizedContext(user.Username), &pb.GetSummaryRequest{From: "2022-02-01"})
+	require.NoError(t, err)
+
+	// the January entry is before from
+	expected := &pb.GetSummaryResponse{
+		Total:   150,
+		Entries: 3,
+		Categories: []*pb.CategoryTotal{
+			{Category: "", Total: 20, Entries: 1},
+			{Category: "food", Total: 30, Entries: 1},
+			{Category: "home", Total: 100, Entries: 1},
+		},
+		Months: []*pb.MonthTotal{
+			{Month: "2022-02", Total: 20},
+			{Month: "2022-03", Total: 130},
+		},
+	}
+	require.True(t, proto.Equal(expected, rsp), "got %v", rsp)
+}
@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"fmt"
+	"net"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"github.com/LeandroEstevez/budgetAppAPI/token"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server serves gRPC requests
+type Server struct {
+	pb.UnimplementedUserServiceServer
+	pb.UnimplementedEntryServiceServer
+	pb.UnimplementedCategoryServiceServer
+	pb.UnimplementedReportServiceServer
+	config         util.Config
+	store          db.Store
+	tokenMaker     token.Maker
+	rateLimiter    util.RateLimiter
+	trustedProxies []*net.IPNet
+	logger         zerolog.Logger
+}
+
+// Creates a new gRPC server, whose quotas are taken from limiter
+func NewServer(config util.Config, store db.Store, limiter util.RateLimiter, logger zerolog.Logger) (*Server, error) {
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	trustedProxies, err := parseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot set the trusted proxies: %w", err)
+	}
+
+	server := &Server{
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		rateLimiter:    limiter,
+		trustedProxies: trustedProxies,
+		logger:         logger,
+	}
+
+	return server, nil
+}
+
+// Creates a grpc.Server with the interceptors and every service registered
+func (server *Server) GRPCServer() *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tracingInterceptor,
+			auditInterceptor(server.trustedProxies),
+			loggerInterceptor(server.logger),
+			metricsInterceptor,
+			authInterceptor(server.tokenMaker),
+			server.rateLimitInterceptor,
+		),
+	)
+
+	pb.RegisterUserServiceServer(grpcServer, server)
+	pb.RegisterEntryServiceServer(grpcServer, server)
+	pb.RegisterCategoryServiceServer(grpcServer, server)
+	pb.RegisterReportServiceServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
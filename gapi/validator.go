@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Usernames and passwords follow the same rules as the signup of the REST API,
+// the other fields are also capped in length
+
+const dateLayout = "2006-01-02"
+
+var isAlphanumeric = regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString
+
+func validateString(value string, minLength int, maxLength int) error {
+	n := len(value)
+	if n < minLength || n > maxLength {
+		return fmt.Errorf("must contain from %d-%d characters", minLength, maxLength)
+	}
+	return nil
+}
+
+func validateUsername(value string) error {
+	if err := validateString(value, 1, 15); err != nil {
+		return err
+	}
+	if !isAlphanumeric(value) {
+		return fmt.Errorf("must contain only letters and numbers")
+	}
+	return nil
+}
+
+func validatePassword(value string) error {
+	return validateString(value, 6, 100)
+}
+
+func validateFullName(value string) error {
+	return validateString(value, 1, 100)
+}
+
+func validateEmail(value string) error {
+	if err := validateString(value, 3, 200); err != nil {
+		return err
+	}
+	if _, err := mail.ParseAddress(value); err != nil {
+		return fmt.Errorf("must be a valid email address")
+	}
+	return nil
+}
+
+func validateEntryName(value string) error {
+	if err := validateString(value, 1, 100); err != nil {
+		return err
+	}
+	if !isAlphanumeric(value) {
+		return fmt.Errorf("must contain only letters and numbers")
+	}
+	return nil
+}
+
+func validateCategory(value string) error {
+	return validateString(value, 0, 15)
+}
+
+func validateID(value int32) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func validateAmount(value int64) error {
+	if value <= 0 {
+		return fmt.Errorf("must be greater than 0")
+	}
+	return nil
+}
+
+func validateVersion(value int32) error {
+	if value < 0 {
+		return fmt.Errorf("must not be negative")
+	}
+	return nil
+}
+
+// Requires the version the client last saw, like the If-Match header of the
+// REST API. Without it two clients could silently overwrite each other's changes.
+func requireVersion(value int32) error {
+	if value == 0 {
+		return status.Error(codes.FailedPrecondition, "the entry version is required")
+	}
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a date formatted as YYYY-MM-DD")
+	}
+	return date, nil
+}
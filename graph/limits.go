@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Number of items a list field is assumed to return when estimating the
+// complexity of a query
+const listSize = 10
+
+// Rejects the operations of doc that exceed the depth or complexity limits.
+// Each field costs one plus the cost of its selection, times listSize for lists.
+func (s *Schema) checkLimits(doc *ast.Document, operationName string) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+
+		depth, complexity := measure(operation.SelectionSet, s.schema.QueryType(), fragments)
+		if depth > s.maxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", depth, s.maxDepth)
+		}
+		if complexity > s.maxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, s.maxComplexity)
+		}
+	}
+
+	return nil
+}
+
+// Returns the depth and the complexity of a selection set on parent. The
+// document must be valid, which rules out unknown fields and fragment cycles.
+func measure(selectionSet *ast.SelectionSet, parent *graphql.Object, fragments map[string]*ast.FragmentDefinition) (depth int, complexity int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+
+	for _, selection := range selectionSet.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			// introspection is not limited
+			if strings.HasPrefix(selection.Name.Value, "__") || parent == nil {
+				continue
+			}
+
+			field := parent.Fields()[selection.Name.Value]
+			object, list := unwrapType(field.Type)
+			d, c = measure(selection.SelectionSet, object, fragments)
+			if list {
+				c *= listSize
+			}
+			d++
+			c++
+		case *ast.InlineFragment:
+			d, c = measure(selection.SelectionSet, parent, fragments)
+		case *ast.FragmentSpread:
+			d, c = measure(fragments[selection.Name.Value].SelectionSet, parent, fragments)
+		}
+
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+
+	return depth, complexity
+}
+
+// Returns the object a field resolves to, nil for scalars, and whether it is a list
+func unwrapType(t graphql.Type) (object *graphql.Object, list bool) {
+	for {
+		switch typ := t.(type) {
+		case *graphql.NonNull:
+			t = typ.OfType
+		case *graphql.List:
+			list = true
+			t = typ.OfType
+		case *graphql.Object:
+			return typ, list
+		default:
+			return nil, list
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+)
+
+// loader batches the keys requested while a level of the query is resolved
+// and fetches them with a single query once the first value is needed.
+// Values are cached for the rest of the request.
+type loader[K comparable, V any] struct {
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	mu      sync.Mutex
+	pending []K
+	results map[K]*loaded[V]
+}
+
+type loaded[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:   fetch,
+		results: map[K]*loaded[V]{},
+	}
+}
+
+// Queues key and returns a thunk that waits for its value. The thunk reports
+// whether the key was found.
+func (l *loader[K, V]) load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = nil
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.results[key] == nil {
+			l.dispatch(ctx)
+		}
+		result := l.results[key]
+		return result.value, result.found, result.err
+	}
+}
+
+// Fetches every pending key, must be called with mu held
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		value, found := values[key]
+		l.results[key] = &loaded[V]{value: value, found: found, err: err}
+	}
+}
+
+// loaders of a single request
+type loaders struct {
+	users            *loader[string, db.User]
+	householdMembers *loader[int32, []db.HouseholdMember]
+	householdEntries *loader[int32, []db.Entry]
+}
+
+func newLoaders(store db.Store) *loaders {
+	return &loaders{
+		users: newLoader(func(ctx context.Context, usernames []string) (map[string]db.User, error) {
+			users, err := store.GetUsers(ctx, usernames)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[string]db.User, len(users))
+			for _, user := range users {
+				values[user.Username] = user
+			}
+			return values, nil
+		}),
+		householdMembers: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.HouseholdMember, error) {
+			members, err := store.ListMembersOfHouseholds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int32][]db.HouseholdMember, len(ids))
+			for _, id := range ids {
+				values[id] = []db.HouseholdMember{}
+			}
+			for _, member := range members {
+				values[member.HouseholdID] = append(values[member.HouseholdID], member)
+			}
+			return values, nil
+		}),
+		householdEntries: newLoader(func(ctx context.Context, ids []int32) (map[int32][]db.Entry, error) {
+			entries, err := store.GetEntriesOfHouseholds(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make(map[int32][]db.Entry, len(ids))
+			for _, id := range ids {
+				values[id] = []db.Entry{}
+			}
+			for _, entry := range entries {
+				id := entry.HouseholdID.Int32
+				values[id] = append(values[id], entry)
+			}
+			return values, nil
+		}),
+	}
+}
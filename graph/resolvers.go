@@ -0,0 +1,225 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/graphql-go/graphql"
+)
+
+var (
+	errInternal     = errors.New("internal server error")
+	errUserNotFound = errors.New("user not found")
+)
+
+type requestKey struct{}
+
+// request is what the resolvers of a single query share
+type request struct {
+	username string
+	store    db.Store
+	loaders  *loaders
+}
+
+func requestFromContext(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+func resolveMe(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	load := r.loaders.users.load(p.Context, r.username)
+
+	return func() (interface{}, error) {
+		user, found, err := load()
+		if err != nil {
+			return nil, errInternal
+		}
+		if !found {
+			return nil, errUserNotFound
+		}
+		return user, nil
+	}, nil
+}
+
+func resolveEntries(p graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	r := requestFromContext(p.Context)
+	entries, err := r.store.GetEntries(p.Context, r.username)
+	if err != nil {
+		return nil, errInternal
+	}
+
+	category, filterCategory := p.Args["category"].(string)
+	filtered := []db.Entry{}
+	for _, entry := range entries {
+		dueDate := entry.DueDate.UTC()
+		if (!from.IsZero() && dueDate.Before(from)) || (!to.IsZero() && dueDate.After(to)) {
+			continue
+		}
+		if filterCategory && entry.Category.String != category {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered, nil
+}
+
+func resolveEntry(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	arg := db.GetEntryParams{
+		Owner: r.username,
+		ID:    int32(p.Args["id"].(int)),
+	}
+
+	entry, err := r.store.GetEntry(p.Context, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errInternal
+	}
+
+	return entry, nil
+}
+
+func resolveCategories(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	categories, err := r.store.GetCategories(p.Context, r.username)
+	if err != nil {
+		return nil, errInternal
+	}
+
+	names := []string{}
+	for _, category := range categories {
+		if category.Valid {
+			names = append(names, category.String)
+		}
+	}
+
+	return names, nil
+}
+
+func resolveSummary(p graphql.ResolveParams) (interface{}, error) {
+	from, to, err := dateRange(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	r := requestFromContext(p.Context)
+	entries, err := r.store.GetEntries(p.Context, r.username)
+	if err != nil {
+		return nil, errInternal
+	}
+
+	return db.SummarizeEntries(entries, from, to), nil
+}
+
+func resolveHouseholds(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	households, err := r.store.ListHouseholds(p.Context, r.username)
+	if err != nil {
+		return nil, errInternal
+	}
+
+	return households, nil
+}
+
+func resolveHouseholdMembers(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	load := r.loaders.householdMembers.load(p.Context, p.Source.(db.Household).ID)
+
+	return func() (interface{}, error) {
+		members, _, err := load()
+		if err != nil {
+			return nil, errInternal
+		}
+		return members, nil
+	}, nil
+}
+
+func resolveHouseholdEntries(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	load := r.loaders.householdEntries.load(p.Context, p.Source.(db.Household).ID)
+
+	return func() (interface{}, error) {
+		entries, _, err := load()
+		if err != nil {
+			return nil, errInternal
+		}
+		return entries, nil
+	}, nil
+}
+
+func resolveHouseholdTotal(p graphql.ResolveParams) (interface{}, error) {
+	r := requestFromContext(p.Context)
+	load := r.loaders.householdEntries.load(p.Context, p.Source.(db.Household).ID)
+
+	return func() (interface{}, error) {
+		entries, _, err := load()
+		if err != nil {
+			return nil, errInternal
+		}
+
+		var total int64
+		for _, entry := range entries {
+			total += entry.Amount
+		}
+		return total, nil
+	}, nil
+}
+
+func resolveEntryOwner(p graphql.ResolveParams) (interface{}, error) {
+	return loadProfile(p, p.Source.(db.Entry).Owner), nil
+}
+
+func resolveEntryCreatedBy(p graphql.ResolveParams) (interface{}, error) {
+	createdBy := p.Source.(db.Entry).CreatedBy
+	if !createdBy.Valid {
+		return nil, nil
+	}
+	return loadProfile(p, createdBy.String), nil
+}
+
+func resolveMemberUser(p graphql.ResolveParams) (interface{}, error) {
+	return loadProfile(p, p.Source.(db.HouseholdMember).Username), nil
+}
+
+// Loads the profile of a user, which is null once the user is deleted
+func loadProfile(p graphql.ResolveParams, username string) func() (interface{}, error) {
+	r := requestFromContext(p.Context)
+	load := r.loaders.users.load(p.Context, username)
+
+	return func() (interface{}, error) {
+		user, found, err := load()
+		if err != nil {
+			return nil, errInternal
+		}
+		if !found {
+			return nil, nil
+		}
+		return user, nil
+	}
+}
+
+// Parses the optional from and to arguments
+func dateRange(args map[string]interface{}) (from time.Time, to time.Time, err error) {
+	if value, ok := args["from"].(string); ok {
+		if from, err = time.Parse(dateLayout, value); err != nil {
+			return from, to, errors.New("from must be a date formatted as YYYY-MM-DD")
+		}
+	}
+	if value, ok := args["to"].(string); ok {
+		if to, err = time.Parse(dateLayout, value); err != nil {
+			return from, to, errors.New("to must be a date formatted as YYYY-MM-DD")
+		}
+	}
+	return from, to, nil
+}
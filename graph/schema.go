@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Limits applied when the config leaves them unset
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 1000
+)
+
+// Schema executes GraphQL queries on behalf of a user
+type Schema struct {
+	schema        graphql.Schema
+	store         db.Store
+	maxDepth      int
+	maxComplexity int
+}
+
+// Creates the schema. Queries deeper than maxDepth or costlier than
+// maxComplexity are rejected before they run, zero picks the default limit.
+func NewSchema(store db.Store, maxDepth int, maxComplexity int) (*Schema, error) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: queryType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if maxComplexity == 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+
+	return &Schema{
+		schema:        schema,
+		store:         store,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}, nil
+}
+
+// Runs a query as username. operationName picks the operation to run when the
+// query holds several of them.
+func (s *Schema) Execute(ctx context.Context, username string, query string, operationName string, variables map[string]interface{}) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if err := s.checkLimits(doc, operationName); err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	ctx = context.WithValue(ctx, requestKey{}, &request{
+		username: username,
+		store:    s.store,
+		loaders:  newLoaders(s.store),
+	})
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: operationName,
+		Args:          variables,
+		Context:       ctx,
+	})
+}
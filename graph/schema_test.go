@@ -0,0 +1,286 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/require"
+)
+
+func randomUser() db.User {
+	return db.User{
+		Username:      util.RandomString(6),
+		FullName:      util.RandomFullName(),
+		Email:         util.RandomEmail(),
+		TotalExpenses: util.RandomMoney(),
+	}
+}
+
+func randomEntry(owner string, dueDate time.Time, category string) db.Entry {
+	return db.Entry{
+		ID:       int32(util.RandomInt(1, 1000)),
+		Owner:    owner,
+		Name:     util.RandomString(6),
+		DueDate:  dueDate,
+		Amount:   util.RandomMoney(),
+		Category: sql.NullString{String: category, Valid: category != ""},
+		Version:  1,
+	}
+}
+
+// Marshals the data of result to JSON to compare it with the expected response
+func requireData(t *testing.T, expected string, result *graphql.Result) {
+	require.Empty(t, result.Errors)
+
+	data, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(data))
+}
+
+func TestDashboardQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := randomUser()
+	march := randomEntry(user.Username, time.Date(2022, time.March, 10, 0, 0, 0, 0, time.UTC), "home")
+	march.Amount = 100
+	april := randomEntry(user.Username, time.Date(2022, time.April, 10, 0, 0, 0, 0, time.UTC), "")
+	april.Amount = 50
+	entries := []db.Entry{march, april}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetEntries(gomock.Any(), gomock.Eq(user.Username)).
+		Times(2).
+		Return(entries, nil)
+	store.EXPECT().
+		GetCategories(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return([]sql.NullString{{String: "home", Valid: true}, {}}, nil)
+	// me and the owners of the entries are loaded together
+	store.EXPECT().
+		GetUsers(gomock.Any(), gomock.Eq([]string{user.Username})).
+		Times(1).
+		Return([]db.User{user}, nil)
+
+	schema, err := NewSchema(store, 0, 0)
+	require.NoError(t, err)
+
+	query := `query Dashboard($from: String) {
+		me { username fullName totalExpenses }
+		entries(from: $from) { name amount category owner { username } }
+		categories
+		summary(from: $from) { total entries categories { category total } months { month total } }
+	}`
+	variables := map[string]interface{}{"from": "2022-04-01"}
+	result := schema.Execute(context.Background(), user.Username, query, "Dashboard", variables)
+
+	requireData(t, `{
+		"me": {"username": "`+user.Username+`", "fullName": "`+user.FullName+`", "totalExpenses": `+fmt.Sprint(user.TotalExpenses)+`},
+		"entries": [{"name": "`+april.Name+`", "amount": 50, "category": null, "owner": {"username": "`+user.Username+`"}}],
+		"categories": ["home"],
+		"summary": {"total": 50, "entries": 1, "categories": [{"category": "", "total": 50}], "months": [{"month": "2022-04", "total": 50}]}
+	}`, result)
+}
+
+func TestHouseholdsQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := randomUser()
+	member := randomUser()
+	households := []db.Household{{ID: 1, Name: "home"}, {ID: 2, Name: "cabin"}}
+
+	rent := randomEntry(owner.Username, time.Now(), "")
+	rent.HouseholdID = sql.NullInt32{Int32: 1, Valid: true}
+	rent.CreatedBy = sql.NullString{String: member.Username, Valid: true}
+	rent.Amount = 900
+	wood := randomEntry(owner.Username, time.Now(), "")
+	wood.HouseholdID = sql.NullInt32{Int32: 2, Valid: true}
+	wood.CreatedBy = sql.NullString{String: "deleted", Valid: true}
+	wood.Amount = 40
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListHouseholds(gomock.Any(), gomock.Eq(owner.Username)).
+		Times(1).
+		Return(households, nil)
+	store.EXPECT().
+		ListMembersOfHouseholds(gomock.Any(), gomock.Eq([]int32{1, 2})).
+		Times(1).
+		Return([]db.HouseholdMember{
+			{HouseholdID: 1, Username: owner.Username, Role: util.OwnerRole},
+			{HouseholdID: 1, Username: member.Username, Role: util.EditorRole},
+			{HouseholdID: 2, Username: owner.Username, Role: util.OwnerRole},
+		}, nil)
+	store.EXPECT().
+		GetEntriesOfHouseholds(gomock.Any(), gomock.Eq([]int32{1, 2})).
+		Times(1).
+		Return([]db.Entry{rent, wood}, nil)
+	store.EXPECT().
+		GetUsers(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, usernames []string) ([]db.User, error) {
+			require.ElementsMatch(t, []string{owner.Username, member.Username, "deleted"}, usernames)
+			return []db.User{owner, member}, nil
+		})
+
+	schema, err := NewSchema(store, 0, 0)
+	require.NoError(t, err)
+
+	query := `{
+		households {
+			id
+			members { role user { username } }
+			entries { amount createdBy { fullName } }
+			total
+		}
+	}`
+	result := schema.Execute(context.Background(), owner.Username, query, "", nil)
+
+	requireData(t, `{"households": [
+		{
+			"id": 1,
+			"members": [
+				{"role": "owner", "user": {"username": "`+owner.Username+`"}},
+				{"role": "editor", "user": {"username": "`+member.Username+`"}}
+			],
+			"entries": [{"amount": 900, "createdBy": {"fullName": "`+member.FullName+`"}}],
+			"total": 900
+		},
+		{
+			"id": 2,
+			"members": [{"role": "owner", "user": {"username": "`+owner.Username+`"}}],
+			"entries": [{"amount": 40, "createdBy": null}],
+			"total": 40
+		}
+	]}`, result)
+}
+
+func TestEntryQuery(t *testing.T) {
+	user := randomUser()
+	entry := randomEntry(user.Username, time.Date(2022, time.December, 11, 0, 0, 0, 0, time.UTC), "food")
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		expected   string
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.GetEntryParams{Owner: user.Username, ID: entry.ID}
+				store.EXPECT().
+					GetEntry(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(entry, nil)
+			},
+			expected: `{"entry": {"dueDate": "2022-12-11", "category": "food"}}`,
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Entry{}, sql.ErrNoRows)
+			},
+			expected: `{"entry": null}`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			schema, err := NewSchema(store, 0, 0)
+			require.NoError(t, err)
+
+			variables := map[string]interface{}{"id": entry.ID}
+			result := schema.Execute(context.Background(), user.Username, `query($id: Int!) { entry(id: $id) { dueDate category } }`, "", variables)
+			requireData(t, tc.expected, result)
+		})
+	}
+}
+
+func TestRejectedQueries(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{
+			name:    "Syntax",
+			query:   `{ me { username }`,
+			message: "Syntax Error",
+		},
+		{
+			name:    "UnknownField",
+			query:   `{ me { password } }`,
+			message: `Cannot query field "password"`,
+		},
+		{
+			name:    "InvalidDate",
+			query:   `{ summary(from: "yesterday") { total } }`,
+			message: "from must be a date formatted as YYYY-MM-DD",
+		},
+		{
+			name:    "TooDeep",
+			query:   `{ households { members { user { username } } } }`,
+			message: "query depth 4 exceeds the limit of 3",
+		},
+		{
+			name:    "TooDeepFragment",
+			query:   `{ households { ...f } } fragment f on Household { entries { owner { username } } }`,
+			message: "query depth 4 exceeds the limit of 3",
+		},
+		{
+			name:    "TooComplex",
+			query:   `{ households { entries { id name amount } } }`,
+			message: "query complexity 311 exceeds the limit of 300",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// rejected queries never reach the store, except for the
+			// arguments checked by the resolvers
+			store := mockdb.NewMockStore(ctrl)
+
+			schema, err := NewSchema(store, 3, 300)
+			require.NoError(t, err)
+
+			result := schema.Execute(context.Background(), "user", tc.query, "", nil)
+			require.Len(t, result.Errors, 1)
+			require.Contains(t, result.Errors[0].Message, tc.message)
+		})
+	}
+}
+
+func TestIntrospectionIsNotLimited(t *testing.T) {
+	schema, err := NewSchema(nil, 1, 1)
+	require.NoError(t, err)
+
+	result := schema.Execute(context.Background(), "user", `{ __schema { queryType { fields { name type { kind ofType { name } } } } } }`, "", nil)
+	require.Empty(t, result.Errors)
+}
@@ -0,0 +1,301 @@
+package graph
+
+import (
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/graphql-go/graphql"
+)
+
+const dateLayout = "2006-01-02"
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "User",
+	Description: "The authenticated user",
+	Fields: graphql.Fields{
+		"username": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.Username }),
+		},
+		"fullName": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.FullName }),
+		},
+		"email": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.Email }),
+		},
+		"totalExpenses": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.Int),
+			Resolve: userField(func(user db.User) interface{} { return user.TotalExpenses }),
+		},
+		"createdAt": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.CreatedAt.Format(time.RFC3339) }),
+		},
+	},
+})
+
+var profileType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Profile",
+	Description: "Public part of another user",
+	Fields: graphql.Fields{
+		"username": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.Username }),
+		},
+		"fullName": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: userField(func(user db.User) interface{} { return user.FullName }),
+		},
+	},
+})
+
+var entryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Entry",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.Int),
+			Resolve: entryField(func(entry db.Entry) interface{} { return entry.ID }),
+		},
+		"name": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.String),
+			Resolve: entryField(func(entry db.Entry) interface{} { return entry.Name }),
+		},
+		"dueDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Formatted as YYYY-MM-DD",
+			Resolve:     entryField(func(entry db.Entry) interface{} { return entry.DueDate.Format(dateLayout) }),
+		},
+		"amount": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.Int),
+			Resolve: entryField(func(entry db.Entry) interface{} { return entry.Amount }),
+		},
+		"category": &graphql.Field{
+			Type: graphql.String,
+			Resolve: entryField(func(entry db.Entry) interface{} {
+				if !entry.Category.Valid {
+					return nil
+				}
+				return entry.Category.String
+			}),
+		},
+		"version": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.Int),
+			Resolve: entryField(func(entry db.Entry) interface{} { return entry.Version }),
+		},
+		"householdId": &graphql.Field{
+			Type: graphql.Int,
+			Resolve: entryField(func(entry db.Entry) interface{} {
+				if !entry.HouseholdID.Valid {
+					return nil
+				}
+				return entry.HouseholdID.Int32
+			}),
+		},
+		"owner": &graphql.Field{
+			Type:    profileType,
+			Resolve: resolveEntryOwner,
+		},
+		"createdBy": &graphql.Field{
+			Type:    profileType,
+			Resolve: resolveEntryCreatedBy,
+		},
+	},
+})
+
+var categoryTotalType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CategoryTotal",
+	Fields: graphql.Fields{
+		"category": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Empty for entries without a category",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.CategoryTotal).Category, nil
+			},
+		},
+		"total": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.CategoryTotal).Total, nil
+			},
+		},
+		"entries": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.CategoryTotal).Entries, nil
+			},
+		},
+	},
+})
+
+var monthTotalType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "MonthTotal",
+	Fields: graphql.Fields{
+		"month": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Formatted as YYYY-MM",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.MonthTotal).Month, nil
+			},
+		},
+		"total": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.MonthTotal).Total, nil
+			},
+		},
+	},
+})
+
+var summaryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Summary",
+	Description: "Totals of the entries due in a date range",
+	Fields: graphql.Fields{
+		"total": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.EntrySummary).Total, nil
+			},
+		},
+		"entries": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.EntrySummary).Entries, nil
+			},
+		},
+		"categories": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryTotalType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.EntrySummary).Categories, nil
+			},
+		},
+		"months": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(monthTotalType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.EntrySummary).Months, nil
+			},
+		},
+	},
+})
+
+var householdMemberType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "HouseholdMember",
+	Fields: graphql.Fields{
+		"user": &graphql.Field{
+			Type:    profileType,
+			Resolve: resolveMemberUser,
+		},
+		"role": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.HouseholdMember).Role, nil
+			},
+		},
+		"joinedAt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.HouseholdMember).CreatedAt.Format(time.RFC3339), nil
+			},
+		},
+	},
+})
+
+var householdType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Household",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.Household).ID, nil
+			},
+		},
+		"name": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.Household).Name, nil
+			},
+		},
+		"createdAt": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(db.Household).CreatedAt.Format(time.RFC3339), nil
+			},
+		},
+		"members": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(householdMemberType))),
+			Resolve: resolveHouseholdMembers,
+		},
+		"entries": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(entryType))),
+			Resolve: resolveHouseholdEntries,
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Sum of the household entries",
+			Resolve:     resolveHouseholdTotal,
+		},
+	},
+})
+
+var dateRangeArgs = graphql.FieldConfigArgument{
+	"from": &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "First due date included, formatted as YYYY-MM-DD",
+	},
+	"to": &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Last due date included, formatted as YYYY-MM-DD",
+	},
+}
+
+var queryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Query",
+	Fields: graphql.Fields{
+		"me": &graphql.Field{
+			Type:    graphql.NewNonNull(userType),
+			Resolve: resolveMe,
+		},
+		"entries": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(entryType))),
+			Args: graphql.FieldConfigArgument{
+				"from":     dateRangeArgs["from"],
+				"to":       dateRangeArgs["to"],
+				"category": &graphql.ArgumentConfig{Type: graphql.String},
+			},
+			Resolve: resolveEntries,
+		},
+		"entry": &graphql.Field{
+			Type: entryType,
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+			},
+			Resolve: resolveEntry,
+		},
+		"categories": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: resolveCategories,
+		},
+		"summary": &graphql.Field{
+			Type:    graphql.NewNonNull(summaryType),
+			Args:    dateRangeArgs,
+			Resolve: resolveSummary,
+		},
+		"households": &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(householdType))),
+			Resolve: resolveHouseholds,
+		},
+	},
+})
+
+func userField(get func(user db.User) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(db.User)), nil
+	}
+}
+
+func entryField(get func(entry db.Entry) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(db.Entry)), nil
+	}
+}
@@ -1,34 +1,123 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
-	"github.com/LeandroEstevez/budgetAppAPI/api"
 	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
 	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/gin-gonic/gin"
 	_ "github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
+// Signals that start a graceful shutdown
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+	syscall.SIGINT,
+}
+
+// command is a subcommand of the binary. Commands get the args following their name.
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, config util.Config, args []string) error
+}
+
+var commands = []command{
+	{"serve", "serve\n\trun the servers and the background workers, the default", runServeCommand},
+	{"migrate", "migrate up [N] | down [N|all] | status | force VERSION\n\tapply or revert the embedded migrations", runMigrateCommand},
+	{"user", "user create | disable | reset-password -username NAME [flags]\n\tmanage accounts, run `user SUBCOMMAND -h` for the flags", runUserCommand},
+	{"recompute-totals", "recompute-totals\n\trecompute the total expenses of every user from their entries, the same as reconcile-totals -fix", runRecomputeTotalsCommand},
+	{"reconcile-totals", "reconcile-totals [-fix]\n\treport the users whose total expenses differ from their entries, -fix corrects them", runReconcileTotalsCommand},
+	{"export-user", "export-user -username NAME [-output FILE]\n\twrite all the data of a user as JSON", runExportUserCommand},
+	{"seed", "seed [-users N] [-entries N] [-password PASSWORD]\n\tcreate demo users with random entries", runSeedCommand},
+}
+
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
-		log.Fatal("cannot load config:", err)
+		log.Fatal().Err(err).Msg("cannot load config")
+	}
+
+	logger, err := util.NewLogger(config.Environment, config.LogLevel)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create logger")
+	}
+	log.Logger = logger
+	zerolog.DefaultContextLogger = &logger
+	if config.Environment == util.ProductionEnvironment {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		os.Exit(2)
 	}
 
-	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
+	err = cmd.run(ctx, config, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatal("cannot connect to db:", err)
+		stop()
+		log.Fatal().Err(err).Msgf("%s failed", name)
 	}
+}
 
-	store := db.NewStore(conn)
-	server, err := api.NewServer(config, store)
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "usage: %s COMMAND [args]\n\ncommands:\n", filepath.Base(os.Args[0]))
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\n", cmd.usage)
+	}
+}
+
+// Opens the database of config for the admin commands, closing conn releases it
+func openStore(ctx context.Context, config util.Config) (store db.Store, conn *sql.DB, err error) {
+	conn, err = db.Open(config.DBDriver, config.DBSource)
 	if err != nil {
-		log.Fatal("cannot create server:", err)
+		return nil, nil, fmt.Errorf("cannot connect to db: %w", err)
 	}
 
-	err = server.Start(config.ServerAddress)
+	err = conn.PingContext(ctx)
 	if err != nil {
-		log.Fatal("cannot start server:", err)
+		conn.Close()
+		return nil, nil, fmt.Errorf("cannot connect to db: %w", err)
 	}
-}
\ No newline at end of file
+
+	return db.NewStoreForDriver(config.DBDriver, conn, log.Logger), conn, nil
+}
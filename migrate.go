@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/LeandroEstevez/budgetAppAPI/db/migration"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+// Runs `migrate up [N]`, `migrate down [N|all]`, `migrate status` or `migrate force V`
+// with the migrations embedded in the binary. up applies all the pending
+// migrations and down reverts one by default.
+func runMigrateCommand(ctx context.Context, config util.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up [N]|down [N|all]|status|force VERSION")
+	}
+
+	migrator, err := migration.New(config.DBDriver, config.DBSource)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		steps, err := migrationSteps(args[1:], -1)
+		if err != nil {
+			return err
+		}
+		return migrator.Up(steps)
+	case "down":
+		steps, err := migrationSteps(args[1:], 1)
+		if err != nil {
+			return err
+		}
+		return migrator.Down(steps)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\npending: %t\n", status.Version, status.Dirty, status.Latest, status.Pending())
+		return nil
+	case "force":
+		if len(args) < 2 {
+			return errors.New("usage: migrate force VERSION")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(version)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
+
+// Parses the optional number of migrations to apply or revert, "all" is -1
+func migrationSteps(args []string, defaultSteps int) (int, error) {
+	if len(args) == 0 {
+		return defaultSteps, nil
+	}
+	if args[0] == "all" {
+		return -1, nil
+	}
+
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", args[0])
+	}
+	return steps, nil
+}
@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+)
+
+// Drift is a user whose stored total expenses differ from the sum of their entries
+type Drift struct {
+	Username      string `json:"username"`
+	TotalExpenses int64  `json:"total_expenses"`
+	EntriesTotal  int64  `json:"entries_total"`
+	Corrected     bool   `json:"corrected"`
+}
+
+// Returns how much the stored total is above the sum of the entries
+func (drift Drift) Difference() int64 {
+	return drift.TotalExpenses - drift.EntriesTotal
+}
+
+// Report is the outcome of a reconciliation
+type Report struct {
+	Drifts    []Drift `json:"drifts"`
+	Corrected int     `json:"corrected"`
+}
+
+// Reconciler checks the denormalized total expenses of the users against their entries
+type Reconciler struct {
+	store db.Store
+	now   func() time.Time
+}
+
+func NewReconciler(store db.Store) *Reconciler {
+	return &Reconciler{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Lists the users whose total expenses drifted from their entries and, if fix
+// is set, sets their totals back to the sum of the entries
+func (reconciler *Reconciler) Run(ctx context.Context, fix bool) (Report, error) {
+	var report Report
+
+	rows, err := reconciler.store.ListTotalExpensesDrift(ctx)
+	if err != nil {
+		return report, err
+	}
+	metrics.TotalsDrifted.Set(float64(len(rows)))
+
+	for _, row := range rows {
+		drift := Drift{
+			Username:      row.Username,
+			TotalExpenses: row.TotalExpenses,
+			EntriesTotal:  row.EntriesTotal,
+		}
+
+		if fix {
+			// the totals are checked again under a lock, entries may have
+			// changed since they were listed
+			result, err := reconciler.store.ReconcileTotalExpensesTx(ctx, row.Username)
+			if errors.Is(err, sql.ErrNoRows) {
+				// the user was deleted in the meantime
+				continue
+			}
+			if err != nil {
+				return report, err
+			}
+
+			drift.TotalExpenses = result.TotalExpenses
+			drift.EntriesTotal = result.EntriesTotal
+			drift.Corrected = result.Corrected
+			if !result.Corrected {
+				continue
+			}
+			report.Corrected++
+			metrics.TotalsCorrected.Inc()
+		}
+
+		report.Drifts = append(report.Drifts, drift)
+	}
+
+	metrics.TotalsLastRun.Set(float64(reconciler.now().Unix()))
+	return report, nil
+}
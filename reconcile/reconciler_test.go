@@ -0,0 +1,154 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomDrift() db.ListTotalExpensesDriftRow {
+	return db.ListTotalExpensesDriftRow{
+		Username:      util.RandomString(6),
+		TotalExpenses: util.RandomMoney() + 1000,
+		EntriesTotal:  util.RandomMoney(),
+	}
+}
+
+func TestRun(t *testing.T) {
+	drift1 := randomDrift()
+	drift2 := randomDrift()
+
+	testCases := []struct {
+		name          string
+		fix           bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, report Report, err error)
+	}{
+		{
+			name: "ReportOnly",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTotalExpensesDrift(gomock.Any()).
+					Times(1).
+					Return([]db.ListTotalExpensesDriftRow{drift1, drift2}, nil)
+				store.EXPECT().
+					ReconcileTotalExpensesTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, report Report, err error) {
+				require.NoError(t, err)
+				require.Zero(t, report.Corrected)
+				require.Len(t, report.Drifts, 2)
+				require.Equal(t, drift1.Username, report.Drifts[0].Username)
+				require.Equal(t, drift1.TotalExpenses-drift1.EntriesTotal, report.Drifts[0].Difference())
+				require.False(t, report.Drifts[0].Corrected)
+			},
+		},
+		{
+			name: "Fix",
+			fix:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTotalExpensesDrift(gomock.Any()).
+					Times(1).
+					Return([]db.ListTotalExpensesDriftRow{drift1, drift2}, nil)
+				store.EXPECT().
+					ReconcileTotalExpensesTx(gomock.Any(), gomock.Eq(drift1.Username)).
+					Times(1).
+					Return(db.ReconcileTotalExpensesTxResult{
+						Username:      drift1.Username,
+						TotalExpenses: drift1.TotalExpenses,
+						EntriesTotal:  drift1.EntriesTotal,
+						Corrected:     true,
+					}, nil)
+				// fixed by another replica in the meantime
+				store.EXPECT().
+					ReconcileTotalExpensesTx(gomock.Any(), gomock.Eq(drift2.Username)).
+					Times(1).
+					Return(db.ReconcileTotalExpensesTxResult{
+						Username:      drift2.Username,
+						TotalExpenses: drift2.EntriesTotal,
+						EntriesTotal:  drift2.EntriesTotal,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, report Report, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, report.Corrected)
+				require.Len(t, report.Drifts, 1)
+				require.Equal(t, drift1.Username, report.Drifts[0].Username)
+				require.True(t, report.Drifts[0].Corrected)
+			},
+		},
+		{
+			name: "FixDeletedUser",
+			fix:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTotalExpensesDrift(gomock.Any()).
+					Times(1).
+					Return([]db.ListTotalExpensesDriftRow{drift1}, nil)
+				store.EXPECT().
+					ReconcileTotalExpensesTx(gomock.Any(), gomock.Eq(drift1.Username)).
+					Times(1).
+					Return(db.ReconcileTotalExpensesTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, report Report, err error) {
+				require.NoError(t, err)
+				require.Zero(t, report.Corrected)
+				require.Empty(t, report.Drifts)
+			},
+		},
+		{
+			name: "NoDrift",
+			fix:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTotalExpensesDrift(gomock.Any()).
+					Times(1).
+					Return([]db.ListTotalExpensesDriftRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, report Report, err error) {
+				require.NoError(t, err)
+				require.Empty(t, report.Drifts)
+			},
+		},
+		{
+			name: "InternalError",
+			fix:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTotalExpensesDrift(gomock.Any()).
+					Times(1).
+					Return([]db.ListTotalExpensesDriftRow{drift1}, nil)
+				store.EXPECT().
+					ReconcileTotalExpensesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReconcileTotalExpensesTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, report Report, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			report, err := NewReconciler(store).Run(context.Background(), tc.fix)
+			tc.checkResponse(t, report, err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+// Categories given to the seeded entries
+var seedCategories = []string{"rent", "groceries", "utilities", "transport", "subscriptions", ""}
+
+// Creates demo users with random entries due in the coming months. Refuses to
+// run in production.
+func runSeedCommand(ctx context.Context, config util.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	users := flags.Int("users", 3, "users to create")
+	entries := flags.Int("entries", 20, "entries per user")
+	password := flags.String("password", "secret", "password of the users")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if config.Environment == util.ProductionEnvironment {
+		return errors.New("seed is disabled in production")
+	}
+
+	hashedPassword, err := util.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i := 0; i < *users; i++ {
+		user, err := store.CreateUserTx(ctx, db.CreateUserParams{
+			Username:       "demo" + util.RandomString(6),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomFullName(),
+			Email:          util.RandomEmail(),
+		})
+		if err != nil {
+			return err
+		}
+
+		for j := 0; j < *entries; j++ {
+			_, err := store.AddEntryTx(ctx, db.AddEntryTxParams{
+				Username: user.Username,
+				Name:     util.RandomString(8),
+				DueDate:  today.AddDate(0, 0, int(util.RandomInt(0, 90))),
+				Amount:   util.RandomInt(1, 1000),
+				Category: seedCategories[util.RandomInt(0, int64(len(seedCategories)-1))],
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("created user %s with %d entries\n", user.Username, *entries)
+	}
+
+	fmt.Printf("password: %s\n", *password)
+	return nil
+}
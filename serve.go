@@ -0,0 +1,427 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LeandroEstevez/budgetAppAPI/api"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/events"
+	"github.com/LeandroEstevez/budgetAppAPI/gapi"
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
+	"github.com/LeandroEstevez/budgetAppAPI/pb"
+	"github.com/LeandroEstevez/budgetAppAPI/reconcile"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/LeandroEstevez/budgetAppAPI/webhook"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Runs the HTTP, gRPC and gateway servers and the background workers until
+// ctx is done, then drains them
+func runServeCommand(ctx context.Context, config util.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments")
+	}
+
+	if config.MigrateOnStart {
+		err := runMigrateCommand(ctx, config, []string{"up"})
+		if err != nil {
+			return err
+		}
+	}
+
+	shutdownTracing, err := util.SetUpTracing(ctx, config)
+	if err != nil {
+		return fmt.Errorf("cannot set up tracing: %w", err)
+	}
+
+	conn, err := db.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		return fmt.Errorf("cannot connect to db: %w", err)
+	}
+
+	err = metrics.RegisterDB(conn, config.DBDriver)
+	if err != nil {
+		return fmt.Errorf("cannot register db metrics: %w", err)
+	}
+
+	waitGroup, ctx := errgroup.WithContext(ctx)
+
+	store := db.NewStoreForDriver(config.DBDriver, conn, log.Logger)
+	broker := events.NewBroker()
+	runWorker(waitGroup, func() { runEventListener(ctx, broker, config.DBDriver, config.DBSource) })
+	runWorker(waitGroup, func() { runTrashPurger(ctx, store, config.TrashRetention, config.TrashPurgeInterval) })
+	runWorker(waitGroup, func() { runIdempotencyKeyCleanup(ctx, store, config.IdempotencyKeyTTL) })
+	runWorker(waitGroup, func() { runWebhookWorker(ctx, store, config.WebhookPollInterval) })
+	runWorker(waitGroup, func() { runTotalsReconciler(ctx, store, config.TotalsReconcileInterval, config.TotalsReconcileFix) })
+	if config.RateLimitStore == api.PostgresRateLimitStore {
+		runWorker(waitGroup, func() { runRateLimitCleanup(ctx, store, config.RateLimitPeriod, config.AuthRateLimitPeriod) })
+	}
+
+	// the HTTP and gRPC servers count their calls against the same quotas
+	limiter, err := api.NewRateLimiter(config, store)
+	if err != nil {
+		return err
+	}
+
+	gatewayListener := gapi.NewGatewayListener()
+	runGrpcServer(ctx, waitGroup, config, store, limiter, gatewayListener)
+	runGatewayServer(ctx, waitGroup, config, gatewayListener)
+	runGinServer(ctx, waitGroup, config, store, broker, limiter)
+	runMetricsServer(ctx, waitGroup, config)
+
+	err = waitGroup.Wait()
+	if err != nil {
+		log.Error().Err(err).Msg("stopped with an error")
+	}
+
+	// the servers and workers are done with the pool by now
+	err = conn.Close()
+	if err != nil {
+		log.Error().Err(err).Msg("cannot close db connections")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	err = shutdownTracing(shutdownCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("cannot flush traces")
+	}
+	log.Info().Msg("stopped")
+	return nil
+}
+
+// Runs a background worker until it returns, which it does once ctx is done
+func runWorker(waitGroup *errgroup.Group, run func()) {
+	waitGroup.Go(func() error {
+		run()
+		return nil
+	})
+}
+
+// Serves HTTP until ctx is done, then waits up to the shutdown timeout for the
+// running requests
+func runGinServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, broker *events.Broker, limiter util.RateLimiter) {
+	server, err := api.NewServer(config, store, broker, limiter, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create server")
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start HTTP server at %s", config.ServerAddress)
+		err := server.Start(config.ServerAddress)
+		if err != nil {
+			return fmt.Errorf("HTTP server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown HTTP server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			return fmt.Errorf("cannot shut down HTTP server: %w", err)
+		}
+
+		log.Info().Msg("HTTP server is stopped")
+		return nil
+	})
+}
+
+// Serves gRPC on its address and to the gateway through gatewayListener
+func runGrpcServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store, limiter util.RateLimiter, gatewayListener *gapi.GatewayListener) {
+	server, err := gapi.NewServer(config, store, limiter, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gRPC server")
+	}
+	grpcServer := server.GRPCServer()
+
+	listener, err := net.Listen("tcp", config.GRPCServerAddress)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gRPC listener")
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			return fmt.Errorf("gRPC server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		err := grpcServer.Serve(gatewayListener)
+		if err != nil {
+			return fmt.Errorf("gRPC server of the gateway failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown gRPC server")
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(config.ShutdownTimeout):
+			log.Warn().Msg("gRPC calls still running after the shutdown timeout")
+			grpcServer.Stop()
+		}
+
+		log.Info().Msg("gRPC server is stopped")
+		return nil
+	})
+}
+
+// Serves the gRPC services over HTTP/JSON by forwarding to the gRPC server in
+// process, so calls go through the same interceptors
+func runGatewayServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, gatewayListener *gapi.GatewayListener) {
+	grpcMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			switch strings.ToLower(key) {
+			case "x-request-id", "traceparent", "tracestate":
+				return key, true
+			// the gateway forwards the client address itself
+			case "grpc-metadata-x-forwarded-for":
+				return "", false
+			}
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+	)
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(gatewayListener.DialContext),
+	}
+	for _, register := range []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		pb.RegisterUserServiceHandlerFromEndpoint,
+		pb.RegisterEntryServiceHandlerFromEndpoint,
+		pb.RegisterCategoryServiceHandlerFromEndpoint,
+		pb.RegisterReportServiceHandlerFromEndpoint,
+	} {
+		err := register(ctx, grpcMux, "gateway", opts)
+		if err != nil {
+			log.Fatal().Err(err).Msg("cannot register gateway handler")
+		}
+	}
+
+	listener, err := net.Listen("tcp", config.GatewayServerAddress)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gateway listener")
+	}
+
+	httpServer := &http.Server{Handler: grpcMux}
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start HTTP gateway server at %s", listener.Addr().String())
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP gateway server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown HTTP gateway server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			return fmt.Errorf("cannot shut down HTTP gateway server: %w", err)
+		}
+
+		log.Info().Msg("HTTP gateway server is stopped")
+		return nil
+	})
+}
+
+// Serves the Prometheus metrics on their own address, so they can be kept off
+// the public network. Disabled when the address is empty.
+func runMetricsServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config) {
+	if config.MetricsServerAddress == "" {
+		log.Info().Msg("metrics server disabled")
+		return
+	}
+
+	listener, err := net.Listen("tcp", config.MetricsServerAddress)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create metrics listener")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	httpServer := &http.Server{Handler: mux}
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start metrics server at %s", listener.Addr().String())
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("metrics server failed: %w", err)
+		}
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			return fmt.Errorf("cannot shut down metrics server: %w", err)
+		}
+
+		log.Info().Msg("metrics server is stopped")
+		return nil
+	})
+}
+
+// Publishes the changes notified by the database to the event streams until ctx is done
+func runEventListener(ctx context.Context, broker *events.Broker, driver string, dataSource string) {
+	if driver != db.PostgresDriver {
+		log.Info().Str("driver", driver).Msg("event streams disabled, the database can't notify changes")
+		return
+	}
+
+	err := broker.ListenPostgres(ctx, dataSource)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Error().Err(err).Msg("cannot listen for events")
+	}
+}
+
+// Periodically deletes the entries and accounts that have been in the trash longer than retention
+func runTrashPurger(ctx context.Context, store db.Store, retention time.Duration, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		log.Info().Msg("trash purge disabled")
+		return
+	}
+
+	runPeriodically(ctx, interval, true, func() {
+		result, err := store.PurgeTrashTx(ctx, time.Now().Add(-retention))
+		if err != nil {
+			log.Error().Err(err).Msg("cannot purge trash")
+			return
+		}
+		if result.Entries > 0 || result.Users > 0 {
+			log.Info().Int64("entries", result.Entries).Int64("users", result.Users).Msg("purged the trash")
+		}
+	})
+}
+
+// Periodically deletes the idempotency keys that are older than ttl
+func runIdempotencyKeyCleanup(ctx context.Context, store db.Store, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	runPeriodically(ctx, ttl, false, func() {
+		_, err := store.DeleteExpiredIdempotencyKeys(ctx, time.Now().Add(-ttl))
+		if err != nil {
+			log.Error().Err(err).Msg("cannot delete expired idempotency keys")
+		}
+	})
+}
+
+// Periodically deletes the rate limit buckets that have been idle long enough to be full again
+func runRateLimitCleanup(ctx context.Context, store db.Store, periods ...time.Duration) {
+	var idle time.Duration
+	for _, period := range periods {
+		if period > idle {
+			idle = period
+		}
+	}
+	if idle <= 0 {
+		return
+	}
+
+	runPeriodically(ctx, idle, false, func() {
+		_, err := store.DeleteIdleRateLimitBuckets(ctx, time.Now().Add(-idle))
+		if err != nil {
+			log.Error().Err(err).Msg("cannot delete idle rate limit buckets")
+		}
+	})
+}
+
+// Sends the queued webhook deliveries until ctx is done, every replica can run a worker
+func runWebhookWorker(ctx context.Context, store db.Store, interval time.Duration) {
+	if interval <= 0 {
+		log.Info().Msg("webhook delivery disabled")
+		return
+	}
+
+	webhook.NewWorker(store).Run(ctx, interval)
+}
+
+// Periodically checks the total expenses of the users against their entries,
+// logging the drifted users and correcting them if fix is set. Replicas may
+// run it at the same time, a correction is only applied once.
+func runTotalsReconciler(ctx context.Context, store db.Store, interval time.Duration, fix bool) {
+	if interval <= 0 {
+		log.Info().Msg("total expenses reconciliation disabled")
+		return
+	}
+
+	reconciler := reconcile.NewReconciler(store)
+	runPeriodically(ctx, interval, false, func() {
+		report, err := reconciler.Run(ctx, fix)
+		for _, drift := range report.Drifts {
+			log.Warn().
+				Str("username", drift.Username).
+				Int64("total_expenses", drift.TotalExpenses).
+				Int64("entries_total", drift.EntriesTotal).
+				Bool("corrected", drift.Corrected).
+				Msg("total expenses drifted from the entries")
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("cannot reconcile total expenses")
+		}
+	})
+}
+
+// Calls run every interval, and once right away if now is set, until ctx is done
+func runPeriodically(ctx context.Context, interval time.Duration, now bool, run func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if now {
+		run()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -2,9 +2,6 @@
 
 set -e
 
-echo "run db migration"
-source "/app/app.env"
-/app/migrate -path /app/migration -database "$DB_SOURCE" -verbose up
-
+# the app applies the migrations embedded in it when MIGRATE_ON_START is set
 echo "start the app"
 exec "$@"
\ No newline at end of file
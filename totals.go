@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/LeandroEstevez/budgetAppAPI/reconcile"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+)
+
+// Reports the users whose total expenses drifted from their entries, and
+// corrects them with -fix
+func runReconcileTotalsCommand(ctx context.Context, config util.Config, args []string) error {
+	flags := flag.NewFlagSet("reconcile-totals", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "set the drifted totals to the sum of the entries")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	report, err := reconcile.NewReconciler(store).Run(ctx, *fix)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Drifts) == 0 {
+		fmt.Println("no drift found")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "USERNAME\tSTORED\tENTRIES\tDIFFERENCE\tCORRECTED")
+	for _, drift := range report.Drifts {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%+d\t%t\n", drift.Username, drift.TotalExpenses, drift.EntriesTotal, drift.Difference(), drift.Corrected)
+	}
+	writer.Flush()
+
+	if *fix {
+		fmt.Printf("corrected the totals of %d users\n", report.Corrected)
+	} else {
+		fmt.Printf("found %d drifted users, run with -fix to correct them\n", len(report.Drifts))
+	}
+	return nil
+}
+
+// Corrects the total expenses of every drifted user, the same as reconcile-totals -fix
+func runRecomputeTotalsCommand(ctx context.Context, config util.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("recompute-totals takes no arguments")
+	}
+	return runReconcileTotalsCommand(ctx, config, []string{"-fix"})
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/go-playground/validator/v10"
+)
+
+// Same rules as the signup request of the API
+type userFlags struct {
+	Username string `flag:"username" validate:"required,alphanum,min=6,max=15"`
+	FullName string `flag:"full-name" validate:"required"`
+	Email    string `flag:"email" validate:"required,email"`
+	Password string `flag:"password" validate:"omitempty,min=6"`
+}
+
+// Checks the flags against their validate tags, naming the flag that fails
+func validateFlags(flags interface{}) error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("flag")
+	})
+
+	var validationErrors validator.ValidationErrors
+	if err := validate.Struct(flags); errors.As(err, &validationErrors) {
+		field := validationErrors[0]
+		if field.Param() != "" {
+			return fmt.Errorf("-%s fails %s=%s", field.Field(), field.Tag(), field.Param())
+		}
+		return fmt.Errorf("-%s fails %s", field.Field(), field.Tag())
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+type passwordFlags struct {
+	Password string `flag:"password" validate:"omitempty,min=6"`
+}
+
+// Runs `user create`, `user disable` or `user reset-password`
+func runUserCommand(ctx context.Context, config util.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: user create|disable|reset-password -username NAME [flags]")
+	}
+
+	switch args[0] {
+	case "create":
+		return createUser(ctx, config, args[1:])
+	case "disable":
+		return disableUser(ctx, config, args[1:])
+	case "reset-password":
+		return resetUserPassword(ctx, config, args[1:])
+	default:
+		return fmt.Errorf("unknown user command %q", args[0])
+	}
+}
+
+// Creates an account, printing the password when it is generated
+func createUser(ctx context.Context, config util.Config, args []string) error {
+	var req userFlags
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	flags.StringVar(&req.Username, "username", "", "username, 6 to 15 letters and digits")
+	flags.StringVar(&req.FullName, "full-name", "", "full name")
+	flags.StringVar(&req.Email, "email", "", "email address")
+	flags.StringVar(&req.Password, "password", "", "password, generated when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := validateFlags(req); err != nil {
+		return err
+	}
+
+	password, generated, err := passwordOrGenerated(req.Password)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	user, err := store.CreateUserTx(ctx, db.CreateUserParams{
+		Username:       req.Username,
+		HashedPassword: hashedPassword,
+		FullName:       req.FullName,
+		Email:          req.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("created user %s\n", user.Username)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+	return nil
+}
+
+// Moves an account to the trash like DELETE /v1/me: it can't log in anymore
+// and is purged after TRASH_RETENTION unless restored
+func disableUser(ctx context.Context, config util.Config, args []string) error {
+	flags := flag.NewFlagSet("user disable", flag.ContinueOnError)
+	username := flags.String("username", "", "user to disable")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = store.DeleteUserTx(ctx, *username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("user %s not found", *username)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("disabled user %s\n", *username)
+	return nil
+}
+
+// Sets a new password, printing it when it is generated
+func resetUserPassword(ctx context.Context, config util.Config, args []string) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	username := flags.String("username", "", "user whose password is reset")
+	newPassword := flags.String("password", "", "new password, generated when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+	if err := validateFlags(passwordFlags{Password: *newPassword}); err != nil {
+		return err
+	}
+
+	password, generated, err := passwordOrGenerated(*newPassword)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	store, conn, err := openStore(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = store.ResetPasswordTx(ctx, db.ResetPasswordParams{
+		Username:       *username,
+		HashedPassword: hashedPassword,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("user %s not found", *username)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("reset the password of %s\n", *username)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+	return nil
+}
+
+func passwordOrGenerated(password string) (string, bool, error) {
+	if password != "" {
+		return password, false, nil
+	}
+	password, err := util.GeneratePassword()
+	return password, true, err
+}
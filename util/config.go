@@ -9,11 +9,41 @@ import (
 // Config stores the configuration of the app
 // The values are read by viper from a congif file or enviroment variables
 type Config struct {
+	Environment string `mapstructure:"ENVIRONMENT"`
+	LogLevel string `mapstructure:"LOG_LEVEL"`
 	DBDriver string `mapstructure:"DB_DRIVER"`
 	DBSource string `mapstructure:"DB_SOURCE"`
 	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	GRPCServerAddress string `mapstructure:"GRPC_SERVER_ADDRESS"`
+	GatewayServerAddress string `mapstructure:"GATEWAY_SERVER_ADDRESS"`
+	MetricsServerAddress string `mapstructure:"METRICS_SERVER_ADDRESS"`
+	FrontendURL string `mapstructure:"FRONTEND_URL"`
 	TokenSymmetricKey string `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	TrashRetention time.Duration `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	GraphQLMaxDepth int `mapstructure:"GRAPHQL_MAX_DEPTH"`
+	GraphQLMaxComplexity int `mapstructure:"GRAPHQL_MAX_COMPLEXITY"`
+	WebhookPollInterval time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	TotalsReconcileInterval time.Duration `mapstructure:"TOTALS_RECONCILE_INTERVAL"`
+	TotalsReconcileFix bool `mapstructure:"TOTALS_RECONCILE_FIX"`
+	RateLimitStore string `mapstructure:"RATE_LIMIT_STORE"`
+	RateLimitRequests int `mapstructure:"RATE_LIMIT_REQUESTS"`
+	RateLimitPeriod time.Duration `mapstructure:"RATE_LIMIT_PERIOD"`
+	AuthRateLimitRequests int `mapstructure:"AUTH_RATE_LIMIT_REQUESTS"`
+	AuthRateLimitPeriod time.Duration `mapstructure:"AUTH_RATE_LIMIT_PERIOD"`
+	CORSAllowedOrigins []string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods []string `mapstructure:"CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders []string `mapstructure:"CORS_ALLOWED_HEADERS"`
+	CORSMaxAge time.Duration `mapstructure:"CORS_MAX_AGE"`
+	CORSAllowCredentials bool `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	TracingOTLPEndpoint string `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool `mapstructure:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	MigrateOnStart bool `mapstructure:"MIGRATE_ON_START"`
 }
 
 // LoadConfig read configuration from file
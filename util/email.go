@@ -5,10 +5,10 @@ import (
 	"crypto/tls"
 	"fmt"
 	"html/template"
-	"log"
 	"os"
 	"path/filepath"
 
+	"github.com/LeandroEstevez/budgetAppAPI/metrics"
 	"github.com/k3a/html2text"
 	"gopkg.in/gomail.v2"
 )
@@ -33,8 +33,6 @@ func ParseTemplateDir(dir string) (*template.Template, error) {
 		return nil
 	})
 
-	fmt.Println("Am parsing templates...")
-
 	if err != nil {
 		return nil, err
 	}
@@ -57,17 +55,11 @@ func SendEmail(data *EmailData, templateName string) error {
 
 	template, err := ParseTemplateDir("templates")
 	if err != nil {
-		log.Fatal("Could not parse template", err)
+		return fmt.Errorf("cannot parse email templates: %w", err)
 	}
 
-	fmt.Println(to)
-	fmt.Println("Parsed the template")
-
 	template = template.Lookup(templateName)
 	template.Execute(&body, &data)
-	fmt.Println(template.Name())
-
-	fmt.Println("Got the template")
 
 	m := gomail.NewMessage()
 
@@ -84,6 +76,6 @@ func SendEmail(data *EmailData, templateName string) error {
 	if err := d.DialAndSend(m); err != nil {
 		return err
 	}
-	fmt.Println("Sent the email")
+	metrics.EmailsSent.WithLabelValues(templateName).Inc()
 	return nil
 }
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Environments the app runs in
+const (
+	DevelopmentEnvironment = "development"
+	ProductionEnvironment  = "production"
+)
+
+// NewLogger returns the logger of the app: JSON lines in production and
+// colored console output elsewhere. level defaults to info.
+func NewLogger(environment string, level string) (zerolog.Logger, error) {
+	logLevel := zerolog.InfoLevel
+	if level != "" {
+		var err error
+		logLevel, err = zerolog.ParseLevel(level)
+		if err != nil {
+			return zerolog.Nop(), err
+		}
+	}
+
+	var output io.Writer = os.Stderr
+	if environment != ProductionEnvironment {
+		output = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}
+	}
+
+	return zerolog.New(output).Level(logLevel).With().Timestamp().Logger(), nil
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 
 	"golang.org/x/crypto/bcrypt"
@@ -16,4 +18,13 @@ func HashPassword(password string) (string, error) {
 
 func CheckPassword(password string, hashedPassword string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
\ No newline at end of file
+}
+
+// GeneratePassword returns a random password of 16 URL safe characters
+func GeneratePassword() (string, error) {
+	password := make([]byte, 12)
+	if _, err := rand.Read(password); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(password), nil
+}
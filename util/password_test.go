@@ -25,4 +25,13 @@ func TestPassword(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
-}
\ No newline at end of file
+}
+func TestGeneratePassword(t *testing.T) {
+	password1, err := GeneratePassword()
+	require.NoError(t, err)
+	require.Len(t, password1, 16)
+
+	password2, err := GeneratePassword()
+	require.NoError(t, err)
+	require.NotEqual(t, password1, password2)
+}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// How often the in-process limiter forgets idle clients
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit allows Requests per Period, all of which can be spent in a burst
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled returns false for the zero RateLimit, which allows every request
+func (limit RateLimit) Enabled() bool {
+	return limit.Requests > 0 && limit.Period > 0
+}
+
+// TokenBucket holds the requests a client has left. Tokens are added back
+// continuously at Requests/Period, up to Requests.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// time until the next token, zero when Allowed
+	RetryAfter time.Duration
+	// time until the bucket is full again
+	Reset time.Duration
+}
+
+// NewBucket returns a full bucket
+func (limit RateLimit) NewBucket(now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(limit.Requests), UpdatedAt: now}
+}
+
+// Take refills bucket up to now and takes a token from it if there is one.
+// Rejected requests don't use up tokens.
+func (limit RateLimit) Take(bucket TokenBucket, now time.Time) (TokenBucket, RateLimitResult) {
+	perToken := limit.Period / time.Duration(limit.Requests)
+
+	elapsed := now.Sub(bucket.UpdatedAt)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens := math.Min(float64(limit.Requests), bucket.Tokens+float64(elapsed)/float64(perToken))
+
+	result := RateLimitResult{Limit: limit.Requests}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	result.Remaining = int(tokens)
+	result.Reset = time.Duration((float64(limit.Requests) - tokens) * float64(perToken))
+
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, result
+}
+
+// RateLimiter takes tokens from the bucket of each client. The HTTP and gRPC
+// servers share one, so a quota holds whichever API a client calls.
+type RateLimiter interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// MemoryRateLimiter keeps the buckets in process, so each replica counts on its own
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]TokenBucket
+	maxPeriod time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets: map[string]TokenBucket{},
+		now:     time.Now,
+	}
+}
+
+func (limiter *MemoryRateLimiter) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := limiter.now()
+	if limit.Period > limiter.maxPeriod {
+		limiter.maxPeriod = limit.Period
+	}
+	if now.Sub(limiter.lastSweep) >= rateLimitSweepInterval {
+		limiter.sweep(now)
+	}
+
+	bucket, ok := limiter.buckets[key]
+	if !ok {
+		bucket = limit.NewBucket(now)
+	}
+
+	bucket, result := limit.Take(bucket, now)
+	limiter.buckets[key] = bucket
+	return result, nil
+}
+
+// Forgets the buckets that have been idle long enough to be full again
+func (limiter *MemoryRateLimiter) sweep(now time.Time) {
+	for key, bucket := range limiter.buckets {
+		if now.Sub(bucket.UpdatedAt) > limiter.maxPeriod {
+			delete(limiter.buckets, key)
+		}
+	}
+	limiter.lastSweep = now
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitTake(t *testing.T) {
+	limit := RateLimit{Requests: 3, Period: 3 * time.Second}
+	now := time.Now()
+	bucket := limit.NewBucket(now)
+
+	var result RateLimitResult
+	for i := 2; i >= 0; i-- {
+		bucket, result = limit.Take(bucket, now)
+		require.True(t, result.Allowed)
+		require.Equal(t, i, result.Remaining)
+	}
+	require.Equal(t, 3*time.Second, result.Reset)
+
+	// the burst is spent, a token comes back every second
+	bucket, result = limit.Take(bucket, now.Add(500*time.Millisecond))
+	require.False(t, result.Allowed)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter)
+	require.Equal(t, 2500*time.Millisecond, result.Reset)
+
+	bucket, result = limit.Take(bucket, now.Add(time.Second))
+	require.True(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+
+	// an idle bucket refills up to the limit
+	_, result = limit.Take(bucket, now.Add(time.Hour))
+	require.True(t, result.Allowed)
+	require.Equal(t, 2, result.Remaining)
+}
+
+func TestRateLimitEnabled(t *testing.T) {
+	require.False(t, RateLimit{}.Enabled())
+	require.False(t, RateLimit{Requests: 5}.Enabled())
+	require.True(t, RateLimit{Requests: 5, Period: time.Minute}.Enabled())
+}
+
+func TestMemoryRateLimiterSweep(t *testing.T) {
+	limiter := NewMemoryRateLimiter()
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	limit := RateLimit{Requests: 5, Period: time.Minute}
+	_, err := limiter.Take(context.Background(), "idle", limit)
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Minute)
+	_, err = limiter.Take(context.Background(), "active", limit)
+	require.NoError(t, err)
+
+	require.Len(t, limiter.buckets, 1)
+	require.Contains(t, limiter.buckets, "active")
+}
@@ -0,0 +1,25 @@
+package util
+
+// Roles a user can hold inside a shared household
+const (
+	OwnerRole  = "owner"
+	EditorRole = "editor"
+	ViewerRole = "viewer"
+)
+
+var roleRanks = map[string]int{
+	ViewerRole: 1,
+	EditorRole: 2,
+	OwnerRole:  3,
+}
+
+// IsSupportedRole returns true if the role is one of the household roles
+func IsSupportedRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole reports whether role grants at least the permissions of required
+func HasRole(role string, required string) bool {
+	return IsSupportedRole(role) && roleRanks[role] >= roleRanks[required]
+}
@@ -0,0 +1,175 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Ways an expense can be split between users
+const (
+	EqualSplit      = "equal"
+	ExactSplit      = "exact"
+	PercentageSplit = "percentage"
+)
+
+var ErrInvalidSplit = errors.New("shares don't add up to the expense amount")
+
+// IsSupportedSplitType returns true if the split type is supported
+func IsSupportedSplitType(splitType string) bool {
+	switch splitType {
+	case EqualSplit, ExactSplit, PercentageSplit:
+		return true
+	}
+	return false
+}
+
+// SplitAmount divides amount into one share per value.
+// Values are ignored for equal splits, are the share amounts themselves for exact
+// splits and are whole percentages for percentage splits. Any remainder left by
+// integer division goes to the first shares so the result always adds up to amount.
+func SplitAmount(amount int64, splitType string, values []int64) ([]int64, error) {
+	n := int64(len(values))
+	if n == 0 {
+		return nil, errors.New("at least one share is required")
+	}
+
+	shares := make([]int64, n)
+	var remainder int64
+
+	switch splitType {
+	case EqualSplit:
+		for i := range shares {
+			shares[i] = amount / n
+		}
+		remainder = amount % n
+	case ExactSplit:
+		var sum int64
+		for i, value := range values {
+			if value < 0 {
+				return nil, ErrInvalidSplit
+			}
+			shares[i] = value
+			sum += value
+		}
+		if sum != amount {
+			return nil, ErrInvalidSplit
+		}
+	case PercentageSplit:
+		var percent, sum int64
+		for i, value := range values {
+			if value < 0 {
+				return nil, ErrInvalidSplit
+			}
+			shares[i] = amount * value / 100
+			percent += value
+			sum += shares[i]
+		}
+		if percent != 100 {
+			return nil, ErrInvalidSplit
+		}
+		remainder = amount - sum
+	default:
+		return nil, fmt.Errorf("unsupported split type %s", splitType)
+	}
+
+	for i := int64(0); i < remainder; i++ {
+		shares[i%n]++
+	}
+
+	return shares, nil
+}
+
+// ResplitAmount divides a new amount among the shares of an existing split.
+// Equal splits stay equal, the others keep the proportions between the shares,
+// so percentages are kept and exact shares are scaled to the new amount.
+// Like SplitAmount, any remainder goes to the first shares.
+func ResplitAmount(amount int64, splitType string, shares []int64) ([]int64, error) {
+	var total int64
+	for _, share := range shares {
+		total += share
+	}
+	if splitType == EqualSplit || total == 0 {
+		return SplitAmount(amount, EqualSplit, shares)
+	}
+	if !IsSupportedSplitType(splitType) {
+		return nil, fmt.Errorf("unsupported split type %s", splitType)
+	}
+
+	n := int64(len(shares))
+	resplit := make([]int64, n)
+	var sum int64
+	for i, share := range shares {
+		resplit[i] = amount * share / total
+		sum += resplit[i]
+	}
+
+	for i := int64(0); i < amount-sum; i++ {
+		resplit[i%n]++
+	}
+
+	return resplit, nil
+}
+
+// Transfer is a suggested payment between two users
+type Transfer struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int64  `json:"amount"`
+}
+
+// MinimizeTransfers suggests a small set of transfers that settles every net balance.
+// A positive balance means the user is owed money, a negative one that the user owes it.
+// It repeatedly matches the largest creditor with the largest debtor.
+func MinimizeTransfers(balances map[string]int64) []Transfer {
+	type position struct {
+		username string
+		amount   int64
+	}
+
+	var creditors, debtors []position
+	for username, amount := range balances {
+		if amount > 0 {
+			creditors = append(creditors, position{username, amount})
+		} else if amount < 0 {
+			debtors = append(debtors, position{username, -amount})
+		}
+	}
+
+	byAmount := func(positions []position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if positions[i].amount == positions[j].amount {
+				return positions[i].username < positions[j].username
+			}
+			return positions[i].amount > positions[j].amount
+		}
+	}
+
+	transfers := []Transfer{}
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.Slice(creditors, byAmount(creditors))
+		sort.Slice(debtors, byAmount(debtors))
+
+		amount := creditors[0].amount
+		if debtors[0].amount < amount {
+			amount = debtors[0].amount
+		}
+
+		transfers = append(transfers, Transfer{
+			From:   debtors[0].username,
+			To:     creditors[0].username,
+			Amount: amount,
+		})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+
+	return transfers
+}
@@ -0,0 +1,135 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitAmount(t *testing.T) {
+	testCases := []struct {
+		name      string
+		amount    int64
+		splitType string
+		values    []int64
+		shares    []int64
+		err       error
+	}{
+		{
+			name:      "Equal",
+			amount:    100,
+			splitType: EqualSplit,
+			values:    []int64{0, 0, 0},
+			shares:    []int64{34, 33, 33},
+		},
+		{
+			name:      "Exact",
+			amount:    100,
+			splitType: ExactSplit,
+			values:    []int64{70, 30},
+			shares:    []int64{70, 30},
+		},
+		{
+			name:      "ExactDoesNotAddUp",
+			amount:    100,
+			splitType: ExactSplit,
+			values:    []int64{70, 20},
+			err:       ErrInvalidSplit,
+		},
+		{
+			name:      "Percentage",
+			amount:    101,
+			splitType: PercentageSplit,
+			values:    []int64{50, 25, 25},
+			shares:    []int64{51, 25, 25},
+		},
+		{
+			name:      "PercentageDoesNotAddUp",
+			amount:    100,
+			splitType: PercentageSplit,
+			values:    []int64{50, 40},
+			err:       ErrInvalidSplit,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := SplitAmount(tc.amount, tc.splitType, tc.values)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.shares, shares)
+		})
+	}
+}
+
+func TestResplitAmount(t *testing.T) {
+	testCases := []struct {
+		name      string
+		amount    int64
+		splitType string
+		shares    []int64
+		resplit   []int64
+	}{
+		{
+			name:      "Equal",
+			amount:    100,
+			splitType: EqualSplit,
+			shares:    []int64{20, 20, 20},
+			resplit:   []int64{34, 33, 33},
+		},
+		{
+			name:      "Exact",
+			amount:    200,
+			splitType: ExactSplit,
+			shares:    []int64{70, 30},
+			resplit:   []int64{140, 60},
+		},
+		{
+			name:      "Percentage",
+			amount:    101,
+			splitType: PercentageSplit,
+			shares:    []int64{100, 50, 50},
+			resplit:   []int64{51, 25, 25},
+		},
+		{
+			name:      "NothingShared",
+			amount:    10,
+			splitType: ExactSplit,
+			shares:    []int64{0, 0},
+			resplit:   []int64{5, 5},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			resplit, err := ResplitAmount(tc.amount, tc.splitType, tc.shares)
+			require.NoError(t, err)
+			require.Equal(t, tc.resplit, resplit)
+		})
+	}
+}
+
+func TestMinimizeTransfers(t *testing.T) {
+	balances := map[string]int64{
+		"alice": 60,
+		"bob":   -40,
+		"carol": -20,
+		"dave":  0,
+	}
+
+	transfers := MinimizeTransfers(balances)
+	require.Equal(t, []Transfer{
+		{From: "bob", To: "alice", Amount: 40},
+		{From: "carol", To: "alice", Amount: 20},
+	}, transfers)
+
+	require.Empty(t, MinimizeTransfers(map[string]int64{}))
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Events users can subscribe their webhooks to
+const (
+	EntryCreatedEvent   = "entry.created"
+	BudgetExceededEvent = "budget.exceeded"
+	BillDueEvent        = "bill.due"
+	// sent on request to check an endpoint, webhooks can't subscribe to it
+	WebhookTestEvent = "webhook.test"
+)
+
+// IsSupportedWebhookEvent returns true if webhooks can subscribe to the event
+func IsSupportedWebhookEvent(event string) bool {
+	switch event {
+	case EntryCreatedEvent, BudgetExceededEvent, BillDueEvent:
+		return true
+	}
+	return false
+}
+
+// NewWebhookSecret generates the secret a webhook's payloads are signed with
+func NewWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 of "timestamp.body".
+// Signing the timestamp lets receivers reject replayed deliveries.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsWebhookURL returns true if deliveries can be posted to the URL: it is http
+// or https and its host is not a loopback, private or link-local address.
+// Hostnames are resolved when connecting, see IsPublicAddress.
+func IsWebhookURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicAddress(ip)
+	}
+	return true
+}
+
+// IsPublicAddress returns false for the addresses webhooks must not reach:
+// loopback, private, link-local, multicast and unspecified ones
+func IsPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
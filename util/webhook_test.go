@@ -0,0 +1,55 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"type":"webhook.test"}`)
+
+	// computed with: printf '1670716800.{"type":"webhook.test"}' | openssl dgst -sha256 -hmac secret
+	signature := SignWebhookPayload("secret", 1670716800, body)
+	require.Equal(t, "af85af3ac80b260bd066f91d1fab83311330be44c36304660ebdca475bdc4615", signature)
+
+	require.NotEqual(t, signature, SignWebhookPayload("other secret", 1670716800, body))
+	require.NotEqual(t, signature, SignWebhookPayload("secret", 1670716801, body))
+}
+
+func TestNewWebhookSecret(t *testing.T) {
+	secret1, err := NewWebhookSecret()
+	require.NoError(t, err)
+	require.Len(t, secret1, 64)
+
+	secret2, err := NewWebhookSecret()
+	require.NoError(t, err)
+	require.NotEqual(t, secret1, secret2)
+}
+
+func TestIsWebhookURL(t *testing.T) {
+	testCases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://example.com/hooks", true},
+		{"http://93.184.216.34:8080/hooks", true},
+		{"ftp://example.com/hooks", false},
+		{"file:///etc/passwd", false},
+		{"https:///hooks", false},
+		{"http://localhost:5432", false},
+		{"http://api.localhost/hooks", false},
+		{"http://127.0.0.1/hooks", false},
+		{"http://10.0.0.8/hooks", false},
+		{"http://192.168.1.1/hooks", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://0.0.0.0/hooks", false},
+		{"http://[::1]/hooks", false},
+		{"http://[fd00::1]/hooks", false},
+		{"http://[::ffff:127.0.0.1]/hooks", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.valid, IsWebhookURL(tc.url), tc.url)
+	}
+}
@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/rs/zerolog/log"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// Deliveries sent in each poll
+	batchSize = 20
+	// Attempts before a delivery is marked as failed
+	maxAttempts = 8
+	// Delay before the first retry, doubled after every attempt
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 6 * time.Hour
+	// How long a claimed delivery is hidden from other workers
+	leaseDuration = time.Minute
+	// How often the entries are checked for bills coming due
+	billDueInterval = time.Hour
+)
+
+var (
+	errAddressNotAllowed = errors.New("webhook endpoints must have a public address")
+	errRedirect          = errors.New("webhook endpoints must not redirect")
+)
+
+// Body is the JSON posted to the webhook endpoints
+type Body struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Worker sends the queued deliveries to the webhook endpoints
+type Worker struct {
+	store  db.Store
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWorker(store db.Store) *Worker {
+	return &Worker{
+		store:  store,
+		client: newClient(),
+		now:    time.Now,
+	}
+}
+
+// Creates the client deliveries are posted with. Users choose the URLs, so
+// the client only connects to public addresses and doesn't follow redirects.
+// The address is checked once resolved, right before connecting, so a host
+// can't pass the check and then resolve to an internal address.
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !util.IsPublicAddress(ip) {
+				return errAddressNotAllowed
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// a proxy would connect on our behalf, past the address check
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			return errRedirect
+		},
+	}
+}
+
+// Sends the pending deliveries every interval and queues the bills coming due
+// every hour, until ctx is done
+func (worker *Worker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastBillCheck time.Time
+	for {
+		if worker.now().Sub(lastBillCheck) >= billDueInterval {
+			lastBillCheck = worker.now()
+			err := worker.QueueBillsDue(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("cannot queue bill due webhooks")
+			}
+		}
+
+		_, err := worker.DeliverPending(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("cannot deliver webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Queues a bill due event for the entries due today or tomorrow. Each entry
+// is only notified once per due date, however often this runs.
+func (worker *Worker) QueueBillsDue(ctx context.Context) error {
+	year, month, day := worker.now().UTC().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	_, err := worker.store.EnqueueBillDue(ctx, db.EnqueueBillDueParams{
+		DueAfter:  today,
+		DueBefore: today.AddDate(0, 0, 2),
+	})
+	return err
+}
+
+// Claims a batch of due deliveries and sends them. Returns how many were claimed.
+func (worker *Worker) DeliverPending(ctx context.Context) (int, error) {
+	deliveries, err := worker.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseUntil:    worker.now().Add(leaseDuration),
+		MaxDeliveries: batchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		err = worker.deliver(ctx, delivery)
+		if err != nil {
+			log.Error().Err(err).Int64("delivery_id", delivery.ID).Msg("cannot record webhook delivery")
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// Sends a claimed delivery and records the outcome
+func (worker *Worker) deliver(ctx context.Context, delivery db.WebhookDelivery) error {
+	webhook, err := worker.store.GetWebhookForDelivery(ctx, delivery.WebhookID)
+	if err == sql.ErrNoRows {
+		// the webhook was deleted along with its deliveries
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	statusCode, err := worker.send(ctx, webhook, delivery)
+	if err == nil {
+		return worker.store.CompleteWebhookDelivery(ctx, db.CompleteWebhookDeliveryParams{
+			ID:             delivery.ID,
+			LastStatusCode: int32(statusCode),
+		})
+	}
+
+	arg := db.FailWebhookDeliveryParams{
+		ID:             delivery.ID,
+		Status:         "pending",
+		LastStatusCode: int32(statusCode),
+		LastError:      err.Error(),
+		NextAttemptAt:  worker.now().Add(retryDelay(delivery.Attempts)),
+	}
+	if delivery.Attempts >= maxAttempts {
+		arg.Status = "failed"
+	}
+	return worker.store.FailWebhookDelivery(ctx, arg)
+}
+
+// Posts the signed delivery to the webhook. Returns the status code of the
+// response, 0 when none was received.
+func (worker *Worker) send(ctx context.Context, webhook db.Webhook, delivery db.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(Body{
+		ID:        delivery.ID,
+		Type:      delivery.EventType,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := worker.now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, "sha256="+util.SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	response, err := worker.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// the body isn't kept, users could otherwise read what an endpoint returns
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("endpoint responded %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
+
+// Returns how long to wait before the next attempt of a delivery
+func retryDelay(attempts int32) time.Duration {
+	delay := baseRetryDelay
+	for i := int32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
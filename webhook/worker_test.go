@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/LeandroEstevez/budgetAppAPI/db/mock"
+	db "github.com/LeandroEstevez/budgetAppAPI/db/sqlc"
+	"github.com/LeandroEstevez/budgetAppAPI/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhook(url string) db.Webhook {
+	return db.Webhook{
+		ID:         int32(util.RandomInt(1, 1000)),
+		Owner:      util.RandomString(6),
+		Url:        url,
+		Secret:     util.RandomString(32),
+		EventTypes: []string{util.EntryCreatedEvent},
+	}
+}
+
+func randomDelivery(webhook db.Webhook, attempts int32) db.WebhookDelivery {
+	return db.WebhookDelivery{
+		ID:        util.RandomInt(1, 1000),
+		WebhookID: webhook.ID,
+		EventType: util.EntryCreatedEvent,
+		DedupeKey: "entry:1",
+		Payload:   json.RawMessage(`{"entry":{"id":1}}`),
+		Status:    "pending",
+		Attempts:  attempts,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestDeliverPending(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	testCases := []struct {
+		name       string
+		statusCode int
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore, delivery db.WebhookDelivery)
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusNoContent,
+			attempts:   1,
+			buildStubs: func(store *mockdb.MockStore, delivery db.WebhookDelivery) {
+				store.EXPECT().
+					CompleteWebhookDelivery(gomock.Any(), gomock.Eq(db.CompleteWebhookDeliveryParams{
+						ID:             delivery.ID,
+						LastStatusCode: http.StatusNoContent,
+					})).
+					Times(1)
+				store.EXPECT().FailWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+			},
+		},
+		{
+			name:       "Retry",
+			statusCode: http.StatusInternalServerError,
+			attempts:   3,
+			buildStubs: func(store *mockdb.MockStore, delivery db.WebhookDelivery) {
+				store.EXPECT().CompleteWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FailWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					Do(func(ctx context.Context, arg db.FailWebhookDeliveryParams) {
+						require.Equal(t, delivery.ID, arg.ID)
+						require.Equal(t, "pending", arg.Status)
+						require.Equal(t, int32(http.StatusInternalServerError), arg.LastStatusCode)
+						require.Contains(t, arg.LastError, "500")
+						require.NotContains(t, arg.LastError, "internal response")
+						require.Equal(t, now.Add(2*time.Minute), arg.NextAttemptAt)
+					})
+			},
+		},
+		{
+			name:       "GiveUp",
+			statusCode: http.StatusGone,
+			attempts:   maxAttempts,
+			buildStubs: func(store *mockdb.MockStore, delivery db.WebhookDelivery) {
+				store.EXPECT().CompleteWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					FailWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(1).
+					Do(func(ctx context.Context, arg db.FailWebhookDeliveryParams) {
+						require.Equal(t, "failed", arg.Status)
+						require.Equal(t, int32(http.StatusGone), arg.LastStatusCode)
+					})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var webhook db.Webhook
+			endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+				require.NoError(t, err)
+				require.Equal(t, now.Unix(), timestamp)
+				require.Equal(t, "sha256="+util.SignWebhookPayload(webhook.Secret, timestamp, body), r.Header.Get(SignatureHeader))
+				require.Equal(t, util.EntryCreatedEvent, r.Header.Get(EventHeader))
+
+				var payload Body
+				require.NoError(t, json.Unmarshal(body, &payload))
+				require.Equal(t, util.EntryCreatedEvent, payload.Type)
+				require.JSONEq(t, `{"entry":{"id":1}}`, string(payload.Data))
+
+				w.WriteHeader(tc.statusCode)
+				w.Write([]byte("internal response"))
+			}))
+			defer endpoint.Close()
+
+			webhook = randomWebhook(endpoint.URL)
+			delivery := randomDelivery(webhook, tc.attempts)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimWebhookDeliveries(gomock.Any(), gomock.Eq(db.ClaimWebhookDeliveriesParams{
+					LeaseUntil:    now.Add(leaseDuration),
+					MaxDeliveries: batchSize,
+				})).
+				Times(1).
+				Return([]db.WebhookDelivery{delivery}, nil)
+			store.EXPECT().
+				GetWebhookForDelivery(gomock.Any(), gomock.Eq(webhook.ID)).
+				Times(1).
+				Return(webhook, nil)
+			tc.buildStubs(store, delivery)
+
+			worker := NewWorker(store)
+			worker.now = func() time.Time { return now }
+			// the test endpoint listens on loopback, which the worker's client refuses
+			worker.client = endpoint.Client()
+
+			n, err := worker.DeliverPending(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, n)
+		})
+	}
+}
+
+func TestClientRefusesInternalAddresses(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the client reached a loopback address")
+	}))
+	defer endpoint.Close()
+
+	client := newClient()
+
+	_, err := client.Post(endpoint.URL, "application/json", nil)
+	require.ErrorIs(t, err, errAddressNotAllowed)
+
+	// hostnames are checked once resolved
+	_, err = client.Post(strings.Replace(endpoint.URL, "127.0.0.1", "localhost", 1), "application/json", nil)
+	require.ErrorIs(t, err, errAddressNotAllowed)
+
+	require.ErrorIs(t, client.CheckRedirect(nil, nil), errRedirect)
+}
+
+func TestQueueBillsDue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		EnqueueBillDue(gomock.Any(), gomock.Eq(db.EnqueueBillDueParams{
+			DueAfter:  time.Date(2023, 3, 31, 0, 0, 0, 0, time.UTC),
+			DueBefore: time.Date(2023, 4, 2, 0, 0, 0, 0, time.UTC),
+		})).
+		Times(1)
+
+	worker := NewWorker(store)
+	worker.now = func() time.Time { return time.Date(2023, 3, 31, 18, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, worker.QueueBillsDue(context.Background()))
+}
+
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, 30*time.Second, retryDelay(1))
+	require.Equal(t, time.Minute, retryDelay(2))
+	require.Equal(t, 4*time.Minute, retryDelay(4))
+	require.Equal(t, maxRetryDelay, retryDelay(20))
+}